@@ -31,6 +31,16 @@ import (
 )
 
 var selectedProfile string
+var selectedObjStoreAPIVersion string
+var offlineMode bool
+
+// offlineContext is the placeholder context used in offline mode when the selected context doesn't exist
+var offlineContext = Context{
+	Name:       "offline",
+	AuthMethod: AuthMethodNone,
+	Server:     "fso.example.com",
+	Tenant:     "00000000-0000-0000-0000-000000000000",
+}
 
 // Package registration function for the config root command
 func NewSubCmd() *cobra.Command {
@@ -57,7 +67,9 @@ func NewSubCmd() *cobra.Command {
 	cmd.AddCommand(newCmdConfigGet())
 	cmd.AddCommand(newCmdConfigSet())
 	cmd.AddCommand(newCmdConfigUse())
+	cmd.AddCommand(newCmdConfigRename())
 	cmd.AddCommand(newCmdConfigList())
+	cmd.AddCommand(newCmdConfigDebug())
 
 	return cmd
 }
@@ -72,9 +84,6 @@ func GetCurrentContext() *Context {
 
 	// read config file
 	cfg := getConfig()
-	if len(cfg.Contexts) == 0 {
-		return nil
-	}
 
 	// locate & return the named context
 	for _, c := range cfg.Contexts {
@@ -83,6 +92,10 @@ func GetCurrentContext() *Context {
 		}
 	}
 
+	if offlineMode {
+		c := offlineContext
+		return &c
+	}
 	return nil
 }
 
@@ -262,3 +275,32 @@ func GetCurrentProfileName() string {
 
 	return profile
 }
+
+// SetOfflineMode enables or disables offline mode. In offline mode, GetCurrentContext returns a
+// placeholder context if the selected context doesn't exist, so that requests can be constructed
+// (e.g., to be explained) without a configured context.
+// This function should not be used outside of the fsoc root pre-command.
+func SetOfflineMode(enabled bool) {
+	offlineMode = enabled
+}
+
+// SetSelectedObjStoreAPIVersion sets the object store API version that should be used instead
+// of the context's value. This function should not be used outside of the fsoc root pre-command.
+func SetSelectedObjStoreAPIVersion(version string) {
+	selectedObjStoreAPIVersion = version
+}
+
+// GetObjStoreBasePath returns the base path of the object store API (e.g., "objstore/v1beta"),
+// using the API version from the command line, the current context or the default, in this order.
+func GetObjStoreBasePath() string {
+	version := selectedObjStoreAPIVersion
+	if version == "" {
+		if ctx := GetCurrentContext(); ctx != nil {
+			version = ctx.ObjStoreAPIVersion
+		}
+	}
+	if version == "" {
+		version = DefaultObjStoreAPIVersion
+	}
+	return "objstore/" + version
+}
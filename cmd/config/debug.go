@@ -0,0 +1,199 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cisco-open/fsoc/output"
+)
+
+// Sources of configuration values, as displayed by `config debug`
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceFile    = "file"
+	sourceDefault = "default"
+	sourceOffline = "offline"
+)
+
+// currentContextEnvVar is the environment variable that overrides the config file's
+// current context (viper maps it to the current_context key)
+const currentContextEnvVar = "CURRENT_CONTEXT"
+
+// debugValue is a resolved configuration value and where it came from
+type debugValue struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// debugInfo is the effective configuration, as displayed by `config debug`
+type debugInfo struct {
+	ConfigFile       debugValue            `json:"config_file"`
+	ConfigFileExists bool                  `json:"config_file_exists"`
+	Profile          debugValue            `json:"profile"`
+	ContextExists    bool                  `json:"context_exists"`
+	Context          map[string]debugValue `json:"context"`
+}
+
+func newCmdConfigDebug() *cobra.Command {
+
+	var cmd = &cobra.Command{
+		Use:   "debug",
+		Short: "Display the effective configuration and the source of each value",
+		Long: `Display the fully resolved configuration as JSON, after applying the config file,
+environment variables and command line flags, together with the source of each value
+(file, env, flag or default). Secrets are masked. No requests are sent to the platform.
+
+This is useful to find out why fsoc is using an unexpected profile, tenant or server.`,
+		Example: `  fsoc config debug
+  fsoc config debug --profile prod`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{AnnotationForConfigBypass: ""},
+		Run:         configDebug,
+	}
+
+	return cmd
+}
+
+func configDebug(cmd *cobra.Command, args []string) {
+	info := debugInfo{
+		ConfigFile: configFileDebugValue(cmd),
+		Profile:    profileDebugValue(),
+	}
+
+	// check the config file, without requiring it to exist
+	if info.ConfigFile.Value != "" {
+		_, err := os.Stat(info.ConfigFile.Value)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Warnf("Failed to access config file %q: %v", info.ConfigFile.Value, err)
+		}
+		info.ConfigFileExists = err == nil
+	}
+
+	ctx := GetCurrentContext()
+	info.ContextExists = ctx != nil && ctx.Name == info.Profile.Value
+	contextSource := sourceFile
+	if ctx != nil && !info.ContextExists {
+		contextSource = sourceOffline // placeholder context, see SetOfflineMode
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	info.Context = contextDebugValues(ctx, contextSource, outputFormat, cmd.Flags().Changed("output"))
+
+	if err := output.PrintJson(cmd, info); err != nil {
+		log.Fatalf("Failed to display the configuration: %v", err)
+	}
+}
+
+// configFileDebugValue returns the config file path that is used (or would be created)
+func configFileDebugValue(cmd *cobra.Command) debugValue {
+	source := sourceDefault
+	if cmd.Flags().Changed("config") {
+		source = sourceFlag
+	}
+
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		home, _ := os.UserHomeDir()
+		path = strings.Replace(defaultConfigFile, "~", home, 1)
+	}
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	return debugValue{Value: path, Source: source}
+}
+
+// profileDebugValue returns the selected profile name, using the same order as GetCurrentProfileName
+func profileDebugValue() debugValue {
+	if selectedProfile != "" {
+		return debugValue{Value: selectedProfile, Source: sourceFlag}
+	}
+	cfg := getConfig()
+	if cfg.CurrentContext != "" {
+		source := sourceFile
+		if value, found := os.LookupEnv(currentContextEnvVar); found && value != "" {
+			source = sourceEnv
+		}
+		return debugValue{Value: cfg.CurrentContext, Source: source}
+	}
+	return debugValue{Value: defaultContext, Source: sourceDefault}
+}
+
+// contextDebugValues returns the effective values of the context's settings, with secrets masked.
+// Settings without a value are omitted, except those that have a default.
+func contextDebugValues(ctx *Context, source string, outputFormat string, outputChanged bool) map[string]debugValue {
+	values := map[string]debugValue{}
+	var c Context
+	if ctx != nil {
+		c = *ctx
+	}
+
+	// "upgrade" config schema if needed
+	if c.SecretFile == "" && c.CsvFile != "" {
+		c.SecretFile = c.CsvFile
+	}
+
+	addIfPresent := func(key, value string) {
+		if value != "" {
+			values[key] = debugValue{Value: value, Source: source}
+		}
+	}
+	addIfPresent("auth_method", c.AuthMethod)
+	addIfPresent("server", c.Server)
+	addIfPresent("tenant", c.Tenant)
+	addIfPresent("user", c.User)
+	addIfPresent("secret_file", c.SecretFile)
+	addIfPresent("audit_log", c.AuditLog)
+	addIfPresent("field_manager", c.FieldManager)
+	if c.Token != "" {
+		values["token"] = debugValue{Value: "(present)", Source: source}
+	}
+	if c.RefreshToken != "" {
+		values["refresh_token"] = debugValue{Value: "(present)", Source: source}
+	}
+
+	// settings that can be overridden on the command line
+	switch {
+	case selectedObjStoreAPIVersion != "":
+		values["objstore_api_version"] = debugValue{Value: selectedObjStoreAPIVersion, Source: sourceFlag}
+	case c.ObjStoreAPIVersion != "":
+		values["objstore_api_version"] = debugValue{Value: c.ObjStoreAPIVersion, Source: source}
+	default:
+		values["objstore_api_version"] = debugValue{Value: DefaultObjStoreAPIVersion, Source: sourceDefault}
+	}
+
+	// note: the root command sets --output from the context's default output, if not specified
+	switch {
+	case c.DefaultOutput != "" && outputFormat == c.DefaultOutput:
+		values["output"] = debugValue{Value: c.DefaultOutput, Source: source}
+	case outputChanged:
+		values["output"] = debugValue{Value: outputFormat, Source: sourceFlag}
+	default:
+		if outputFormat == "" {
+			outputFormat = "auto"
+		}
+		values["output"] = debugValue{Value: outputFormat, Source: sourceDefault}
+	}
+
+	return values
+}
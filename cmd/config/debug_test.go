@@ -0,0 +1,81 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runConfigDebug(t *testing.T, args ...string) debugInfo {
+	cmd := &cobra.Command{Run: configDebug}
+	cmd.Flags().String("config", "", "")
+	cmd.Flags().String("output", "auto", "")
+	require.NoError(t, cmd.Flags().Parse(args))
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	configDebug(cmd, nil)
+
+	var info debugInfo
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &info))
+	return info
+}
+
+func TestConfigDebug(t *testing.T) {
+	viper.Set("contexts", []map[string]any{
+		{"name": "test", "server": "test.example.com", "tenant": "tenant-1", "token": "secret", "default_output": "json"},
+		{"name": "other", "server": "other.example.com", "tenant": "tenant-2"},
+	})
+	viper.Set("current_context", "test")
+	t.Cleanup(func() {
+		viper.Set("contexts", nil)
+		viper.Set("current_context", nil)
+		selectedProfile, selectedObjStoreAPIVersion = "", ""
+	})
+
+	// values from the file, secrets masked
+	info := runConfigDebug(t, "--output", "json") // as set by the root command from the default output
+	assert.Equal(t, debugValue{Value: "test", Source: sourceFile}, info.Profile)
+	assert.True(t, info.ContextExists)
+	assert.Equal(t, debugValue{Value: "tenant-1", Source: sourceFile}, info.Context["tenant"])
+	assert.Equal(t, debugValue{Value: "(present)", Source: sourceFile}, info.Context["token"])
+	assert.Equal(t, debugValue{Value: "json", Source: sourceFile}, info.Context["output"])
+	assert.Equal(t, debugValue{Value: DefaultObjStoreAPIVersion, Source: sourceDefault}, info.Context["objstore_api_version"])
+	assert.NotContains(t, info.Context, "refresh_token")
+
+	// values from the command line
+	selectedProfile, selectedObjStoreAPIVersion = "other", "v2"
+	info = runConfigDebug(t, "--output", "yaml", "--config", "/tmp/fsoc-test")
+	assert.Equal(t, debugValue{Value: "other", Source: sourceFlag}, info.Profile)
+	assert.Equal(t, sourceFlag, info.ConfigFile.Source)
+	assert.Equal(t, debugValue{Value: "tenant-2", Source: sourceFile}, info.Context["tenant"])
+	assert.Equal(t, debugValue{Value: "yaml", Source: sourceFlag}, info.Context["output"])
+	assert.Equal(t, debugValue{Value: "v2", Source: sourceFlag}, info.Context["objstore_api_version"])
+	assert.NotContains(t, info.Context, "token")
+
+	// missing profile
+	selectedProfile = "missing"
+	info = runConfigDebug(t)
+	assert.False(t, info.ContextExists)
+	assert.Equal(t, debugValue{Value: "auto", Source: sourceDefault}, info.Context["output"])
+	assert.NotContains(t, info.Context, "server")
+}
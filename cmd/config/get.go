@@ -81,6 +81,10 @@ func configGetContext(cmd *cobra.Command, args []string) error {
 	appendIfPresent("Token", ctx.Token)
 	appendIfPresent("Refresh Token", ctx.RefreshToken)
 	appendIfPresent("Secret File", ctx.SecretFile)
+	appendIfPresent("Objstore API Version", ctx.ObjStoreAPIVersion)
+	appendIfPresent("Default Output", ctx.DefaultOutput)
+	appendIfPresent("Audit Log", ctx.AuditLog)
+	appendIfPresent("Field Manager", ctx.FieldManager)
 
 	output.PrintCmdOutputCustom(cmd, ctx, &output.Table{
 		Headers: headers,
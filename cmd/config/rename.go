@@ -0,0 +1,78 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+)
+
+func newCmdConfigRename() *cobra.Command {
+
+	var cmd = &cobra.Command{
+		Use:   "rename OLD_NAME NEW_NAME",
+		Short: "Rename a context in an fsoc config file",
+		Long: `Rename a context in an fsoc config file, keeping all of its settings.
+If the renamed context is the current context, it remains the current context under its new name.`,
+		Example: `  fsoc config rename default prod`,
+		Args:    cobra.ExactArgs(2),
+		Run:     configRenameContext,
+	}
+
+	return cmd
+}
+
+func configRenameContext(cmd *cobra.Command, args []string) {
+	oldName, newName := args[0], args[1]
+
+	update, err := renameContext(getConfig(), oldName, newName)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	updateConfigFile(update)
+	output.PrintCmdStatus(cmd, fmt.Sprintf("Context %q renamed to %q\n", oldName, newName))
+}
+
+// renameContext returns the config file values to update in order to rename a context,
+// including the current context if it is the renamed one
+func renameContext(cfg configFileContents, oldName string, newName string) (map[string]interface{}, error) {
+	if newName == "" {
+		return nil, fmt.Errorf("the new context name cannot be empty")
+	}
+
+	found := -1
+	for idx, c := range cfg.Contexts {
+		if c.Name == newName {
+			return nil, fmt.Errorf("a context with the name %q already exists", newName)
+		}
+		if c.Name == oldName {
+			found = idx
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("no context exists with the name: %q", oldName)
+	}
+
+	cfg.Contexts[found].Name = newName
+	update := map[string]interface{}{"contexts": cfg.Contexts}
+	if cfg.CurrentContext == oldName {
+		update["current_context"] = newName
+	}
+	return update, nil
+}
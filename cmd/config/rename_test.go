@@ -0,0 +1,49 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameContext(t *testing.T) {
+	newConfig := func() configFileContents {
+		return configFileContents{
+			Contexts:       []Context{{Name: "default", Tenant: "tenant-1"}, {Name: "dev"}},
+			CurrentContext: "default",
+		}
+	}
+
+	// renaming the current context updates the current context pointer
+	update, err := renameContext(newConfig(), "default", "prod")
+	require.NoError(t, err)
+	assert.Equal(t, []Context{{Name: "prod", Tenant: "tenant-1"}, {Name: "dev"}}, update["contexts"])
+	assert.Equal(t, "prod", update["current_context"])
+
+	// renaming another context leaves it alone
+	update, err = renameContext(newConfig(), "dev", "staging")
+	require.NoError(t, err)
+	assert.Equal(t, []Context{{Name: "default", Tenant: "tenant-1"}, {Name: "staging"}}, update["contexts"])
+	assert.NotContains(t, update, "current_context")
+
+	// the old name must exist and the new name must not
+	_, err = renameContext(newConfig(), "missing", "prod")
+	assert.ErrorContains(t, err, `"missing"`)
+	_, err = renameContext(newConfig(), "default", "dev")
+	assert.ErrorContains(t, err, "already exists")
+}
@@ -25,6 +25,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"golang.org/x/exp/slices"
+
+	"github.com/cisco-open/fsoc/output"
 )
 
 var (
@@ -32,19 +34,31 @@ var (
 	setContextLong = `Create or modify a context entry in an fsoc config file.
 
 Specifying a name that already exists will merge new fields on top of existing values for those fields.
-if on context name is specified, the current context is created/updated.`
+if on context name is specified, the current context is created/updated.
+
+Fields can be specified either as flags or as key=value arguments, using the flag names as keys.`
 
 	setContextExample = `# Set the token field on the "prod" context entry without touching other values
-fsoc config set --profile prod --token=top-secret`
+fsoc config set --profile prod --token=top-secret
+
+# Change the tenant and server of the current context
+fsoc config set tenant=foo server=mytenant.observe.appdynamics.com
+
+# Display JSON by default when using the "automation" context
+fsoc config set --profile automation --default-output json`
 )
 
+// contextFieldKeys lists the context fields that can be set with key=value arguments
+// (each has a flag with the same name)
+var contextFieldKeys = []string{"server", "tenant", "token", "secret-file", "objstore-api-version", "auth", "default-output", "audit-log", "field-manager"}
+
 func newCmdConfigSet() *cobra.Command {
 
 	var cmd = &cobra.Command{
-		Use:         "set --profile [CONTEXT] [token=VALUE][tenant=TENANT_ID][secret-file=PATH]",
+		Use:         "set [--profile CONTEXT] [KEY=VALUE]...",
 		Short:       "Create or modify a context entry in an fsoc config file",
 		Long:        setContextLong,
-		Args:        cobra.MaximumNArgs(1),
+		Args:        cobra.ArbitraryArgs,
 		Example:     setContextExample,
 		Annotations: map[string]string{AnnotationForConfigBypass: ""},
 		Run:         configSetContext,
@@ -54,21 +68,25 @@ func newCmdConfigSet() *cobra.Command {
 	cmd.Flags().String("tenant", "", "Set tenant ID in context")
 	cmd.Flags().String("token", "", "Set token value in context (use --token=- to get from stdin)")
 	cmd.Flags().String("secret-file", "", "Set credentials file to use for service principal login (.json or .csv)")
+	cmd.Flags().String("objstore-api-version", "", fmt.Sprintf("Set the object store API version to use (default %q)", DefaultObjStoreAPIVersion))
 	cmd.Flags().String("auth", "", fmt.Sprintf(`Select authentication method, one of {"%v"}`, strings.Join(GetAuthMethodsStringList(), `", "`)))
+	cmd.Flags().String("default-output", "", fmt.Sprintf(`Set the output format used when --output is not specified, one of {"%v"} (empty to clear)`, strings.Join(output.Formats, `", "`)))
+	cmd.Flags().String("audit-log", "", "Set the file to append a JSON record of each create, update and delete request to (empty to clear)")
+	cmd.Flags().String("field-manager", "", fmt.Sprintf("Set the identifier sent with each create, update and delete request to attribute the change (empty for the default, %q)", DefaultFieldManager))
 	return cmd
 }
 
 func configSetContext(cmd *cobra.Command, args []string) {
 	var contextName string
 
-	// Check that either context name or current context is specified
-	if len(args) > 0 {
+	// Apply key=value arguments as if they were specified as flags
+	flags := cmd.Flags()
+	if err := applyKeyValueArgs(cmd, args); err != nil {
 		_ = cmd.Help()
-		log.Fatalf("Unexpected args: %v", args)
+		log.Fatalf("%v", err)
 	}
 
 	// Check that at least one value is specified (including empty)
-	flags := cmd.Flags()
 	valid := false
 	flags.VisitAll(func(flag *pflag.Flag) {
 		valid = valid || flag.Changed
@@ -134,6 +152,9 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		}
 		ctxPtr.CsvFile = "" // CSV file is a backward-compatibility value only
 	}
+	if flags.Changed("objstore-api-version") {
+		ctxPtr.ObjStoreAPIVersion, _ = flags.GetString("objstore-api-version")
+	}
 	if flags.Changed("auth") {
 		val, _ := flags.GetString("auth")
 		if val != "" && !slices.Contains(GetAuthMethodsStringList(), val) {
@@ -141,6 +162,25 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		}
 		ctxPtr.AuthMethod = val
 	}
+	if flags.Changed("default-output") {
+		val, _ := flags.GetString("default-output")
+		if val != "" && !slices.Contains(output.Formats, val) {
+			log.Fatalf(`Invalid --default-output format %q; must be one of {"%v"}`, val, strings.Join(output.Formats, `", "`))
+		}
+		ctxPtr.DefaultOutput = val
+	}
+	if flags.Changed("audit-log") {
+		path, _ := flags.GetString("audit-log")
+		if path != "" {
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+		}
+		ctxPtr.AuditLog = path
+	}
+	if flags.Changed("field-manager") {
+		ctxPtr.FieldManager, _ = flags.GetString("field-manager")
+	}
 
 	// upgrade config format from CsvFile to SecretFile, opportunistically using the update
 	if ctxPtr.SecretFile == "" && ctxPtr.CsvFile != "" {
@@ -162,3 +202,25 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		log.Infof("Created context %q", contextName)
 	}
 }
+
+// applyKeyValueArgs sets the flags named by the keys of key=value arguments.
+// Keys must be names of the context field flags and each field can be specified only once.
+func applyKeyValueArgs(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return fmt.Errorf("invalid argument %q: expected key=value", arg)
+		}
+		if !slices.Contains(contextFieldKeys, key) {
+			return fmt.Errorf("unknown key %q; valid keys are: %v", key, strings.Join(contextFieldKeys, ", "))
+		}
+		if flags.Changed(key) {
+			return fmt.Errorf("%q is specified more than once", key)
+		}
+		if err := flags.Set(key, value); err != nil {
+			return fmt.Errorf("invalid value for %q: %v", key, err)
+		}
+	}
+	return nil
+}
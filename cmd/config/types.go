@@ -19,6 +19,14 @@ const (
 	defaultContext    = "default"
 )
 
+// DefaultObjStoreAPIVersion is the object store API version used unless overridden
+// by the context or the command line
+const DefaultObjStoreAPIVersion = "v1beta"
+
+// DefaultFieldManager identifies the writes made by fsoc unless overridden
+// by the context or the command line
+const DefaultFieldManager = "fsoc"
+
 // Supported authentication methods
 const (
 	// No authentication (used in local/dev environments)
@@ -50,6 +58,11 @@ type Context struct {
 	RefreshToken string `json:"refresh_token,omitempty" yaml:"refresh_token,omitempty" mapstructure:"refresh_token"`
 	CsvFile      string `json:"csv_file,omitempty" yaml:"csv_file,omitempty"`
 	SecretFile   string `json:"secret_file,omitempty" yaml:"secret_file,omitempty" mapstructure:"secret_file"`
+
+	ObjStoreAPIVersion string `json:"objstore_api_version,omitempty" yaml:"objstore_api_version,omitempty" mapstructure:"objstore_api_version"`
+	DefaultOutput      string `json:"default_output,omitempty" yaml:"default_output,omitempty" mapstructure:"default_output"` // output format used when --output is not specified
+	AuditLog           string `json:"audit_log,omitempty" yaml:"audit_log,omitempty" mapstructure:"audit_log"`                // file to append a record of each mutating request to
+	FieldManager       string `json:"field_manager,omitempty" yaml:"field_manager,omitempty" mapstructure:"field_manager"`    // identifies the writes made with the context
 }
 
 // internal, to be renamed to lower case
@@ -15,12 +15,10 @@
 package logs
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/signal"
 	"regexp"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/apex/log"
@@ -149,7 +147,11 @@ func fetchLogs(cmd *cobra.Command, args []string) error {
 	printLogs(resp, formatter, cmd)
 
 	if follow {
-		return followLogs(resp, formatter, variables.Count, cmd)
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return followLogs(ctx, resp, formatter, variables.Count, cmd)
 	}
 
 	return nil
@@ -160,16 +162,15 @@ type eventResult struct {
 	err  error
 }
 
-func followLogs(initialResponse *uql.Response, formatter rowFormatter, limit int, p printer) error {
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+// followLogs displays new log entries as they arrive, until the context is canceled (e.g., by Ctrl-C)
+func followLogs(ctx context.Context, initialResponse *uql.Response, formatter rowFormatter, limit int, p printer) error {
 	eventResults := make(chan eventResult, 1)
 	eventResults <- eventResult{data: extractEventDataSet(initialResponse)}
 
 	for {
 		select {
-		case <-interrupt:
-			return nil
+		case <-ctx.Done():
+			return nil // stopping is the normal way to end following
 		case followResult := <-eventResults:
 			if followResult.err != nil {
 				log.Fatal(followResult.err.Error())
@@ -0,0 +1,437 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// kinds of actions in an apply plan
+const (
+	applyCreate    = "create"
+	applyUpdate    = "update"
+	applyDelete    = "delete"
+	applyUnchanged = "unchanged"
+)
+
+// desiredObject is an object definition read by the apply command
+type desiredObject struct {
+	Type      string         `json:"type"`
+	ID        string         `json:"id"`
+	LayerType string         `json:"layerType"`
+	LayerID   string         `json:"layerId"`
+	Data      map[string]any `json:"data"`
+	file      string
+}
+
+// objectLayer identifies the objects of a type in a layer
+type objectLayer struct {
+	Type      string
+	LayerType string
+	LayerID   string
+}
+
+// applyAction is a step in the plan to reconcile the object store with the desired objects
+type applyAction struct {
+	Op        string         `json:"op" yaml:"op"`
+	Type      string         `json:"type" yaml:"type"`
+	ID        string         `json:"id" yaml:"id"`
+	LayerType string         `json:"layerType" yaml:"layerType"`
+	LayerID   string         `json:"layerId" yaml:"layerId"`
+	File      string         `json:"file,omitempty" yaml:"file,omitempty"`
+	Changes   []diffEntry    `json:"changes,omitempty" yaml:"changes,omitempty"`
+	data      map[string]any // object data to create or update
+}
+
+func newApplyCmd() *cobra.Command {
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Reconcile objects in the object store with object definition files",
+		Long: `Reconcile the objects in the object store with the desired objects defined in a file or in
+a directory of files (including subdirectories, skipping the files listed in the directory's .fsocignore file).
+
+Each json or yaml file defines one object, with its type, ID, layer and data, e.g.:
+
+  type: preferences:theme
+  id: mytheme
+  layerType: TENANT
+  layerId: ...    # optional, derived from the current context like in other objstore commands
+  data:
+    ...
+
+Each desired object is compared with the object in the object store: objects that don't exist are
+created, objects whose data differs are updated (replaced) and other objects are left as is.
+The data of an object to be created must contain the fields from which the type derives the
+object ID. With --prune, objects of the same types and layers that are not in the desired set
+are deleted.
+
+The command displays the plan of changes and applies it only if --auto-approve is specified.
+Use --output json or --output yaml to get the plan in machine-readable form.`,
+		Example: `  # Review the changes needed to reconcile the objects in a directory
+  fsoc obj apply -f objects/
+
+  # Apply the changes, deleting objects that are no longer defined
+  fsoc obj apply -f objects/ --prune --auto-approve`,
+		Args: cobra.NoArgs,
+		RunE: applyObjects,
+	}
+
+	applyCmd.Flags().StringP("file", "f", "", "A json or yaml object definition file or a directory of such files")
+	_ = applyCmd.MarkFlagRequired("file")
+
+	applyCmd.Flags().Bool("auto-approve", false, "Apply the changes instead of only displaying the plan")
+	applyCmd.Flags().Bool("prune", false, "Delete objects of the same types and layers that are not defined in the desired set")
+
+	return applyCmd
+}
+
+func applyObjects(cmd *cobra.Command, args []string) error {
+	path := objectFilePath(cmd, "file")
+	prune, _ := cmd.Flags().GetBool("prune")
+	autoApprove, _ := cmd.Flags().GetBool("auto-approve")
+
+	desired, err := readDesiredObjects(cmd, path)
+	if err != nil {
+		return err
+	}
+	if len(desired) == 0 {
+		log.Warnf("No object definitions found in %q", path)
+		return nil
+	}
+
+	plan, err := buildApplyPlan(apiClient(cmd), desired, prune)
+	if err != nil {
+		return fmt.Errorf("Failed to build the plan: %v", err)
+	}
+
+	if format, _ := cmd.Flags().GetString("output"); format == "json" || format == "yaml" || format == "jsonl" {
+		output.PrintCmdOutput(cmd, plan)
+	} else {
+		printApplyPlan(cmd, plan)
+	}
+
+	if countActions(plan)[applyUnchanged] == len(plan) {
+		return nil
+	}
+	if !autoApprove {
+		output.PrintCmdStatus(cmd, "Run the command with --auto-approve to apply the changes.\n")
+		return nil
+	}
+	return executeApplyPlan(cmd, plan)
+}
+
+// readDesiredObjects reads the desired objects from a file or from the object files in a directory
+func readDesiredObjects(cmd *cobra.Command, path string) ([]desiredObject, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files, err = listObjectFiles(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	desired := make([]desiredObject, 0, len(files))
+	seen := map[string]string{}
+	for _, file := range files {
+		object, err := readObjectFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Can't read the object definition from the %s file: %v", file, err)
+		}
+		obj, err := parseDesiredObject(object, file)
+		if err != nil {
+			return nil, err
+		}
+		if obj.LayerID == "" {
+			obj.LayerID = getCorrectLayerID(obj.LayerType, obj.Type)
+			if obj.LayerID == "" {
+				return nil, fmt.Errorf("%s: unable to determine the layer ID from the current context; please specify layerId", file)
+			}
+		} else if err := checkTenantLayerID(cmd, obj.LayerType, obj.LayerID); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+
+		key := fmt.Sprintf("%s/%s/%s/%s", obj.Type, obj.LayerType, obj.LayerID, obj.ID)
+		if other, found := seen[key]; found {
+			return nil, fmt.Errorf("object %s %q is defined in both %s and %s", obj.Type, obj.ID, other, file)
+		}
+		seen[key] = file
+		desired = append(desired, obj)
+	}
+	return desired, nil
+}
+
+// parseDesiredObject converts the content of an object definition file into a desired object
+func parseDesiredObject(object map[string]any, file string) (desiredObject, error) {
+	obj := desiredObject{file: file}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return obj, fmt.Errorf("%s: %v", file, err)
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return obj, fmt.Errorf("%s: invalid object definition: %v", file, err)
+	}
+
+	var missing []string
+	if obj.Type == "" {
+		missing = append(missing, "type")
+	}
+	if obj.ID == "" {
+		missing = append(missing, "id")
+	}
+	if obj.LayerType == "" {
+		missing = append(missing, "layerType")
+	}
+	if obj.Data == nil {
+		missing = append(missing, "data")
+	}
+	if len(missing) > 0 {
+		return obj, fmt.Errorf("%s: missing %s", file, strings.Join(missing, ", "))
+	}
+
+	var lt layerType
+	if err := lt.Set(obj.LayerType); err != nil {
+		return obj, fmt.Errorf("%s: invalid layerType %q: %v", file, obj.LayerType, err)
+	}
+	obj.LayerType = string(lt)
+	return obj, nil
+}
+
+// buildApplyPlan fetches the current state of the desired objects and, if pruning,
+// the objects in their layers, and returns the actions to reconcile them
+func buildApplyPlan(client *api.Client, desired []desiredObject, prune bool) ([]applyAction, error) {
+	plan := make([]applyAction, 0, len(desired))
+	for _, obj := range desired {
+		var res map[string]any
+		err := client.JSONGet(getObjectUrl(obj.Type, obj.ID), &res, &api.Options{Headers: layerHeaders(obj.LayerType, obj.LayerID)})
+		if err != nil && !api.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to fetch %s object %q: %v", obj.Type, obj.ID, err)
+		}
+		var current any
+		if err == nil {
+			current = res["data"]
+		}
+		plan = append(plan, planObject(obj, current, err == nil))
+	}
+
+	if prune {
+		existing := map[objectLayer][]string{}
+		for _, layer := range desiredLayers(desired) {
+			var res any
+			options := api.Options{Headers: layerHeaders(layer.LayerType, layer.LayerID)}
+			if err := client.JSONGetCollection(getObjectListUrl(layer.Type), &res, &options); err != nil {
+				return nil, fmt.Errorf("failed to list objects of type %s: %v", layer.Type, err)
+			}
+			if options.CollectionTruncated {
+				return nil, fmt.Errorf("too many objects of type %s to prune; use --max-items to raise the limit", layer.Type)
+			}
+			ids, err := collectionObjectIDs(res)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the list of objects of type %s: %v", layer.Type, err)
+			}
+			existing[layer] = ids
+		}
+		plan = append(plan, planPrune(desired, existing)...)
+	}
+
+	return plan, nil
+}
+
+// planObject returns the action needed to reconcile the desired object with
+// the current object data, if the object exists
+func planObject(obj desiredObject, current any, exists bool) applyAction {
+	action := applyAction{
+		Op:        applyUnchanged,
+		Type:      obj.Type,
+		ID:        obj.ID,
+		LayerType: obj.LayerType,
+		LayerID:   obj.LayerID,
+		File:      obj.file,
+		data:      obj.Data,
+	}
+	if !exists {
+		action.Op = applyCreate
+		return action
+	}
+	action.Changes = diffValues(current, obj.Data, "")
+	if len(action.Changes) > 0 {
+		action.Op = applyUpdate
+	}
+	return action
+}
+
+// planPrune returns the delete actions for the existing objects that are not in the desired set
+func planPrune(desired []desiredObject, existing map[objectLayer][]string) []applyAction {
+	wanted := map[objectLayer]map[string]bool{}
+	for _, obj := range desired {
+		layer := objectLayer{Type: obj.Type, LayerType: obj.LayerType, LayerID: obj.LayerID}
+		if wanted[layer] == nil {
+			wanted[layer] = map[string]bool{}
+		}
+		wanted[layer][obj.ID] = true
+	}
+
+	var actions []applyAction
+	for _, layer := range sortedLayers(existing) {
+		for _, id := range existing[layer] {
+			if !wanted[layer][id] {
+				actions = append(actions, applyAction{
+					Op:        applyDelete,
+					Type:      layer.Type,
+					ID:        id,
+					LayerType: layer.LayerType,
+					LayerID:   layer.LayerID,
+				})
+			}
+		}
+	}
+	return actions
+}
+
+// desiredLayers returns the distinct type/layer combinations of the desired objects
+func desiredLayers(desired []desiredObject) []objectLayer {
+	layers := map[objectLayer][]string{}
+	for _, obj := range desired {
+		layers[objectLayer{Type: obj.Type, LayerType: obj.LayerType, LayerID: obj.LayerID}] = nil
+	}
+	return sortedLayers(layers)
+}
+
+func sortedLayers(layers map[objectLayer][]string) []objectLayer {
+	sorted := make([]objectLayer, 0, len(layers))
+	for layer := range layers {
+		sorted = append(sorted, layer)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.LayerType != b.LayerType {
+			return a.LayerType < b.LayerType
+		}
+		return a.LayerID < b.LayerID
+	})
+	return sorted
+}
+
+func layerHeaders(layerType string, layerID string) map[string]string {
+	return map[string]string{
+		"layer-type": layerType,
+		"layer-id":   layerID,
+	}
+}
+
+func countActions(plan []applyAction) map[string]int {
+	counts := map[string]int{}
+	for _, action := range plan {
+		counts[action.Op]++
+	}
+	return counts
+}
+
+// printApplyPlan displays the plan, with the changes to each updated object
+func printApplyPlan(cmd *cobra.Command, plan []applyAction) {
+	color := output.ColorEnabled(cmd)
+	colorize := func(colorCode string, s string) string {
+		if color {
+			return output.Colorize(colorCode, s)
+		}
+		return s
+	}
+
+	var sb strings.Builder
+	for _, action := range plan {
+		name := fmt.Sprintf("%s %q in %s layer %s", action.Type, action.ID, action.LayerType, action.LayerID)
+		switch action.Op {
+		case applyCreate:
+			sb.WriteString(colorize(output.ColorGreen, "+ create "+name) + "\n")
+		case applyDelete:
+			sb.WriteString(colorize(output.ColorRed, "- delete "+name) + "\n")
+		case applyUpdate:
+			sb.WriteString(fmt.Sprintf("~ update %s\n", name))
+			for _, d := range action.Changes {
+				if d.Op != diffAdded {
+					sb.WriteString(colorize(output.ColorRed, fmt.Sprintf("    - %v: %v", d.Path, formatDiffValue(d.Old))) + "\n")
+				}
+				if d.Op != diffRemoved {
+					sb.WriteString(colorize(output.ColorGreen, fmt.Sprintf("    + %v: %v", d.Path, formatDiffValue(d.New))) + "\n")
+				}
+			}
+		}
+	}
+
+	counts := countActions(plan)
+	sb.WriteString(fmt.Sprintf("Plan: %v to create, %v to update, %v to delete, %v unchanged.\n",
+		counts[applyCreate], counts[applyUpdate], counts[applyDelete], counts[applyUnchanged]))
+	output.PrintCmdStatus(cmd, sb.String())
+}
+
+// executeApplyPlan performs the actions of the plan, continuing on failure
+func executeApplyPlan(cmd *cobra.Command, plan []applyAction) error {
+	idempotencyKey, err := newIdempotencyKey()
+	if err != nil {
+		return err
+	}
+
+	failed, total := 0, 0
+	for _, action := range plan {
+		if action.Op == applyUnchanged {
+			continue
+		}
+		if cmdkit.Interrupted(cmd) {
+			output.PrintCmdStatus(cmd, fmt.Sprintf("Applied %v changes (%v failed) before the interrupt.\n", total-failed, failed))
+			return cmdkit.InterruptedError(cmd)
+		}
+		total++
+
+		headers := layerHeaders(action.LayerType, action.LayerID)
+		var res any
+		switch action.Op {
+		case applyCreate:
+			_, err = postObject(apiClient(cmd), action.Type, action.data, action.LayerType, action.LayerID, idempotencyKey+"-"+action.ID, false)
+		case applyUpdate:
+			err = apiClient(cmd).JSONPut(getObjectUrl(action.Type, action.ID), action.data, &res, &api.Options{Headers: headers})
+		case applyDelete:
+			err = apiClient(cmd).JSONDelete(getObjectUrl(action.Type, action.ID), &res, &api.Options{Headers: headers})
+		}
+		if err != nil {
+			failed++
+			output.PrintCmdStatus(cmd, fmt.Sprintf("%s %q: %s failed: %v\n", action.Type, action.ID, action.Op, err))
+			continue
+		}
+		output.PrintCmdStatus(cmd, fmt.Sprintf("%s %q: %sd\n", action.Type, action.ID, action.Op)) // created, updated, deleted
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%v of %v changes could not be applied", failed, total)
+	}
+	output.PrintCmdStatus(cmd, fmt.Sprintf("Applied %v changes.\n", total))
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDesiredObject(t *testing.T) {
+	obj, err := parseDesiredObject(map[string]any{
+		"type":      "preferences:theme",
+		"id":        "mytheme",
+		"layerType": "TENANT",
+		"data":      map[string]any{"name": "mytheme"},
+	}, "mytheme.yaml")
+	require.Nil(t, err)
+	assert.Equal(t, "preferences:theme", obj.Type)
+	assert.Equal(t, "mytheme", obj.ID)
+	assert.Equal(t, "", obj.LayerID)
+	assert.Equal(t, map[string]any{"name": "mytheme"}, obj.Data)
+
+	_, err = parseDesiredObject(map[string]any{"type": "preferences:theme"}, "bad.yaml")
+	require.NotNil(t, err)
+	assert.Equal(t, "bad.yaml: missing id, layerType, data", err.Error())
+
+	_, err = parseDesiredObject(map[string]any{
+		"type":      "preferences:theme",
+		"id":        "mytheme",
+		"layerType": "NOSUCHLAYER",
+		"data":      map[string]any{},
+	}, "bad.yaml")
+	assert.NotNil(t, err)
+}
+
+func TestPlanObject(t *testing.T) {
+	obj := desiredObject{Type: "t:a", ID: "x", LayerType: "TENANT", LayerID: "t1", Data: map[string]any{"v": 1}}
+
+	assert.Equal(t, applyCreate, planObject(obj, nil, false).Op)
+	assert.Equal(t, applyUnchanged, planObject(obj, map[string]any{"v": float64(1)}, true).Op)
+
+	action := planObject(obj, map[string]any{"v": 2}, true)
+	assert.Equal(t, applyUpdate, action.Op)
+	assert.Equal(t, []diffEntry{{Path: "v", Op: diffChanged, Old: 2, New: 1}}, action.Changes)
+}
+
+func TestPlanPrune(t *testing.T) {
+	desired := []desiredObject{
+		{Type: "t:a", ID: "x", LayerType: "TENANT", LayerID: "t1"},
+		{Type: "t:a", ID: "y", LayerType: "TENANT", LayerID: "t1"},
+	}
+	existing := map[objectLayer][]string{
+		{Type: "t:a", LayerType: "TENANT", LayerID: "t1"}: {"x", "y", "z"},
+	}
+
+	actions := planPrune(desired, existing)
+	assert.Equal(t, []applyAction{{Op: applyDelete, Type: "t:a", ID: "z", LayerType: "TENANT", LayerID: "t1"}}, actions)
+	assert.Equal(t, map[string]int{applyDelete: 1}, countActions(actions))
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/output"
+)
+
+// Status values of the items of a bulk operation
+const (
+	bulkItemCreated = "created"
+	bulkItemMerged  = "merged" // the object already existed and was merged into (see --merge-existing)
+	bulkItemFailed  = "failed"
+)
+
+// bulkItemResult is the outcome of creating one object in a bulk operation
+type bulkItemResult struct {
+	Source   string `json:"source" yaml:"source"` // file or file:line the object was read from
+	Status   string `json:"status" yaml:"status"`
+	ObjectID string `json:"objectId,omitempty" yaml:"objectId,omitempty"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// bulkResult is the report of a bulk operation, displayed with the json and yaml output formats
+type bulkResult struct {
+	Type        string           `json:"type,omitempty" yaml:"type,omitempty"`
+	Total       int              `json:"total" yaml:"total"`
+	Created     int              `json:"created" yaml:"created"`
+	Merged      int              `json:"merged,omitempty" yaml:"merged,omitempty"`
+	Failed      int              `json:"failed" yaml:"failed"`
+	Stopped     bool             `json:"stopped,omitempty" yaml:"stopped,omitempty"`         // stopped at the first failure
+	Interrupted bool             `json:"interrupted,omitempty" yaml:"interrupted,omitempty"` // stopped by an interrupt
+	Items       []bulkItemResult `json:"items" yaml:"items"`
+}
+
+// bulkReport collects the outcome of a bulk operation. In the human output formats, each item's
+// outcome is displayed as it is added; in the machine formats, the whole report is displayed at the end.
+type bulkReport struct {
+	cmd        *cobra.Command
+	structured bool
+	result     bulkResult
+}
+
+func newBulkReport(cmd *cobra.Command, objType string) *bulkReport {
+	return &bulkReport{
+		cmd:        cmd,
+		structured: isMachineFormat(cmd),
+		result:     bulkResult{Type: objType, Items: []bulkItemResult{}},
+	}
+}
+
+// isMachineFormat returns true if the command's output format is meant for automation
+func isMachineFormat(cmd *cobra.Command) bool {
+	format, _ := cmd.Flags().GetString("output")
+	return format == "json" || format == "yaml" || format == "jsonl"
+}
+
+// add records the outcome of creating an object, or of merging it into the existing object;
+// note is displayed after a success, if not empty
+func (r *bulkReport) add(source string, objectID string, merged bool, err error, note string) {
+	r.result.Total++
+	if err != nil {
+		r.result.Failed++
+		r.result.Items = append(r.result.Items, bulkItemResult{Source: source, Status: bulkItemFailed, Error: err.Error()})
+		r.status(fmt.Sprintf("%v: failed: %v\n", source, err))
+		return
+	}
+	status := bulkItemCreated
+	if merged {
+		status = bulkItemMerged
+		r.result.Merged++
+	} else {
+		r.result.Created++
+	}
+	r.result.Items = append(r.result.Items, bulkItemResult{Source: source, Status: status, ObjectID: objectID})
+	r.status(fmt.Sprintf("%v: %v%v\n", source, status, note))
+}
+
+// status displays a message in the human output formats; with the machine formats, it is logged instead
+func (r *bulkReport) status(message string) {
+	if r.structured {
+		log.Info(message)
+		return
+	}
+	output.PrintCmdStatus(r.cmd, message)
+}
+
+// finish displays the summary or the report and returns an error if the operation was not complete
+func (r *bulkReport) finish() error {
+	r.result.Interrupted = cmdkit.Interrupted(r.cmd)
+	if r.structured {
+		output.PrintCmdOutput(r.cmd, r.result)
+	} else {
+		noun := "objects"
+		if r.result.Type != "" { // empty if each object specifies its type
+			noun = r.result.Type + " objects"
+		}
+		summary := fmt.Sprintf("Created %v", r.result.Created)
+		if r.result.Merged > 0 {
+			summary += fmt.Sprintf(" and merged %v", r.result.Merged)
+		}
+		output.PrintCmdStatus(r.cmd, fmt.Sprintf("%v of %v %s\n", summary, r.result.Total, noun))
+	}
+
+	if err := cmdkit.InterruptedError(r.cmd); err != nil {
+		return err
+	}
+	if r.result.Stopped {
+		return fmt.Errorf("stopped at the first failure (--stop-on-error)")
+	}
+	if r.result.Failed > 0 {
+		return fmt.Errorf("%v of %v objects could not be created", r.result.Failed, r.result.Total)
+	}
+	return nil
+}
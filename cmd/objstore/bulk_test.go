@@ -0,0 +1,104 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+)
+
+func newTestBulkCmd(format string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", format, "")
+	cmd.Flags().String("fields", "", "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+func TestBulkReportHuman(t *testing.T) {
+	cmd, out := newTestBulkCmd("auto")
+	report := newBulkReport(cmd, "test:type")
+	report.add("a.json", "a", false, nil, " (1 field set)")
+	report.add("b.json", "b", false, nil, "")
+	assert.Nil(t, report.finish())
+
+	assert.Equal(t, "a.json: created (1 field set)\nb.json: created\nCreated 2 of 2 test:type objects\n", out.String())
+}
+
+func TestBulkReportStructured(t *testing.T) {
+	cmd, out := newTestBulkCmd("json")
+	report := newBulkReport(cmd, "test:type")
+	report.add("objects.ndjson:1", "a", false, nil, "")
+	report.add("objects.ndjson:2", "", false, errors.New("rejected"), "")
+	assert.Empty(t, out.String()) // nothing but the report is displayed
+
+	output, err := json.Marshal(report.result)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"type": "test:type", "total": 2, "created": 1, "failed": 1,
+		"items": [
+			{"source": "objects.ndjson:1", "status": "created", "objectId": "a"},
+			{"source": "objects.ndjson:2", "status": "failed", "error": "rejected"}
+		]}`, string(output))
+}
+
+func TestBulkReportMerged(t *testing.T) {
+	cmd, out := newTestBulkCmd("auto")
+	report := newBulkReport(cmd, "test:type")
+	report.add("a.json", "a", false, nil, "")
+	report.add("b.json", "b", true, nil, "")
+	assert.Nil(t, report.finish())
+	assert.Equal(t, "a.json: created\nb.json: merged\nCreated 1 and merged 1 of 2 test:type objects\n", out.String())
+
+	output, err := json.Marshal(report.result)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"type": "test:type", "total": 2, "created": 1, "merged": 1, "failed": 0,
+		"items": [
+			{"source": "a.json", "status": "created", "objectId": "a"},
+			{"source": "b.json", "status": "merged", "objectId": "b"}
+		]}`, string(output))
+}
+
+func TestBulkReportInterrupted(t *testing.T) {
+	cmd, out := newTestBulkCmd("auto")
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd.SetContext(ctx)
+	report := newBulkReport(cmd, "test:type")
+	report.add("a.json", "a", false, nil, "")
+	cancel()
+
+	var exitErr cmdkit.ExitCodeError
+	require.True(t, errors.As(report.finish(), &exitErr))
+	assert.Equal(t, cmdkit.ExitCodeInterrupted, exitErr.Code)
+	assert.Contains(t, out.String(), "Created 1 of 1 test:type objects")
+	assert.True(t, report.result.Interrupted)
+}
+
+func TestCreatedObjectID(t *testing.T) {
+	assert.Equal(t, "from-response", createdObjectID(map[string]any{"id": "from-response"}, map[string]any{"id": "from-object"}))
+	assert.Equal(t, "from-object", createdObjectID(nil, map[string]any{"id": "from-object"}))
+	assert.Equal(t, "", createdObjectID(map[string]any{}, map[string]any{}))
+}
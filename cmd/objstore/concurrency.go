@@ -0,0 +1,87 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/apex/log"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// concurrencyLimiter limits the number of workers running at the same time, adapting the
+// limit to the server's rate limiting with a simple AIMD (additive increase, multiplicative
+// decrease) controller: the limit is halved when a request is throttled (429) and increased
+// by one after a limit's worth of successful requests, up to the configured maximum.
+// The limit is halved once per burst of throttling: requests that started before the last
+// decrease were sent at the higher concurrency, so their throttling is not counted again.
+type concurrencyLimiter struct {
+	mu         sync.Mutex
+	cond       *sync.Cond
+	max        int
+	limit      int
+	active     int
+	successes  int // successes since the last change of the limit
+	generation int // incremented on each decrease of the limit
+}
+
+func newConcurrencyLimiter(max int) *concurrencyLimiter {
+	if max < 1 {
+		max = 1
+	}
+	l := &concurrencyLimiter{max: max, limit: max}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire waits until a worker can start and returns the generation of the limit it started
+// with, to be passed to release
+func (l *concurrencyLimiter) acquire() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+	return l.generation
+}
+
+// release ends a worker that started with the given generation and adapts the limit to the
+// worker's outcome
+func (l *concurrencyLimiter) release(generation int, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+
+	switch {
+	case api.HasStatus(err, http.StatusTooManyRequests):
+		l.successes = 0
+		if l.limit > 1 && generation == l.generation {
+			l.limit /= 2
+			l.generation++
+			log.Debugf("Throttled by the server, reducing concurrency to %v", l.limit)
+		}
+	case err == nil && l.limit < l.max:
+		l.successes++
+		if l.successes >= l.limit {
+			l.successes = 0
+			l.limit++
+			log.Debugf("Increasing concurrency to %v", l.limit)
+		}
+	}
+	l.cond.Broadcast()
+}
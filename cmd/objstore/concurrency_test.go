@@ -0,0 +1,75 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	throttled := api.ResponseError{StatusCode: http.StatusTooManyRequests}
+	l := newConcurrencyLimiter(8)
+
+	// multiplicative decrease on throttling, down to 1
+	l.release(l.acquire(), throttled)
+	assert.Equal(t, 4, l.limit)
+	for i := 0; i < 5; i++ {
+		l.release(l.acquire(), throttled)
+	}
+	assert.Equal(t, 1, l.limit)
+
+	// other failures don't change the limit
+	l.release(l.acquire(), errors.New("not found"))
+	assert.Equal(t, 1, l.limit)
+
+	// additive increase after a limit's worth of successes, up to the maximum
+	l.release(l.acquire(), nil)
+	assert.Equal(t, 2, l.limit)
+	for i := 0; i < 2; i++ {
+		l.release(l.acquire(), nil)
+	}
+	assert.Equal(t, 3, l.limit)
+	for i := 0; i < 100; i++ {
+		l.release(l.acquire(), nil)
+	}
+	assert.Equal(t, 8, l.limit)
+	assert.Equal(t, 0, l.active)
+}
+
+func TestConcurrencyLimiterThrottledBurst(t *testing.T) {
+	throttled := api.ResponseError{StatusCode: http.StatusTooManyRequests}
+	l := newConcurrencyLimiter(8)
+
+	// the requests in flight when the server starts throttling halve the limit once
+	generations := []int{}
+	for i := 0; i < 8; i++ {
+		generations = append(generations, l.acquire())
+	}
+	for _, generation := range generations {
+		l.release(generation, throttled)
+	}
+	assert.Equal(t, 4, l.limit)
+
+	// a request started after the decrease is throttled at the lower limit
+	l.release(l.acquire(), throttled)
+	assert.Equal(t, 2, l.limit)
+	assert.Equal(t, 0, l.active)
+}
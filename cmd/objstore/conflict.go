@@ -0,0 +1,87 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/apex/log"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// updateWithChanges updates the object by applying the changes (a JSON merge patch) to its current
+// data. The object is replaced only if it has not changed since it was fetched (using its ETag, if the
+// platform provides one); if it has, it is fetched again and the changes re-applied, up to retries times.
+func updateWithChanges(client *api.Client, objType string, objID string, headers map[string]string, changes map[string]any, retries int) error {
+	objectUrl := getObjectUrl(objType, objID)
+	for retry := 0; ; retry++ {
+		var current map[string]any
+		getOptions := api.Options{Headers: headers}
+		if err := client.JSONGet(objectUrl, &current, &getOptions); err != nil {
+			return fmt.Errorf("failed to fetch the current %s object %q: %w", objType, objID, err)
+		}
+
+		putHeaders := map[string]string{}
+		for k, v := range headers {
+			putHeaders[k] = v
+		}
+		if etag := http.Header(getOptions.ResponseHeaders).Get("ETag"); etag != "" {
+			putHeaders["If-Match"] = etag
+		} else {
+			log.Infof("The platform returned no ETag for the %s object %q; the update can't detect concurrent changes", objType, objID)
+		}
+
+		var res any
+		data := applyMergePatch(current["data"], changes)
+		err := client.JSONPut(objectUrl, data, &res, &api.Options{Headers: putHeaders})
+		if err == nil {
+			return nil
+		}
+		if !isConflict(err) || retry >= retries {
+			return err
+		}
+		log.Warnf("The %s object %q was changed concurrently; re-applying the changes to its latest version (retry %v of %v)", objType, objID, retry+1, retries)
+	}
+}
+
+// isConflict returns true if the error indicates that the object was changed concurrently
+func isConflict(err error) bool {
+	return api.HasStatus(err, http.StatusConflict) || api.HasStatus(err, http.StatusPreconditionFailed)
+}
+
+// applyMergePatch applies a JSON merge patch (RFC 7386) to the target value and returns the result:
+// objects are merged recursively, null removes a field and any other value replaces the target's.
+// The target is not modified.
+func applyMergePatch(target any, patch any) any {
+	patchMap, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetMap, _ := target.(map[string]any)
+	result := make(map[string]any, len(targetMap)+len(patchMap))
+	for k, v := range targetMap {
+		result[k] = v
+	}
+	for k, v := range patchMap {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+		result[k] = applyMergePatch(result[k], v)
+	}
+	return result
+}
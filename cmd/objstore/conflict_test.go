@@ -0,0 +1,85 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	target := map[string]any{"color": "blue", "font": map[string]any{"size": 12, "face": "serif"}, "tags": []any{"a"}}
+	patch := map[string]any{"color": "green", "font": map[string]any{"face": nil}, "tags": []any{"b"}, "extra": true}
+
+	assert.Equal(t, map[string]any{"color": "green", "font": map[string]any{"size": 12}, "tags": []any{"b"}, "extra": true}, applyMergePatch(target, patch))
+	assert.Equal(t, "blue", target["color"]) // the target is not modified
+	assert.Equal(t, map[string]any{"a": 1}, applyMergePatch(nil, map[string]any{"a": 1}))
+}
+
+// newConcurrentPlatform starts a server with an object that is changed concurrently by someone
+// else the first conflicts times it is replaced, recording the objects it received
+func newConcurrentPlatform(t *testing.T, conflicts int) (*api.Client, *[]map[string]any) {
+	version := 1
+	puts := []map[string]any{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", fmt.Sprintf(`"v%v"`, version))
+			_, _ = fmt.Fprintf(w, `{"id": "mytheme", "data": {"color": "blue", "version": %v}}`, version)
+		case http.MethodPut:
+			var body map[string]any
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			puts = append(puts, body)
+			if conflicts > 0 {
+				conflicts--
+				version++ // someone else changed the object
+			}
+			if r.Header.Get("If-Match") != fmt.Sprintf(`"v%v"`, version) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_, _ = w.Write([]byte(`{"message": "object was modified"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	return client, &puts
+}
+
+func TestUpdateWithChangesRetriesOnConflict(t *testing.T) {
+	client, puts := newConcurrentPlatform(t, 1)
+	changes := map[string]any{"color": "green"}
+
+	err := updateWithChanges(client, "preferences:theme", "mytheme", map[string]string{}, changes, 2)
+	require.Nil(t, err)
+	require.Len(t, *puts, 2)
+	assert.Equal(t, map[string]any{"color": "green", "version": float64(2)}, (*puts)[1]) // applied to the latest version
+}
+
+func TestUpdateWithChangesConflictExhausted(t *testing.T) {
+	client, puts := newConcurrentPlatform(t, 5)
+
+	err := updateWithChanges(client, "preferences:theme", "mytheme", map[string]string{}, map[string]any{"color": "green"}, 1)
+	require.NotNil(t, err)
+	assert.True(t, isConflict(err))
+	assert.Len(t, *puts, 2)
+}
@@ -15,107 +15,370 @@
 package objstore
 
 import (
-	"encoding/json"
-	"io"
-	"os"
+	"fmt"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
 )
 
 var objStoreInsertCmd = &cobra.Command{
-	Use:   "create",
-	Short: "Create a new object of a given type",
+	Use:     "create",
+	Aliases: []string{"import"},
+	Short:   "Create a new object of a given type",
 	Long: `This command allows the creation of a new object of a given type in the Object Store.
+	It is also available as "import", e.g., to clone objects between environments with --object-dir and --transform.
 
 	Usage:
 	fsoc objstore create --type<fully-qualified-typename> --object-file=<fully-qualified-path> --layer-type=<valid-layer-type> [--layer-id=<valid-layer-id>]
 	
 	Flags/Options:
-	--type - Flag to indicate the fully qualified type name of the object that you would like to create
-	--object-file - Flag to indicate the fully qualified path (from your root directory) to the file containing the definition of the object that you want to create
+	--type - Flag to indicate the fully qualified type name of the object that you would like to create. It can be omitted if the object file specifies the type in its top-level "$type" field, e.g., {"$type": "preferences:theme", ...}; the field is removed before the object is created and the flag takes precedence over it
+	--object-file - Flag to indicate the fully qualified path (from your root directory) to the file containing the definition of the object that you want to create. Also available as --request-body-file. The file is loaded in memory in full; a warning is displayed for files larger than 10MB (use --no-size-warning to suppress it)
+	--ndjson - OPTIONAL Flag to indicate that the object file is a newline-delimited JSON file with one object per line, e.g., for very large imports. The file is read line by line and the objects are created one by one, reporting the progress and continuing past failures
+	--stop-on-error - OPTIONAL Flag to stop at the first object that can't be created (with --ndjson)
+	--progress-interval - OPTIONAL Flag to specify how often to report the progress, in number of objects (with --ndjson; default 1000, 0 to disable)
+	--object-dir - OPTIONAL Flag to create an object from each .json, .yaml and .yml file in a directory and its subdirectories, instead of a single object file. Files matching the patterns in the directory's .fsocignore file (gitignore syntax) are skipped
+	--transform - OPTIONAL Flag to specify a find=replace rule applied to all string values of each object before it is created. Can be repeated; rules are applied in order and the replacements made are reported for each object
+	--transform-file - OPTIONAL Flag to specify a file with transform rules, one find=replace rule per line (lines starting with # are comments)
 	--layer-type - Flag to indicate the layer at which you would like to create your object
-	--layer-id - OPTIONAL Flag to specify a custom layer ID for the object that you would like to create.  This is calculated automatically for all layers currently supported but can be overridden with this flag`,
+	--layer-id - OPTIONAL Flag to specify a custom layer ID for the object that you would like to create.  This is calculated automatically for all layers currently supported but can be overridden with this flag. Can be repeated to create the object in several layers of the same type (e.g., several tenants), reporting the outcome for each layer
+	--interactive - OPTIONAL (experimental) Flag to build the object by answering a prompt for each field defined in the type's schema, instead of providing an object file
+	--idempotency-key - OPTIONAL Flag to specify the key sent in the Idempotency-Key header, so that a retried request does not create a duplicate object. If not specified, a key is generated for each invocation; with --object-dir, the key is suffixed with the path of each object file. Note that this only prevents duplicates if the platform honors the header. Specifying the key also allows --retries to retry the create request on any transient failure, as the key is expected to be honored; with a generated key, the request is retried only if it was not sent (or with --retry-writes)
+	--merge-existing - OPTIONAL Flag to merge the object into the existing object with the same ID, as a JSON merge patch, when the platform reports that the object already exists (409 Conflict), instead of failing. The object must specify its ID. Without the flag, creating an object that already exists fails
+	--target-section - OPTIONAL Flag to specify the name of a top-level section in the object file that contains the layer to create the object in, e.g., {"target": {"layerType": "TENANT", "layerId": "..."}}. The section is removed from the object before it is created. Values from --layer-type and --layer-id take precedence over the section's values
+
+	With --object-dir and --ndjson, the outcome of each object is displayed as it is created, followed by a summary. With --output json or yaml, a report is displayed instead, once all objects are processed: the totals and, for each object, its source (file or file:line), status (created, merged with --merge-existing, or failed), the ID of the object or the error.`,
 
 	Args:             cobra.ExactArgs(0),
-	Run:              insertObject,
+	RunE:             insertObject,
 	TraverseChildren: true,
 }
 
 func getCreateObjectCmd() *cobra.Command {
 	objStoreInsertCmd.Flags().
-		String("type", "", "The fully qualified type name of the object")
-	_ = objStoreInsertCmd.MarkPersistentFlagRequired("type")
+		String("type", "", "The fully qualified type name of the object (default is the object file's \"$type\" field)")
 
 	objStoreInsertCmd.Flags().
-		String("object-file", "", "The fully qualified path to the json file containing the object definition")
+		String("object-file", "", "The fully qualified path to the json or yaml file containing the object definition")
 	_ = objStoreInsertCmd.MarkPersistentFlagRequired("objectFile")
 
+	objStoreInsertCmd.Flags().
+		String("object-dir", "", "A directory of json or yaml object files to create an object from each; files listed in the directory's .fsocignore file are skipped")
+
 	objStoreInsertCmd.Flags().
 		String("layer-type", "", "The layer-type that the created object will be added to")
 	_ = objStoreInsertCmd.MarkPersistentFlagRequired("layer-type")
 
 	objStoreInsertCmd.Flags().
-		String("layer-id", "", "The layer-id that the created object will be added to. Optional for TENANT and SOLUTION layers ")
+		StringArray("layer-id", nil, "The layer-id that the created object will be added to. Optional for TENANT and SOLUTION layers. Can be repeated to create the object in several layers")
+
+	objStoreInsertCmd.Flags().
+		Bool("interactive", false, "(experimental) Fetch the type's schema and prompt for each field instead of reading an object file")
+
+	objStoreInsertCmd.Flags().
+		String("target-section", "", "The name of a top-level section in the object file that specifies the layerType and layerId to use when the flags are omitted")
+
+	objStoreInsertCmd.Flags().
+		StringArray("transform", nil, "A find=replace rule applied to all string values of each object before it is created, e.g., to remap tenant IDs when cloning objects between environments (can be repeated)")
+
+	objStoreInsertCmd.Flags().
+		String("transform-file", "", "A file with find=replace transform rules, one per line; applied before the --transform rules")
+
+	objStoreInsertCmd.Flags().
+		String("idempotency-key", "", "The idempotency key to send with the create request, allowing the platform to dedupe retried requests (default: generated for each invocation)")
+
+	objStoreInsertCmd.Flags().
+		Bool("ndjson", false, "The object file is newline-delimited JSON, with one object per line")
+
+	objStoreInsertCmd.Flags().
+		Bool("stop-on-error", false, "Stop at the first object that can't be created (with --ndjson)")
+
+	objStoreInsertCmd.Flags().
+		Int("progress-interval", defaultProgressInterval, "Report the progress every this many objects (with --ndjson; 0 to disable)")
+
+	objStoreInsertCmd.Flags().
+		Bool("merge-existing", false, "If an object with the same ID already exists, merge the object into it (JSON merge patch) instead of failing")
+
+	objStoreInsertCmd.MarkFlagsMutuallyExclusive("object-file", "object-dir", "interactive")
+	objStoreInsertCmd.MarkFlagsMutuallyExclusive("ndjson", "object-dir")
+	objStoreInsertCmd.MarkFlagsMutuallyExclusive("ndjson", "interactive")
+
+	useRequestBodyFileAlias(objStoreInsertCmd)
 
 	return objStoreInsertCmd
 
 }
 
-func insertObject(cmd *cobra.Command, args []string) {
+func insertObject(cmd *cobra.Command, args []string) error {
 	objType, _ := cmd.Flags().GetString("type")
 
-	objJsonFilePath, _ := cmd.Flags().GetString("object-file")
-	objectFile, err := os.Open(objJsonFilePath)
+	transforms, err := getTransformRules(cmd)
 	if err != nil {
-		log.Errorf("Can't find the object definition file named %s", objJsonFilePath)
-		return
+		return err
+	}
+
+	idempotencyKey, err := getIdempotencyKey(cmd)
+	if err != nil {
+		return err
+	}
+
+	if objectDir := objectFilePath(cmd, "object-dir"); objectDir != "" {
+		return insertObjectsFromDir(cmd, objType, objectDir, transforms, idempotencyKey)
 	}
-	defer objectFile.Close()
 
-	objectBytes, _ := io.ReadAll(objectFile)
 	var objectStruct map[string]interface{}
-	err = json.Unmarshal(objectBytes, &objectStruct)
+	objJsonFilePath := objectFilePath(cmd, "object-file")
+	if ndjson, _ := cmd.Flags().GetBool("ndjson"); ndjson {
+		if objJsonFilePath == "" {
+			return fmt.Errorf("--ndjson requires the --object-file flag")
+		}
+		return insertObjectsFromNDJSON(cmd, objType, objJsonFilePath, transforms, idempotencyKey)
+	}
+	if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+		if objType == "" {
+			return fmt.Errorf("--interactive requires the --type flag")
+		}
+		objectStruct, err = promptForObject(cmd, objType)
+		if err != nil {
+			return fmt.Errorf("Can't build a %s object interactively: %v", objType, err)
+		}
+	} else {
+		warnLargeObjectFile(cmd, objJsonFilePath)
+		objectStruct, err = readObjectFile(objJsonFilePath)
+		if err != nil {
+			return fmt.Errorf("Can't generate a %s object from the %s file: %v", objType, objJsonFilePath, err)
+		}
+		objType, err = resolveObjectType(objType, objectStruct)
+		if err != nil {
+			return fmt.Errorf("Can't create an object from the %s file: %v", objJsonFilePath, err)
+		}
+	}
+
+	if len(transforms) > 0 {
+		counts := applyTransforms(objectStruct, transforms)
+		output.PrintCmdStatus(cmd, fmt.Sprintf("%v: %v\n", objJsonFilePath, describeTransforms(transforms, counts)))
+	}
+
+	if _, _, err := createObject(cmd, objType, objectStruct, objJsonFilePath, idempotencyKey); err != nil {
+		return err
+	}
+	log.Infof("Successfully created %s object", objType)
+	return nil
+}
+
+// createObject creates an object of the given type, in the layer specified by the command's flags
+// or by the object's target section, and returns its ID; objectFile is the source of the object,
+// used in messages. The idempotency key is sent with the request so that the platform can dedupe retries.
+// Also returns whether the object already existed (in all of its layers) and was merged into instead
+// (see --merge-existing).
+func createObject(cmd *cobra.Command, objType string, objectStruct map[string]interface{}, objectFile string, idempotencyKey string) (string, bool, error) {
+	objType, err := resolveObjectType(objType, objectStruct)
 	if err != nil {
-		log.Errorf("Can't generate a %s object from the %s file. Make sure the object definition has all the required field and is valid according to the type definition.")
-		return
+		return "", false, err
 	}
 
-	layerType, _ := cmd.Flags().GetString("layer-type")
-	layerID := getCorrectLayerID(layerType, objType)
+	// extract the target layer from the object file, if requested
+	var target targetLayer
+	if cmd.Flags().Changed("target-section") {
+		sectionName, _ := cmd.Flags().GetString("target-section")
+		target, err = extractTargetLayer(objectStruct, sectionName)
+		if err != nil {
+			return "", false, fmt.Errorf("Can't read the target layer from the %s file: %v", objectFile, err)
+		}
+	}
 
-	if layerID == "" {
-		if !cmd.Flags().Changed("layer-id") {
-			log.Error("Unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
-			return
+	layerType := target.LayerType
+	if cmd.Flags().Changed("layer-type") || layerType == "" {
+		layerType, _ = cmd.Flags().GetString("layer-type")
+	}
+	layerType = canonicalLayerType(layerType)
+	if layerType == "" {
+		return "", false, fmt.Errorf("Missing layer type. Please specify it with the --layer-type flag")
+	}
+
+	layerIDs, err := getLayerIDs(cmd, target, layerType, objType)
+	if err != nil {
+		return "", false, err
+	}
+	mergeExisting, _ := cmd.Flags().GetBool("merge-existing")
+	retrySafe := keyRetrySafe(cmd)
+	if len(layerIDs) == 1 {
+		id, merged, err := createOrMergeObject(apiClient(cmd), objType, objectStruct, layerType, layerIDs[0], idempotencyKey, retrySafe, mergeExisting)
+		if merged {
+			layerStatus(cmd, fmt.Sprintf("%v object %q already exists; merged into it\n", objType, id))
 		}
-		layerID, err = cmd.Flags().GetString("layer-id")
+		return id, merged, err
+	}
+
+	// create the object in each layer, reporting the outcome for each
+	failed, mergedLayers := 0, 0
+	objectID := ""
+	for _, layerID := range layerIDs {
+		id, merged, err := createOrMergeObject(apiClient(cmd), objType, objectStruct, layerType, layerID, idempotencyKey+"-"+layerID, retrySafe, mergeExisting)
 		if err != nil {
-			log.Errorf("error trying to get %q flag value: %w", "layer-id", err)
-			return
+			failed++
+			layerStatus(cmd, fmt.Sprintf("%v %v: failed: %v\n", layerType, layerID, err))
+			continue
+		}
+		objectID = id
+		if merged {
+			mergedLayers++
+			layerStatus(cmd, fmt.Sprintf("%v %v: merged into the existing object\n", layerType, layerID))
+		} else {
+			layerStatus(cmd, fmt.Sprintf("%v %v: created\n", layerType, layerID))
+		}
+	}
+	if failed > 0 {
+		return "", false, fmt.Errorf("%v of %v layers failed", failed, len(layerIDs))
+	}
+	return objectID, mergedLayers == len(layerIDs), nil
+}
+
+// layerStatus displays the outcome of creating an object in one of several layers,
+// unless the output is meant for automation
+func layerStatus(cmd *cobra.Command, message string) {
+	if isMachineFormat(cmd) {
+		log.Info(message)
+		return
+	}
+	output.PrintCmdStatus(cmd, message)
+}
+
+// getLayerIDs returns the layers to create the object in: the layer IDs specified with the
+// (repeatable) --layer-id flag, the layer ID from the object's target section or the
+// layer ID derived from the current context, in this order of precedence
+func getLayerIDs(cmd *cobra.Command, target targetLayer, layerType string, objType string) ([]string, error) {
+	if cmd.Flags().Changed("layer-id") {
+		layerIDs, err := cmd.Flags().GetStringArray("layer-id")
+		if err != nil {
+			return nil, fmt.Errorf("error trying to get %q flag value: %w", "layer-id", err)
+		}
+		for i, layerID := range layerIDs {
+			if layerIDs[i], err = renderLayerID(layerID, config.GetCurrentContext()); err != nil {
+				return nil, err
+			}
+			if err := checkTenantLayerID(cmd, layerType, layerIDs[i]); err != nil {
+				return nil, err
+			}
+		}
+		return layerIDs, nil
+	}
+	if target.LayerID != "" {
+		if err := checkTenantLayerID(cmd, layerType, target.LayerID); err != nil {
+			return nil, err
 		}
+		return []string{target.LayerID}, nil
 	}
+	layerID := getCorrectLayerID(layerType, objType)
+	if layerID == "" {
+		return nil, fmt.Errorf("Unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
+	}
+	return []string{layerID}, nil
+}
 
+// postObject sends the request to create the object in the given layer and returns the
+// ID of the created object, as returned by the platform or as specified in the object
+// The request is retried on any transient failure only if retrySafe is set (see keyRetrySafe).
+func postObject(client *api.Client, objType string, objectStruct map[string]interface{}, layerType string, layerID string, idempotencyKey string, retrySafe bool) (string, error) {
 	headers := map[string]string{
-		"layer-type": layerType,
-		"layer-id":   layerID,
+		"layer-type":             layerType,
+		"layer-id":               layerID,
+		api.IdempotencyKeyHeader: idempotencyKey,
 	}
+	log.Infof("Creating %s object in %s layer %q with idempotency key %q", objType, layerType, layerID, idempotencyKey)
 
 	var res any
-	// objJsonStr, err := json.Marshal(objectStruct)
-	err = api.JSONPost(getObjStoreObjectUrl()+"/"+objType, objectStruct, &res, &api.Options{Headers: headers})
+	err := client.JSONPost(getObjStoreObjectUrl()+"/"+objType, objectStruct, &res, &api.Options{Headers: headers, RetrySafe: retrySafe})
 	if err != nil {
-		log.Errorf("objstore command failed: %v", err.Error())
-		return
-	} else {
-		log.Infof("Successfully created %s object", objType)
+		return "", fmt.Errorf("objstore command failed: %w", err)
+	}
+	return createdObjectID(res, objectStruct), nil
+}
+
+// createdObjectID returns the ID of a created object from the platform's response,
+// falling back to the ID specified in the object, if any
+func createdObjectID(res any, objectStruct map[string]interface{}) string {
+	if resMap, ok := res.(map[string]any); ok {
+		if id, ok := resMap["id"].(string); ok && id != "" {
+			return id
+		}
+	}
+	id, _ := objectStruct["id"].(string)
+	return id
+}
+
+// insertObjectsFromDir creates an object from each object file in the directory,
+// skipping the files excluded by the directory's .fsocignore file. Each object is created
+// with an idempotency key derived from the base key and the object file's path.
+func insertObjectsFromDir(cmd *cobra.Command, objType string, dir string, transforms []transformRule, idempotencyKey string) error {
+	files, err := listObjectFiles(dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		log.Warnf("No object files found in %q", dir)
+		return nil
+	}
+
+	report := newBulkReport(cmd, objType)
+	for _, file := range files {
+		if cmdkit.Interrupted(cmd) {
+			break
+		}
+		transformInfo := ""
+		var objectID string
+		var merged bool
+		objectStruct, err := readObjectFile(file)
+		if err == nil {
+			if len(transforms) > 0 {
+				counts := applyTransforms(objectStruct, transforms)
+				transformInfo = fmt.Sprintf(" (%v)", describeTransforms(transforms, counts))
+			}
+			objectID, merged, err = createObject(cmd, objType, objectStruct, file, fileIdempotencyKey(idempotencyKey, dir, file))
+		}
+		report.add(file, objectID, merged, err, transformInfo)
+	}
+	return report.finish()
+}
+
+// targetLayer is the layer specification that can be embedded in an object file
+type targetLayer struct {
+	LayerType string `json:"layerType"`
+	LayerID   string `json:"layerId"`
+}
+
+// extractTargetLayer reads the target layer from the named top-level section of the
+// object and removes the section from the object, so that it is not sent to the server
+func extractTargetLayer(object map[string]interface{}, sectionName string) (targetLayer, error) {
+	var target targetLayer
+
+	section, found := object[sectionName]
+	if !found {
+		return target, nil
+	}
+	delete(object, sectionName)
+
+	sectionMap, ok := section.(map[string]interface{})
+	if !ok {
+		return target, fmt.Errorf("section %q must be an object, found %T instead", sectionName, section)
+	}
+	if v, ok := sectionMap["layerType"].(string); ok {
+		target.LayerType = v
 	}
+	if v, ok := sectionMap["layerId"].(string); ok {
+		target.LayerID = v
+	}
+
+	return target, nil
 }
 
 func getObjStoreObjectUrl() string {
-	return "objstore/v1beta/objects"
+	return config.GetObjStoreBasePath() + "/objects"
 }
 
 var objStoreInsertPatchedObjectCmd = &cobra.Command{
@@ -126,7 +389,17 @@ var objStoreInsertPatchedObjectCmd = &cobra.Command{
 
 
 	Usage:
-	fsoc objstore create-patch --type<fully-qualified-typename> --object-file=<fully-qualified-path> --target-layer-type=<valid-layer-type> --parent-object-id=<valid-object-id>`,
+	fsoc objstore create-patch --type<fully-qualified-typename> --object-file=<fully-qualified-path> [--target-layer-type=<valid-layer-type>] --parent-object-id=<valid-object-id>
+
+	Flags/Options:
+	--type - Flag to indicate the fully qualified type name of the object
+	--parent-object-id - Flag to indicate the ID of the parent object to patch at a lower layer
+	--object-file - Flag to indicate the path to the json or yaml file containing the patch, i.e., only the fields to change; all other fields are inherited from the parent object. Also available as --request-body-file
+	--fields-from-file - Same as --object-file, for use in scripts that apply repeatable partial edits
+	--target-layer-type - OPTIONAL Flag to indicate the layer at which the patched object will be created. If not specified, it is the only layer allowed by the type that is lower than the parent object's layer; the flag is required if there is more than one such layer
+	--check-parent - OPTIONAL Flag to verify that the parent object exists at a layer higher than the target layer before creating the patch (default true; use --check-parent=false to skip the check)
+
+	Before the patch is sent, its fields are checked against the type's JSON schema and the command fails, listing the offending fields, if any of them are immutable (readOnly). The check is skipped if the type's schema is not available.`,
 
 	Args:             cobra.ExactArgs(0),
 	Run:              insertPatchObject,
@@ -143,12 +416,21 @@ func getCreatePatchObjectCmd() *cobra.Command {
 	_ = objStoreInsertPatchedObjectCmd.MarkPersistentFlagRequired("parent-object-id")
 
 	objStoreInsertPatchedObjectCmd.Flags().
-		String("object-file", "", "The fully qualified path to the json file containing the object definition")
+		String("object-file", "", "The fully qualified path to the json or yaml file containing the object definition")
 	_ = objStoreInsertPatchedObjectCmd.MarkPersistentFlagRequired("objectFile")
 
 	objStoreInsertPatchedObjectCmd.Flags().
-		String("target-layer-type", "", "The layer-type at which the patch object will be created. For inheritance purposes, this should always be a `lower` layer than the parent object's layer")
-	_ = objStoreInsertPatchedObjectCmd.MarkPersistentFlagRequired("target-layer-type")
+		String("fields-from-file", "", "The path to a json or yaml file containing only the fields to change (same as --object-file)")
+
+	objStoreInsertPatchedObjectCmd.Flags().
+		String("target-layer-type", "", "The layer-type at which the patch object will be created. For inheritance purposes, this should always be a `lower` layer than the parent object's layer (default is the only such layer allowed by the type)")
+
+	objStoreInsertPatchedObjectCmd.Flags().
+		Bool("check-parent", true, "Verify that the parent object exists at a higher layer than the target layer before creating the patch")
+
+	objStoreInsertPatchedObjectCmd.MarkFlagsMutuallyExclusive("object-file", "fields-from-file")
+
+	useRequestBodyFileAlias(objStoreInsertPatchedObjectCmd)
 
 	return objStoreInsertPatchedObjectCmd
 }
@@ -157,23 +439,32 @@ func insertPatchObject(cmd *cobra.Command, args []string) {
 	objType, _ := cmd.Flags().GetString("type")
 	parentObjId, _ := cmd.Flags().GetString("parent-object-id")
 
-	objJsonFilePath, _ := cmd.Flags().GetString("object-file")
-	objectFile, err := os.Open(objJsonFilePath)
+	objJsonFilePath := objectFilePath(cmd, "object-file")
+	if cmd.Flags().Changed("fields-from-file") {
+		objJsonFilePath = objectFilePath(cmd, "fields-from-file")
+	}
+	warnLargeObjectFile(cmd, objJsonFilePath)
+	objectStruct, err := readObjectFile(objJsonFilePath)
 	if err != nil {
-		log.Errorf("Can't find the object definition file named %s", objJsonFilePath)
+		log.Errorf("Can't generate a %s object from the %s file: %v", objType, objJsonFilePath, err)
 		return
 	}
-	defer objectFile.Close()
 
-	objectBytes, _ := io.ReadAll(objectFile)
-	var objectStruct map[string]interface{}
-	err = json.Unmarshal(objectBytes, &objectStruct)
-	if err != nil {
-		log.Errorf("Can't generate a %s object from the %s file. Make sure the object definition has all the required fields and is valid according to the type definition.")
+	if err := validatePatchFields(apiClient(cmd), objType, objectStruct); err != nil {
+		log.Errorf("Can't create a patched %s object from the %s file: %v", objType, objJsonFilePath, err)
 		return
 	}
 
 	layerType, _ := cmd.Flags().GetString("target-layer-type")
+	layerType = canonicalLayerType(layerType)
+	if layerType == "" {
+		layerType, err = defaultPatchLayer(apiClient(cmd), objType, parentObjId)
+		if err != nil {
+			log.Errorf("Can't determine the target layer of the patched %s object: %v; please specify it with the --target-layer-type flag", objType, err)
+			return
+		}
+		log.Infof("Creating the patched %s object at the %s layer", objType, layerType)
+	}
 	layerID := getCorrectLayerID(layerType, objType)
 
 	headers := map[string]string{
@@ -181,8 +472,15 @@ func insertPatchObject(cmd *cobra.Command, args []string) {
 		"layer-id":   layerID,
 	}
 
+	if checkParent, _ := cmd.Flags().GetBool("check-parent"); checkParent {
+		if err := checkPatchParent(apiClient(cmd), objType, parentObjId, headers); err != nil {
+			log.Errorf("Can't create a patched %s object: %v", objType, err)
+			return
+		}
+	}
+
 	var res any
-	err = api.JSONPatch(getObjStoreObjectUrl()+"/"+objType+"/"+parentObjId, objectStruct, &res, &api.Options{Headers: headers})
+	err = apiClient(cmd).JSONPatch(getObjStoreObjectUrl()+"/"+objType+"/"+parentObjId, objectStruct, &res, &api.Options{Headers: headers})
 	if err != nil {
 		log.Errorf("Creating a patched object command failed: %v", err.Error())
 		return
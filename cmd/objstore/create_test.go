@@ -0,0 +1,95 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLayerIDs(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringArray("layer-id", nil, "")
+		require.Nil(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	// repeated flag
+	layerIDs, err := getLayerIDs(newCmd("--layer-id", "t1", "--layer-id", "t2"), targetLayer{LayerID: "t0"}, "TENANT", "preferences:theme")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"t1", "t2"}, layerIDs)
+
+	// single flag
+	layerIDs, err = getLayerIDs(newCmd("--layer-id", "t1"), targetLayer{}, "TENANT", "preferences:theme")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"t1"}, layerIDs)
+
+	// target section
+	layerIDs, err = getLayerIDs(newCmd(), targetLayer{LayerID: "t0"}, "TENANT", "preferences:theme")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"t0"}, layerIDs)
+}
+
+func TestExtractTargetLayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		object  map[string]interface{}
+		want    targetLayer
+		wantErr string
+	}{
+		{
+			name:   "missing section",
+			object: map[string]interface{}{"name": "dark"},
+			want:   targetLayer{},
+		},
+		{
+			name:   "id and layer",
+			object: map[string]interface{}{"name": "dark", "target": map[string]interface{}{"layerType": "TENANT", "layerId": "t1"}},
+			want:   targetLayer{LayerType: "TENANT", LayerID: "t1"},
+		},
+		{
+			name:   "no id or layer",
+			object: map[string]interface{}{"name": "dark", "target": map[string]interface{}{"other": "value"}},
+			want:   targetLayer{},
+		},
+		{
+			name:   "non-string id and layer",
+			object: map[string]interface{}{"name": "dark", "target": map[string]interface{}{"layerType": 1, "layerId": true}},
+			want:   targetLayer{},
+		},
+		{
+			name:    "non-map section",
+			object:  map[string]interface{}{"name": "dark", "target": "TENANT"},
+			wantErr: `section "target" must be an object, found string instead`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := extractTargetLayer(tt.object, "target")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.Nil(t, err)
+				assert.Equal(t, tt.want, target)
+			}
+			// the section is never sent to the server
+			assert.Equal(t, map[string]interface{}{"name": "dark"}, tt.object)
+		})
+	}
+}
@@ -15,11 +15,13 @@
 package objstore
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
 )
@@ -39,10 +41,13 @@ var objStoreDeleteCmd = &cobra.Command{
 	--type - Flag to indicate the fully qualified type name of the object that you would like to delete
 	--object-id - Flag to indicate the ID of the object which you would like to delete
 	--layer-type - Flag to indicate the layer at which the object you would like to delete currently exists
-	--layer-id - OPTIONAL Flag to specify a custom layer ID for the object that you would like to delete.  This is calculated automatically for all layers currently supported but can be overridden with this flag`,
+	--layer-id - OPTIONAL Flag to specify a custom layer ID for the object that you would like to delete.  This is calculated automatically for all layers currently supported but can be overridden with this flag
+	--filter - OPTIONAL Flag to delete all objects of the type that match a filter condition in SCIM filter format, instead of a single object
+	--dry-run - OPTIONAL Flag to display the objects that match the --filter condition without deleting them
+	--yes - OPTIONAL Flag to skip the confirmation prompt (required when not running interactively)`,
 
 	Args:             cobra.ExactArgs(0),
-	Run:              deleteObject,
+	RunE:             deleteObject,
 	TraverseChildren: true,
 }
 
@@ -62,27 +67,36 @@ func getDeleteObjectCmd() *cobra.Command {
 	objStoreDeleteCmd.Flags().
 		String("layer-id", "", "The layer-id of the updated object. Optional for TENANT and SOLUTION layers ")
 
+	objStoreDeleteCmd.Flags().
+		String("filter", "", "Filter condition in SCIM filter format for deleting all matching objects")
+
+	objStoreDeleteCmd.Flags().
+		Bool("dry-run", false, "Display the objects matching the filter without deleting them")
+
+	objStoreDeleteCmd.MarkFlagsMutuallyExclusive("object-id", "filter")
+
+	output.AddConfirmFlag(objStoreDeleteCmd)
+
 	return objStoreDeleteCmd
 
 }
 
-func deleteObject(cmd *cobra.Command, args []string) {
+func deleteObject(cmd *cobra.Command, args []string) error {
 	var err error
 
 	objType, _ := cmd.Flags().GetString("type")
 
 	layerType, _ := cmd.Flags().GetString("layer-type")
+	layerType = canonicalLayerType(layerType)
 	layerID := getCorrectLayerID(layerType, objType)
 
 	if layerID == "" {
 		if !cmd.Flags().Changed("layer-id") {
-			log.Error("Unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
-			return
+			return fmt.Errorf("Unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
 		}
-		layerID, err = cmd.Flags().GetString("layer-id")
+		layerID, err = getLayerIDFlag(cmd)
 		if err != nil {
-			log.Errorf("error trying to get %q flag value: %w", "layer-id", err)
-			return
+			return err
 		}
 	}
 
@@ -91,16 +105,119 @@ func deleteObject(cmd *cobra.Command, args []string) {
 		"layer-id":   layerID,
 	}
 
+	if cmd.Flags().Changed("filter") {
+		filter, _ := cmd.Flags().GetString("filter")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return deleteObjectsByFilter(cmd, objType, filter, headers, dryRun)
+	}
+
 	var res any
 	objId, _ := cmd.Flags().GetString("object-id")
 	urlStrf := getObjStoreObjectUrl() + "/%s/%s"
 	objectUrl := fmt.Sprintf(urlStrf, objType, objId)
 
+	if ok, err := output.Confirm(cmd, fmt.Sprintf("Delete object %s of type %s?", objId, objType)); err != nil {
+		return fmt.Errorf("Object not deleted: %w", err)
+	} else if !ok {
+		output.PrintCmdStatus(cmd, "Object deletion cancelled\n")
+		return nil
+	}
+
 	output.PrintCmdStatus(cmd, (fmt.Sprintf("Deleting object %s of type  %s \n", objId, objType)))
-	err = api.JSONDelete(objectUrl, &res, &api.Options{Headers: headers})
+	err = apiClient(cmd).JSONDelete(objectUrl, &res, &api.Options{Headers: headers})
 	if err != nil {
-		log.Errorf("Solution command failed: %v", err.Error())
-		return
+		return fmt.Errorf("Solution command failed: %v", err.Error())
 	}
 	output.PrintCmdStatus(cmd, "Object was successfully deleted!\n")
+	return nil
+}
+
+// deleteObjectsByFilter deletes all objects of a type that match the filter, after confirmation.
+// In dry run mode, it only displays the IDs of the matching objects.
+func deleteObjectsByFilter(cmd *cobra.Command, objType string, filter string, headers map[string]string, dryRun bool) error {
+	// list matching objects
+	var res any
+	options := api.Options{Headers: headers, QueryParams: map[string]string{"filter": filter}}
+	err := apiClient(cmd).JSONGetCollection(getObjectListUrl(objType), &res, &options)
+	if err != nil {
+		return fmt.Errorf("Failed to list objects of type %s: %v", objType, err)
+	}
+	ids, err := collectionObjectIDs(res)
+	if err != nil {
+		return fmt.Errorf("Failed to parse the list of objects of type %s: %v", objType, err)
+	}
+	if options.CollectionTruncated {
+		log.Warnf("Only the first %v matching objects will be deleted; use --max-items to raise the limit", len(ids))
+	}
+
+	if len(ids) == 0 {
+		output.PrintCmdStatus(cmd, fmt.Sprintf("No objects of type %s match the filter\n", objType))
+		return nil
+	}
+
+	if dryRun {
+		output.PrintCmdStatus(cmd, fmt.Sprintf("The following %v object(s) of type %s would be deleted:\n", len(ids), objType))
+		for _, id := range ids {
+			output.PrintCmdStatus(cmd, id+"\n")
+		}
+		return nil
+	}
+
+	if ok, err := output.Confirm(cmd, fmt.Sprintf("Delete %v object(s) of type %s?", len(ids), objType)); err != nil {
+		return fmt.Errorf("Objects not deleted: %w", err)
+	} else if !ok {
+		output.PrintCmdStatus(cmd, "Object deletion cancelled\n")
+		return nil
+	}
+
+	// delete objects one by one, continuing on failure
+	failed, deleted := 0, 0
+	for _, id := range ids {
+		if cmdkit.Interrupted(cmd) {
+			output.PrintCmdStatus(cmd, fmt.Sprintf("Deleted %v of %v object(s)\n", deleted, len(ids)))
+			return cmdkit.InterruptedError(cmd)
+		}
+		var res any
+		objectUrl := fmt.Sprintf(getObjStoreObjectUrl()+"/%s/%s", objType, id)
+		if err := apiClient(cmd).JSONDelete(objectUrl, &res, &api.Options{Headers: headers}); err != nil {
+			log.Errorf("Failed to delete object %s: %v", id, err)
+			failed++
+			continue
+		}
+		deleted++
+		output.PrintCmdStatus(cmd, fmt.Sprintf("Deleted object %s\n", id))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to delete %v of %v object(s)", failed, len(ids))
+	}
+	output.PrintCmdStatus(cmd, fmt.Sprintf("%v object(s) were successfully deleted!\n", len(ids)))
+	return nil
+}
+
+// collectionObjectIDs extracts the object IDs from a collection returned by api.JSONGetCollection.
+// Items without an ID are skipped with a warning, since they can't be addressed (e.g., deleted).
+func collectionObjectIDs(collection any) ([]string, error) {
+	data, err := json.Marshal(collection)
+	if err != nil {
+		return nil, err
+	}
+	var parsed struct {
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(parsed.Items))
+	for _, item := range parsed.Items {
+		if item.ID != "" {
+			ids = append(ids, item.ID)
+		}
+	}
+	if skipped := len(parsed.Items) - len(ids); skipped > 0 {
+		log.Warnf("Skipping %v object(s) without an ID in the list", skipped)
+	}
+	return ids, nil
 }
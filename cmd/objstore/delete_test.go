@@ -0,0 +1,87 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// newListPlatform starts a server that lists the given items for any object type and
+// records the paths of the objects deleted
+func newListPlatform(t *testing.T, items string) (*api.Client, *[]string) {
+	deleted := []string{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			if strings.Count(r.URL.Path, "/") > 4 { // a single object
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message": "not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"items": ` + items + `}`))
+		case http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+		}
+	})
+	return client, &deleted
+}
+
+func TestCollectionObjectIDsSkipsMissingIDs(t *testing.T) {
+	ids, err := collectionObjectIDs(map[string]any{"items": []any{
+		map[string]any{"id": "a"},
+		map[string]any{"data": map[string]any{}},
+		map[string]any{"id": ""},
+		map[string]any{"id": "b"},
+	}})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+}
+
+func TestDeleteObjectsByFilter(t *testing.T) {
+	client, deleted := newListPlatform(t, `[{"id": "a"}, {"data": {}}, {"id": "b"}]`)
+	cmd := &cobra.Command{}
+	output.AddConfirmFlag(cmd)
+	require.Nil(t, cmd.Flags().Set(output.ConfirmFlag, "true"))
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetContext(api.WithClient(context.Background(), client))
+
+	err := deleteObjectsByFilter(cmd, "preferences:theme", `data.color eq "blue"`, map[string]string{}, false)
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"/objstore/v1beta/objects/preferences:theme/a",
+		"/objstore/v1beta/objects/preferences:theme/b",
+	}, *deleted)
+}
+
+func TestBuildApplyPlanPrune(t *testing.T) {
+	client, _ := newListPlatform(t, `[{"id": "kept"}, {"id": "old"}, {"data": {}}]`)
+	desired := []desiredObject{{Type: "preferences:theme", ID: "kept", LayerType: "TENANT", LayerID: "t1"}}
+
+	plan, err := buildApplyPlan(client, desired, true)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]int{applyCreate: 1, applyDelete: 1}, countActions(plan))
+	assert.Equal(t, "old", plan[1].ID) // the object without an ID is not pruned
+}
@@ -0,0 +1,214 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// kinds of differences between objects
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+// diffEntry is a single difference between the stored object and the object file
+type diffEntry struct {
+	Path string `json:"path" yaml:"path"`
+	Op   string `json:"op" yaml:"op"`
+	Old  any    `json:"old,omitempty" yaml:"old,omitempty"`
+	New  any    `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+func newDiffCmd() *cobra.Command {
+	ltFlag := unknown
+
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare an object in the object store with an object file.",
+		Long: `Compare the data of an object in the object store with the content of a local object file,
+e.g., to review the changes before updating or patching the object.
+
+Each difference is displayed as a removed (-) and/or added (+) line with the dot-separated path of the field,
+colored red and green when the output is a terminal and the NO_COLOR environment variable is not set.
+Use --output json or --output yaml to get the list of differences in machine-readable form.`,
+		Example: `  # Review the changes in a theme before updating it
+  fsoc obj diff --type preferences:theme --object-id mytheme --layer-type TENANT --object-file mytheme.yaml
+
+  # Get the differences as JSON
+  fsoc obj diff --type preferences:theme --object-id mytheme --layer-type TENANT --object-file mytheme.yaml --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return diffObject(cmd, ltFlag)
+		},
+	}
+
+	diffCmd.Flags().String("type", "", "Fully qualified type name of the object")
+	_ = diffCmd.MarkFlagRequired("type")
+
+	diffCmd.Flags().String("object-id", "", "ID of the object to compare")
+	_ = diffCmd.MarkFlagRequired("object-id")
+
+	diffCmd.Flags().String("object-file", "", "The json or yaml file containing the object definition to compare with")
+	_ = diffCmd.MarkFlagRequired("object-file")
+
+	diffCmd.Flags().
+		Var(&ltFlag, "layer-type", fmt.Sprintf("Valid value: %q, %q, %q, %q, %q", solution, account, globalUser, tenant, localUser))
+	_ = diffCmd.MarkFlagRequired("layer-type")
+
+	diffCmd.Flags().String("layer-id", "", "Layer ID of the object. Optional for all layers except SOLUTION")
+
+	return diffCmd
+}
+
+func diffObject(cmd *cobra.Command, ltFlag layerType) error {
+	fqtn, _ := cmd.Flags().GetString("type")
+	objID, _ := cmd.Flags().GetString("object-id")
+	objectFile := objectFilePath(cmd, "object-file")
+
+	fileObject, err := readObjectFile(objectFile)
+	if err != nil {
+		return fmt.Errorf("Can't read the %s object from the %s file: %v", fqtn, objectFile, err)
+	}
+
+	layerType := string(ltFlag)
+	layerID, err := getLayerIDFlag(cmd)
+	if err != nil {
+		return err
+	}
+	if err := checkTenantLayerID(cmd, layerType, layerID); err != nil {
+		return err
+	}
+	if layerID == "" {
+		layerID = getCorrectLayerID(layerType, fqtn)
+	}
+	headers := map[string]string{
+		"layer-type": layerType,
+		"layer-id":   layerID,
+	}
+
+	var res map[string]any
+	if err := apiClient(cmd).JSONGet(getObjectUrl(fqtn, objID), &res, &api.Options{Headers: headers}); err != nil {
+		return fmt.Errorf("Failed to fetch object %q: %v", objID, err)
+	}
+
+	diffs := diffValues(res["data"], map[string]any(fileObject), "")
+
+	if format, _ := cmd.Flags().GetString("output"); format == "json" || format == "yaml" || format == "jsonl" {
+		output.PrintCmdOutput(cmd, diffs)
+		return nil
+	}
+	printDiff(cmd, diffs)
+	return nil
+}
+
+// diffValues returns the differences between the old and new values, descending into
+// objects so that each difference is reported with the path of the field. Arrays
+// are compared as a whole. The differences are sorted by path.
+func diffValues(oldValue any, newValue any, path string) []diffEntry {
+	oldMap, oldIsMap := oldValue.(map[string]any)
+	newMap, newIsMap := newValue.(map[string]any)
+	if !oldIsMap || !newIsMap {
+		if reflect.DeepEqual(normalizeValue(oldValue), normalizeValue(newValue)) {
+			return nil
+		}
+		return []diffEntry{{Path: path, Op: diffChanged, Old: oldValue, New: newValue}}
+	}
+
+	var diffs []diffEntry
+	for key, oldField := range oldMap {
+		fieldPath := joinPath(path, key)
+		if newField, found := newMap[key]; found {
+			diffs = append(diffs, diffValues(oldField, newField, fieldPath)...)
+		} else {
+			diffs = append(diffs, diffEntry{Path: fieldPath, Op: diffRemoved, Old: oldField})
+		}
+	}
+	for key, newField := range newMap {
+		if _, found := oldMap[key]; !found {
+			diffs = append(diffs, diffEntry{Path: joinPath(path, key), Op: diffAdded, New: newField})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// normalizeValue converts a value to its generic JSON form, so that values parsed
+// from JSON and YAML files (e.g., float64 vs int) compare as equal
+func normalizeValue(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return v
+	}
+	return normalized
+}
+
+// printDiff displays the differences in a format similar to git diff
+func printDiff(cmd *cobra.Command, diffs []diffEntry) {
+	if len(diffs) == 0 {
+		output.PrintCmdStatus(cmd, "No differences\n")
+		return
+	}
+
+	color := output.ColorEnabled(cmd)
+	line := func(prefix string, colorCode string, path string, value any) string {
+		s := fmt.Sprintf("%v %v: %v", prefix, path, formatDiffValue(value))
+		if color {
+			s = output.Colorize(colorCode, s)
+		}
+		return s + "\n"
+	}
+
+	var sb strings.Builder
+	for _, d := range diffs {
+		if d.Op != diffAdded {
+			sb.WriteString(line("-", output.ColorRed, d.Path, d.Old))
+		}
+		if d.Op != diffRemoved {
+			sb.WriteString(line("+", output.ColorGreen, d.Path, d.New))
+		}
+	}
+	output.PrintCmdStatus(cmd, sb.String())
+}
+
+func formatDiffValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffValues(t *testing.T) {
+	oldObj := map[string]any{
+		"name":   "theme",
+		"size":   float64(10),
+		"colors": map[string]any{"background": "white", "foreground": "black"},
+		"tags":   []any{"a", "b"},
+	}
+	newObj := map[string]any{
+		"name":   "theme",
+		"size":   10, // as parsed from YAML
+		"colors": map[string]any{"background": "green"},
+		"tags":   []any{"a", "b", "c"},
+		"font":   "mono",
+	}
+
+	assert.Equal(t, []diffEntry{
+		{Path: "colors.background", Op: diffChanged, Old: "white", New: "green"},
+		{Path: "colors.foreground", Op: diffRemoved, Old: "black"},
+		{Path: "font", Op: diffAdded, New: "mono"},
+		{Path: "tags", Op: diffChanged, Old: []any{"a", "b"}, New: []any{"a", "b", "c"}},
+	}, diffValues(oldObj, newObj, ""))
+
+	assert.Empty(t, diffValues(oldObj, oldObj, ""))
+}
+
+func TestPrintDiff(t *testing.T) {
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	printDiff(cmd, []diffEntry{
+		{Path: "colors.background", Op: diffChanged, Old: "white", New: "green"},
+		{Path: "font", Op: diffAdded, New: "mono"},
+	})
+	assert.Equal(t, "- colors.background: \"white\"\n+ colors.background: \"green\"\n+ font: \"mono\"\n", out.String())
+
+	out.Reset()
+	printDiff(cmd, nil)
+	assert.Equal(t, "No differences\n", out.String())
+}
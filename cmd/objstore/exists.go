@@ -0,0 +1,112 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// exit codes of the exists command
+const (
+	existsExitNotFound = 1
+	existsExitError    = 2
+)
+
+func newExistsCmd() *cobra.Command {
+	ltFlag := unknown
+
+	existsCmd := &cobra.Command{
+		Use:   "exists",
+		Short: "Check whether an object exists in the object store.",
+		Long: `Check whether an object exists in the object store, for use in scripts.
+
+The command displays nothing and exits with status 0 if the object exists, 1 if it does not exist
+and 2 if the check could not be completed (e.g., the platform could not be reached).
+With --verbose, the basic metadata of the found object is displayed.`,
+		Example: `  # Create a theme only if it doesn't exist yet
+  fsoc obj exists --type preferences:theme --object-id mytheme --layer-type TENANT || fsoc obj create --type preferences:theme --object-file mytheme.json --layer-type TENANT`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkObjectExists(cmd, ltFlag)
+		},
+	}
+
+	existsCmd.Flags().String("type", "", "Fully qualified type name of the object")
+	_ = existsCmd.MarkFlagRequired("type")
+
+	existsCmd.Flags().String("object-id", "", "ID of the object to check")
+	_ = existsCmd.MarkFlagRequired("object-id")
+
+	existsCmd.Flags().
+		Var(&ltFlag, "layer-type", fmt.Sprintf("Valid value: %q, %q, %q, %q, %q", solution, account, globalUser, tenant, localUser))
+	_ = existsCmd.MarkFlagRequired("layer-type")
+
+	existsCmd.Flags().String("layer-id", "", "Layer ID of the object. Optional for all layers except SOLUTION")
+
+	return existsCmd
+}
+
+func checkObjectExists(cmd *cobra.Command, ltFlag layerType) error {
+	fqtn, _ := cmd.Flags().GetString("type")
+	objID, _ := cmd.Flags().GetString("object-id")
+
+	layerType := string(ltFlag)
+	layerID, err := getLayerIDFlag(cmd)
+	if err != nil {
+		log.Errorf("%v", err)
+		return cmdkit.ExitWithCode(cmd, existsExitError)
+	}
+	if err := checkTenantLayerID(cmd, layerType, layerID); err != nil {
+		log.Errorf("%v", err)
+		return cmdkit.ExitWithCode(cmd, existsExitError)
+	}
+	if layerID == "" {
+		layerID = getCorrectLayerID(layerType, fqtn)
+	}
+
+	headers := map[string]string{
+		"layer-type": layerType,
+		"layer-id":   layerID,
+	}
+
+	var res map[string]any
+	err = apiClient(cmd).JSONGet(getObjectUrl(fqtn, objID), &res, &api.Options{Headers: headers})
+	if err != nil {
+		if api.IsNotFound(err) {
+			log.Infof("Object %q of type %q does not exist", objID, fqtn)
+			return cmdkit.ExitWithCode(cmd, existsExitNotFound)
+		}
+		log.Errorf("Failed to check whether the object exists: %v", err)
+		return cmdkit.ExitWithCode(cmd, existsExitError)
+	}
+
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		metadata := map[string]any{}
+		for _, field := range []string{"id", "layerType", "layerId", "createdAt", "updatedAt"} {
+			if value, ok := res[field]; ok {
+				metadata[field] = value
+			}
+		}
+		output.PrintCmdOutput(cmd, metadata)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func runExists(t *testing.T, status int) error {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id": "mytheme"}`))
+	})
+
+	cmd := newExistsCmd()
+	for flag, value := range map[string]string{"type": "preferences:theme", "object-id": "mytheme", "layer-type": "SOLUTION", "layer-id": "preferences"} {
+		require.Nil(t, cmd.Flags().Set(flag, value))
+	}
+	cmd.SetContext(api.WithClient(context.Background(), client))
+	return cmd.RunE(cmd, nil)
+}
+
+func TestObjectExistsExitCode(t *testing.T) {
+	assert.Nil(t, runExists(t, http.StatusOK))
+
+	var exitErr cmdkit.ExitCodeError
+	require.True(t, errors.As(runExists(t, http.StatusNotFound), &exitErr))
+	assert.Equal(t, existsExitNotFound, exitErr.Code)
+
+	require.True(t, errors.As(runExists(t, http.StatusInternalServerError), &exitErr))
+	assert.Equal(t, existsExitError, exitErr.Code)
+}
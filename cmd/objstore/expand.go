@@ -0,0 +1,160 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// expandSpec is a reference field to expand, parsed from an --expand value
+type expandSpec struct {
+	Path []string // path of the field within the object's data
+	Type string   // type of the referenced objects
+}
+
+// referenceFetcher fetches the referenced object of the given type and ID
+type referenceFetcher func(fqtn string, id string) (any, error)
+
+// parseExpandSpec parses an --expand value in the form <field>[=<type>], where the
+// field is a dot-separated path within the object's data; the referenced type
+// defaults to the type of the object
+func parseExpandSpec(value string, defaultType string) (expandSpec, error) {
+	field, fqtn, found := strings.Cut(value, "=")
+	if !found || fqtn == "" {
+		fqtn = defaultType
+	}
+	field = strings.TrimPrefix(field, "data.")
+	if field == "" {
+		return expandSpec{}, fmt.Errorf("invalid --expand value %q: missing field name", value)
+	}
+	return expandSpec{Path: strings.Split(field, "."), Type: fqtn}, nil
+}
+
+// expandReferences replaces the IDs in the reference fields of the object's data with the
+// referenced objects. References that cannot be fetched are replaced with the ID and the
+// reason, e.g., {"id": "x", "unresolved": "not found"}, instead of failing.
+func expandReferences(object any, specs []expandSpec, fetch referenceFetcher) {
+	objMap, ok := object.(map[string]any)
+	if !ok {
+		return
+	}
+	data, ok := objMap["data"].(map[string]any)
+	if !ok {
+		return
+	}
+
+	for _, spec := range specs {
+		parent := data
+		for _, key := range spec.Path[:len(spec.Path)-1] {
+			if parent, ok = parent[key].(map[string]any); !ok {
+				break
+			}
+		}
+		field := spec.Path[len(spec.Path)-1]
+		if parent == nil {
+			continue
+		}
+		value, found := parent[field]
+		if !found {
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			parent[field] = resolveReference(spec.Type, v, fetch)
+		case []any:
+			for i, item := range v {
+				if id, ok := item.(string); ok {
+					v[i] = resolveReference(spec.Type, id, fetch)
+				}
+			}
+		default:
+			log.Warnf("Field %q is not a reference (found %T instead of an ID); not expanded", strings.Join(spec.Path, "."), value)
+		}
+	}
+}
+
+func resolveReference(fqtn string, id string, fetch referenceFetcher) any {
+	obj, err := fetch(fqtn, id)
+	if err == nil {
+		return obj
+	}
+
+	reason := err.Error()
+	if api.IsNotFound(err) {
+		reason = "not found"
+	}
+	log.Warnf("Could not resolve reference to %s object %q: %v", fqtn, id, reason)
+	return map[string]any{"id": id, "unresolved": reason}
+}
+
+// newReferenceFetcher returns a fetcher that gets referenced objects from the given layer,
+// fetching each of them only once
+func newReferenceFetcher(client *api.Client, headers map[string]string) referenceFetcher {
+	type result struct {
+		obj any
+		err error
+	}
+	cache := map[string]result{}
+	return func(fqtn string, id string) (any, error) {
+		key := fqtn + "/" + id
+		if r, found := cache[key]; found {
+			return r.obj, r.err
+		}
+		var obj any
+		err := client.JSONGet(getObjectUrl(fqtn, id), &obj, &api.Options{Headers: headers})
+		cache[key] = result{obj, err}
+		return obj, err
+	}
+}
+
+// getExpandedObject fetches an object, or a list of objects, expands the requested
+// reference fields and displays the result
+func getExpandedObject(cmd *cobra.Command, objStoreUrl string, headers map[string]string, isCollection bool, specs []expandSpec) error {
+	fetch := newReferenceFetcher(apiClient(cmd), headers)
+
+	if !isCollection {
+		var res any
+		if err := apiClient(cmd).JSONGet(objStoreUrl, &res, &api.Options{Headers: headers}); err != nil {
+			log.Fatalf("Platform API call failed: %v", err)
+		}
+		expandReferences(res, specs, fetch)
+		output.PrintCmdOutput(cmd, res)
+		return nil
+	}
+
+	items := []any{}
+	options := api.Options{Headers: headers, ItemHandler: func(item any) error {
+		expandReferences(item, specs, fetch)
+		items = append(items, item)
+		return nil
+	}}
+	var res any
+	if err := apiClient(cmd).JSONGetCollection(objStoreUrl, &res, &options); err != nil {
+		log.Fatalf("Platform API call failed: %v", err)
+	}
+	if options.CollectionTruncated {
+		log.Warnf("Results truncated to %v items; use --max-items to raise the limit", api.GetMaxCollectionItems())
+	}
+	output.PrintCmdOutput(cmd, map[string]any{"items": items, "total": len(items)})
+	return nil
+}
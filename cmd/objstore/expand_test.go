@@ -0,0 +1,70 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpandSpec(t *testing.T) {
+	spec, err := parseExpandSpec("baseTheme", "preferences:theme")
+	require.Nil(t, err)
+	assert.Equal(t, expandSpec{Path: []string{"baseTheme"}, Type: "preferences:theme"}, spec)
+
+	spec, err = parseExpandSpec("data.icons.set=preferences:iconSet", "preferences:theme")
+	require.Nil(t, err)
+	assert.Equal(t, expandSpec{Path: []string{"icons", "set"}, Type: "preferences:iconSet"}, spec)
+
+	_, err = parseExpandSpec("=preferences:iconSet", "preferences:theme")
+	assert.NotNil(t, err)
+}
+
+func TestExpandReferences(t *testing.T) {
+	fetch := func(fqtn string, id string) (any, error) {
+		if id == "missing" {
+			return nil, fmt.Errorf("boom")
+		}
+		return map[string]any{"id": id, "type": fqtn}, nil
+	}
+
+	object := map[string]any{
+		"id": "mytheme",
+		"data": map[string]any{
+			"baseTheme": "dark",
+			"icons":     map[string]any{"sets": []any{"a", "missing"}},
+			"count":     3,
+		},
+	}
+	expandReferences(object, []expandSpec{
+		{Path: []string{"baseTheme"}, Type: "preferences:theme"},
+		{Path: []string{"icons", "sets"}, Type: "preferences:iconSet"},
+		{Path: []string{"count"}, Type: "preferences:theme"},
+		{Path: []string{"nosuchfield"}, Type: "preferences:theme"},
+		{Path: []string{"nosuch", "field"}, Type: "preferences:theme"},
+	}, fetch)
+
+	assert.Equal(t, map[string]any{
+		"baseTheme": map[string]any{"id": "dark", "type": "preferences:theme"},
+		"icons": map[string]any{"sets": []any{
+			map[string]any{"id": "a", "type": "preferences:iconSet"},
+			map[string]any{"id": "missing", "unresolved": "boom"},
+		}},
+		"count": 3,
+	}, object["data"])
+}
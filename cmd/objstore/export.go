@@ -0,0 +1,268 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// exportStateFile is the file in the export directory that lists the exported objects,
+// so that an interrupted export can be resumed
+const exportStateFile = ".fsoc-export-state"
+
+// errExportStopped stops the export when the command is interrupted
+var errExportStopped = errors.New("export stopped")
+
+// fileNameReplacer escapes the colons that url.PathEscape leaves in object IDs, since they
+// are not allowed in file names on Windows
+var fileNameReplacer = strings.NewReplacer(":", "%3A")
+
+func newExportCmd() *cobra.Command {
+	ltFlag := unknown
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export objects of a given type to a directory",
+		Long: `Export the objects of a given type in a layer to a directory, one json file per object.
+
+Each file contains the object's data and its type in the "$type" field, so that the objects can be
+created from the directory with "fsoc obj create --object-dir" (e.g., in another environment).
+
+The exported objects are recorded in the directory's ` + exportStateFile + ` file. If the export is
+interrupted or fails, run the same command with --resume to continue it: the objects that were already
+exported are skipped. Note that the list of objects is fetched again when resuming.`,
+		Example: `  # Export all themes of the tenant
+  fsoc obj export --type preferences:theme --layer-type TENANT --dir themes
+
+  # Continue an interrupted export
+  fsoc obj export --type preferences:theme --layer-type TENANT --dir themes --resume
+
+  # Import the exported themes into another environment
+  fsoc obj create --object-dir themes --layer-type TENANT --profile other`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportObjects(cmd, ltFlag)
+		},
+	}
+
+	exportCmd.Flags().String("type", "", "Fully qualified type name of the objects")
+	_ = exportCmd.MarkFlagRequired("type")
+
+	exportCmd.Flags().
+		Var(&ltFlag, "layer-type", fmt.Sprintf("Valid value: %q, %q, %q, %q, %q", solution, account, globalUser, tenant, localUser))
+	_ = exportCmd.MarkFlagRequired("layer-type")
+
+	exportCmd.Flags().String("layer-id", "", "Layer ID of the objects. Optional for all layers except SOLUTION")
+	exportCmd.Flags().String("filter", "", "Filter condition in SCIM filter format for selecting the objects to export")
+
+	exportCmd.Flags().String("dir", "", "Directory to export the objects to (created if it doesn't exist)")
+	_ = exportCmd.MarkFlagRequired("dir")
+
+	exportCmd.Flags().Bool("resume", false, "Continue a previous export to the directory, skipping the objects that were already exported")
+
+	return exportCmd
+}
+
+// exportState tracks the objects exported to a directory
+type exportState struct {
+	header   string          // identifies the export, so that a different export is not resumed
+	exported map[string]bool // IDs of the exported objects
+	file     *os.File
+}
+
+// exportStateHeader returns the first line of the state file, identifying the export
+func exportStateHeader(fqtn string, layerType string, layerID string, filter string) string {
+	return fmt.Sprintf("# fsoc export type=%s layer-type=%s layer-id=%s filter=%s", fqtn, layerType, layerID, url.QueryEscape(filter))
+}
+
+// openExportState opens the export state file in the directory. When resuming, the exported objects
+// are loaded from it; otherwise, a new state file is created, replacing any previous one.
+func openExportState(dir string, header string, resume bool) (*exportState, error) {
+	state := &exportState{header: header, exported: map[string]bool{}}
+	path := filepath.Join(dir, exportStateFile)
+
+	if resume {
+		file, err := os.Open(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read the export state: %v", err)
+		}
+		if err == nil {
+			scanner := bufio.NewScanner(file)
+			for lineNo := 1; scanner.Scan(); lineNo++ {
+				line := scanner.Text()
+				if lineNo == 1 {
+					if line != header {
+						file.Close()
+						return nil, fmt.Errorf("the directory contains a different export (%q); resume it with the same type, layer and filter or export to another directory", strings.TrimPrefix(line, "# fsoc export "))
+					}
+					continue
+				}
+				if line != "" {
+					state.exported[line] = true
+				}
+			}
+			file.Close()
+			if err := scanner.Err(); err != nil {
+				return nil, fmt.Errorf("failed to read the export state: %v", err)
+			}
+		}
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !resume {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the export state: %v", err)
+	}
+	state.file = file
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		if _, err := fmt.Fprintln(file, header); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to write the export state: %v", err)
+		}
+	}
+	return state, nil
+}
+
+// markExported records that the object has been exported
+func (s *exportState) markExported(id string) error {
+	s.exported[id] = true
+	_, err := fmt.Fprintln(s.file, id)
+	return err
+}
+
+func (s *exportState) close() {
+	s.file.Close()
+}
+
+// exportFileName returns the name of the file for the object with the given ID. The ID is
+// escaped rather than sanitized, so that different IDs (e.g., "a/b" and "a_b") never
+// share a file.
+func exportFileName(id string) string {
+	return fileNameReplacer.Replace(url.PathEscape(id)) + ".json"
+}
+
+// writeExportedObject writes the object's data and type to a json file in the directory
+func writeExportedObject(dir string, fqtn string, id string, data map[string]any) error {
+	object := map[string]any{}
+	for k, v := range data {
+		object[k] = v
+	}
+	object[objectTypeField] = fqtn
+
+	content, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, exportFileName(id)), append(content, '\n'), 0644)
+}
+
+func exportObjects(cmd *cobra.Command, ltFlag layerType) error {
+	fqtn, _ := cmd.Flags().GetString("type")
+	dir, _ := cmd.Flags().GetString("dir")
+	resume, _ := cmd.Flags().GetBool("resume")
+	filter, _ := cmd.Flags().GetString("filter")
+
+	layerType := string(ltFlag)
+	layerID, err := getLayerIDFlag(cmd)
+	if err != nil {
+		return err
+	}
+	if err := checkTenantLayerID(cmd, layerType, layerID); err != nil {
+		return err
+	}
+	if layerID == "" {
+		if layerType == "SOLUTION" {
+			return fmt.Errorf("Exporting objects from the SOLUTION layer requires the --layer-id flag")
+		}
+		layerID = getCorrectLayerID(layerType, fqtn)
+	}
+	headers := map[string]string{
+		"layer-type": layerType,
+		"layer-id":   layerID,
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("Failed to create the export directory: %v", err)
+	}
+	state, err := openExportState(dir, exportStateHeader(fqtn, layerType, layerID, filter), resume)
+	if err != nil {
+		return fmt.Errorf("Can't export to %q: %v", dir, err)
+	}
+	defer state.close()
+
+	listUrl := fqtn
+	if filter != "" {
+		listUrl += "?filter=" + url.QueryEscape(filter)
+	}
+
+	exported, skipped := 0, 0
+	options := api.Options{Headers: headers, ItemHandler: func(item any) error {
+		if cmdkit.Interrupted(cmd) {
+			return errExportStopped
+		}
+		obj, _ := item.(map[string]any)
+		id, _ := obj["id"].(string)
+		if id == "" {
+			return fmt.Errorf("object without an ID in the list: %v", item)
+		}
+		if state.exported[id] {
+			skipped++
+			return nil
+		}
+		data, _ := obj["data"].(map[string]any)
+		if err := writeExportedObject(dir, fqtn, id, data); err != nil {
+			return fmt.Errorf("failed to export object %q: %v", id, err)
+		}
+		exported++
+		return state.markExported(id)
+	}}
+	var res any
+	err = apiClient(cmd).JSONGetCollection(getObjectListUrl(listUrl), &res, &options)
+
+	message := fmt.Sprintf("Exported %v %s objects to %q", exported, fqtn, dir)
+	if skipped > 0 {
+		message += fmt.Sprintf(" (%v already exported)", skipped)
+	}
+	output.PrintCmdStatus(cmd, message+"\n")
+
+	if err != nil && !errors.Is(err, errExportStopped) {
+		return fmt.Errorf("Export failed: %v; run the command again with --resume to continue it", err)
+	}
+	if cmdkit.Interrupted(cmd) {
+		log.Warnf("Run the command again with --resume to continue the export")
+		return cmdkit.InterruptedError(cmd)
+	}
+	if options.CollectionTruncated {
+		log.Warnf("Export truncated to %v objects; use --max-items to raise the limit", api.GetMaxCollectionItems())
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportStateResume(t *testing.T) {
+	dir := t.TempDir()
+	header := exportStateHeader("preferences:theme", "TENANT", "tenant-1", "")
+
+	// a new export
+	state, err := openExportState(dir, header, false)
+	require.Nil(t, err)
+	require.Nil(t, state.markExported("a"))
+	require.Nil(t, state.markExported("b"))
+	state.close()
+
+	// resuming skips the exported objects and keeps recording
+	state, err = openExportState(dir, header, true)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]bool{"a": true, "b": true}, state.exported)
+	require.Nil(t, state.markExported("c"))
+	state.close()
+
+	state, err = openExportState(dir, header, true)
+	require.Nil(t, err)
+	assert.Len(t, state.exported, 3)
+	state.close()
+
+	// a different export can't be resumed
+	_, err = openExportState(dir, exportStateHeader("preferences:theme", "TENANT", "tenant-2", ""), true)
+	assert.NotNil(t, err)
+
+	// starting over forgets the exported objects
+	state, err = openExportState(dir, header, false)
+	require.Nil(t, err)
+	state.close()
+	state, err = openExportState(dir, header, true)
+	require.Nil(t, err)
+	assert.Empty(t, state.exported)
+	state.close()
+}
+
+func TestWriteExportedObject(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, writeExportedObject(dir, "preferences:theme", "team/dark:1", map[string]any{"name": "dark"}))
+
+	object, err := readObjectFile(filepath.Join(dir, "team%2Fdark%3A1.json"))
+	require.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"$type": "preferences:theme", "name": "dark"}, object)
+}
+
+func TestExportFileName(t *testing.T) {
+	assert.Equal(t, "mytheme.json", exportFileName("mytheme"))
+	names := map[string]bool{}
+	for _, id := range []string{"a/b", "a_b", "a:b", "a%2Fb", `a\b`, "a*b", "a?b", `a"b`, "a<b>", "a|b"} {
+		name := exportFileName(id)
+		assert.NotContains(t, name, "/")
+		assert.False(t, strings.ContainsAny(name, `\:*?"<>|`), name)
+		assert.False(t, names[name], "duplicate file name %q", name)
+		names[name] = true
+	}
+}
@@ -17,11 +17,18 @@ package objstore
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
+	"github.com/cisco-open/fsoc/cmd/config"
 	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
 )
 
 func newGetObjectCmd() *cobra.Command {
@@ -31,8 +38,8 @@ func newGetObjectCmd() *cobra.Command {
 	getCmd := &cobra.Command{
 		Use:     "get",
 		Short:   "Fetch an object or a list of objects from the object store.",
-		Aliases: []string{"g"},
-		Long:    `Fetch an object from object store using set of properties which can uniquely identify it.`,
+		Aliases: []string{"g", "list"},
+		Long:    `Fetch an object from object store using set of properties which can uniquely identify it, or a list of objects if no object ID is specified.`,
 		Example: `  # Get object [SERVICE principal]
   fsoc obj get --type=extensibility:solution --object=extensibility --layer-id=extensibility --layer-type=SOLUTION
   
@@ -42,8 +49,41 @@ func newGetObjectCmd() *cobra.Command {
   # Get list of solution objects that are system solutions
   fsoc obj get --type=extensibility:solution --layer-type=TENANT --filter="data.isSystem eq true"
 
+  # Save an object exactly as returned by the server
+  fsoc obj get --type preferences:theme --object mytheme --layer-type TENANT --raw --output-file mytheme.json
+
+  # Save an object as YAML for editing and re-submission with "fsoc obj update"
+  fsoc obj get --type preferences:theme --object mytheme --layer-type TENANT --output yaml > mytheme.yaml
+
+  # Get list of solution objects created in the last 24 hours
+  fsoc obj get --type=extensibility:solution --layer-type=TENANT --created-after=24h
+
+  # List the versions of an object and get one of them
+  fsoc obj get --type preferences:theme --object mytheme --layer-type TENANT --list-versions
+  fsoc obj get --type preferences:theme --object mytheme --layer-type TENANT --version 3
+
+  # Get an object with the objects referenced by its fields inlined
+  fsoc obj get --type preferences:theme --object mytheme --layer-type TENANT --expand baseTheme --expand data.iconSet=preferences:iconSet
+
+  # Show the graph of the objects referenced by an object's baseTheme field, transitively, as a tree or in DOT
+  fsoc obj get --type preferences:theme --object mytheme --layer-type TENANT --expand baseTheme --include-refs-graph
+  fsoc obj get --type preferences:theme --object mytheme --layer-type TENANT --expand baseTheme --include-refs-graph --graph-format dot --max-depth 5 | dot -Tsvg > refs.svg
+
+  # Show which layer each field of a patched object is resolved from
+  fsoc obj get --type preferences:theme --object mytheme --layer-type TENANT --trace-inheritance
+
+  # Get several objects by ID, fetched in parallel and displayed as a list
+  fsoc obj get --type preferences:theme --object-id mytheme --object-id yourtheme --layer-type TENANT
+  fsoc obj list --type preferences:theme --layer-type TENANT --ids-only | fsoc obj get --type preferences:theme --layer-type TENANT --ids-file -
+
+  # Get list of theme objects in JSON, together with their count, type and layer
+  fsoc obj get --type preferences:theme --layer-type TENANT --with-metadata --output json
+
   # Get list of objects filtering by a data field
   fsoc obj get --type preferences:theme --layer-type TENANT --filter "data.backgroundColor eq \"green\""
+
+  # Delete all theme objects matching a filter, one at a time (--yes is required when not prompting; see also "fsoc obj delete --filter")
+  fsoc obj list --type preferences:theme --layer-type TENANT --filter "data.backgroundColor eq \"green\"" --ids-only | xargs -I{} fsoc obj delete --type preferences:theme --object-id {} --layer-type TENANT --yes
   `,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -57,13 +97,42 @@ func newGetObjectCmd() *cobra.Command {
 	getCmd.PersistentFlags().
 		String("type", "", "Fully qualified type name. It will be formed by combining the solution which defined the type and the type name.")
 
-	getCmd.PersistentFlags().String("object", "", "Object ID to fetch.")
+	getCmd.PersistentFlags().StringArray("object", nil, "Object ID to fetch. Can be repeated to fetch several objects, which are displayed as a list")
+	getCmd.Flags().String("ids-file", "", "Fetch the objects whose IDs are listed in the file, one per line (\"-\" for stdin)")
+	getCmd.Flags().Int("concurrency", defaultBatchConcurrency, "Maximum number of objects fetched at the same time when fetching several objects; reduced automatically while the server is throttling requests")
 	getCmd.PersistentFlags().String("layer-id", "", "Layer ID object belongs to.")
 
 	getCmd.Flags().
 		Var(&ltFlag, "layer-type", fmt.Sprintf("Valid value: %q, %q, %q, %q, %q", solution, account, globalUser, tenant, localUser))
 
 	getCmd.PersistentFlags().String("filter", "", "Filter condition in SCIM filter format for getting objects")
+	getCmd.Flags().String("created-after", "", "List only objects created after the given ISO 8601 timestamp (e.g., RFC 3339) or within the given duration (e.g., 24h)")
+	getCmd.Flags().String("created-before", "", "List only objects created before the given ISO 8601 timestamp (e.g., RFC 3339) or earlier than the given duration ago (e.g., 1h)")
+	getCmd.Flags().Bool("raw", false, "Display the response body exactly as returned by the server, without formatting (lists are not paginated)")
+	getCmd.Flags().String("output-file", "", "Write the raw response body to a file instead of displaying it (requires --raw)")
+	getCmd.Flags().String("version", "", "Fetch the given historical version of the object (requires --object and a versioned type)")
+	getCmd.Flags().Bool("list-versions", false, "List the available versions of the object (requires --object and a versioned type)")
+	getCmd.Flags().StringArray("expand", nil, "Inline the objects referenced by a field of the object's data, given as <field>[=<type>] (the type defaults to the object's type). Can be repeated; references that cannot be fetched are marked unresolved")
+	getCmd.Flags().Bool("with-metadata", false, "Wrap a list of objects in an envelope with the count, type and layer of the objects, the total reported by the server, the page size and whether the list was truncated (for json and yaml output)")
+	getCmd.Flags().Bool("ids-only", false, "Display only the IDs of the listed objects, one per line (e.g., for piping into xargs)")
+	getCmd.Flags().Bool("trace-inheritance", false, "Display each field of the object with the layer its value is resolved from, fetching the object as seen from each higher layer (requires --object)")
+	getCmd.Flags().Bool("include-refs-graph", false, "Display the graph of the objects referenced by the object, following the --expand reference fields transitively (requires --object and --expand)")
+	getCmd.Flags().Int("max-depth", defaultRefsMaxDepth, "Maximum number of references followed from the object (with --include-refs-graph)")
+	getCmd.Flags().String("graph-format", "tree", "Format of the references graph with the table output: tree or dot (with --include-refs-graph; use --output json or yaml for the nodes and edges)")
+	getCmd.MarkFlagsMutuallyExclusive("version", "list-versions")
+	getCmd.MarkFlagsMutuallyExclusive("trace-inheritance", "version", "list-versions", "expand", "raw", "ids-only", "with-metadata")
+	getCmd.MarkFlagsMutuallyExclusive("ids-only", "raw", "expand", "with-metadata")
+	getCmd.MarkFlagsMutuallyExclusive("expand", "raw")
+	getCmd.MarkFlagsMutuallyExclusive("ids-file", "filter")
+	getCmd.MarkFlagsMutuallyExclusive("include-refs-graph", "trace-inheritance", "version", "list-versions", "raw", "ids-only", "with-metadata")
+
+	// accept --object-id, as used by the other object commands
+	getCmd.SetGlobalNormalizationFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "object-id" {
+			name = "object"
+		}
+		return pflag.NormalizedName(name)
+	})
 	_ = getCmd.MarkPersistentFlagRequired("type")
 	// _ = getCmd.MarkPersistentFlagRequired("object")
 	//_ = getCmd.MarkPersistentFlagRequired("layer-id")
@@ -79,10 +148,16 @@ func newGetTypeCmd() *cobra.Command {
 	getTypeCmd := &cobra.Command{
 		Use:     "get-type",
 		Short:   "Fetch type from object store.",
-		Aliases: []string{"gt"},
-		Long:    `Fetch type from object store using type name`,
+		Aliases: []string{"gt", "describe-type"},
+		Long: `Fetch type from object store using type name.
+
+The type definition, including the full type name and its JSON schema, is displayed as is;
+use --output json or --output yaml to get the complete definition in machine-readable form.`,
 		Example: `# Get type by using fully qualified type name
-  fsoc obj get-type --type extensibility:solution`,
+  fsoc obj get-type --type extensibility:solution
+
+  # Get type definition and schema as JSON
+  fsoc obj describe-type --type extensibility:solution --output json`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return getType(cmd, args)
@@ -94,12 +169,34 @@ func newGetTypeCmd() *cobra.Command {
 	getTypeCmd.PersistentFlags().
 		String("type", "", "Fully qualified type name. It will be formed by combining the solution which defined the type and the type name.")
 
+	getTypeCmd.Flags().
+		Bool("refresh", false, "Ignore the locally cached copy of the type and fetch it from the server")
+
 	// only get type by fqtn is supported.
 	_ = getTypeCmd.MarkPersistentFlagRequired("type")
 
 	return getTypeCmd
 }
 
+func newClearTypeCacheCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear-type-cache",
+		Short: "Clear the local cache of type definitions.",
+		Long: `Clear the local cache of type definitions.
+
+Type definitions fetched by get-type are cached locally and revalidated with the server on each use.
+Clearing the cache forces all types to be fetched again.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := clearTypeCache(); err != nil {
+				return err
+			}
+			output.PrintCmdStatus(cmd, "Type cache cleared\n")
+			return nil
+		},
+	}
+}
+
 func getType(cmd *cobra.Command, args []string) error {
 	log.Info("Fetching type...")
 
@@ -108,8 +205,14 @@ func getType(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error trying to get %q flag value: %w", "type", err)
 	}
 
-	// execute command and print result
-	cmdkit.FetchAndPrint(cmd, getTypeUrl(fqtn), nil)
+	refresh, _ := cmd.Flags().GetBool("refresh")
+
+	// fetch type (possibly from cache) and print result
+	res, err := fetchType(apiClient(cmd), fqtn, refresh)
+	if err != nil {
+		log.Fatalf("Platform API call failed: %v", err)
+	}
+	output.PrintCmdOutput(cmd, res)
 	return nil
 }
 
@@ -121,13 +224,34 @@ func getObject(cmd *cobra.Command, args []string, ltFlag layerType) error {
 		return fmt.Errorf("error trying to get %q flag value: %w", "type", err)
 	}
 
-	objID, err := cmd.Flags().GetString("object")
+	objIDs, err := cmd.Flags().GetStringArray("object")
 	if err != nil {
 		return fmt.Errorf("error trying to get %q flag value: %w", "object", err)
 	}
+	if idsFile := objectFilePath(cmd, "ids-file"); idsFile != "" {
+		ids, err := readObjectIDs(cmd, idsFile)
+		if err != nil {
+			return err
+		}
+		objIDs = append(objIDs, ids...)
+		if len(objIDs) == 0 {
+			return fmt.Errorf("no object IDs found in %q", idsFile)
+		}
+	}
+	batch := len(objIDs) > 1 || cmd.Flags().Changed("ids-file")
+	objID := ""
+	if len(objIDs) > 0 {
+		objID = objIDs[0]
+	}
 
 	var layerType string = string(ltFlag)
-	layerID, _ := cmd.Flags().GetString("layer-id")
+	layerID, err := getLayerIDFlag(cmd)
+	if err != nil {
+		return err
+	}
+	if err := checkTenantLayerID(cmd, layerType, layerID); err != nil {
+		return err
+	}
 	if layerID == "" {
 		if layerType == "SOLUTION" {
 			return fmt.Errorf("Error: for GET requests made to the SOLUTION layer, please manually supply the layerId flag")
@@ -141,37 +265,163 @@ func getObject(cmd *cobra.Command, args []string, ltFlag layerType) error {
 		"layer-id":   layerID,
 	}
 
+	// fetch several objects, if requested
+	if batch {
+		for _, flag := range []string{"version", "list-versions", "expand", "raw", "ids-only", "with-metadata", "trace-inheritance", "include-refs-graph"} {
+			if cmd.Flags().Changed(flag) {
+				return fmt.Errorf("--%v cannot be used when fetching several objects", flag)
+			}
+		}
+		return getObjectBatch(cmd, fqtn, objIDs, headers, objectListInfo{Type: fqtn, Layer: layerType, LayerID: layerID})
+	}
+
+	// show the layer of each field, if requested
+	if traceInheritance, _ := cmd.Flags().GetBool("trace-inheritance"); traceInheritance {
+		if objID == "" {
+			return fmt.Errorf("--trace-inheritance requires the --object flag")
+		}
+		return getInheritanceTrace(cmd, fqtn, objID, layerType, layerID)
+	}
+
+	// fetch versions of the object, if requested
+	objVersion, _ := cmd.Flags().GetString("version")
+	listVersions, _ := cmd.Flags().GetBool("list-versions")
+	if objVersion != "" || listVersions {
+		if objID == "" {
+			return fmt.Errorf("--version and --list-versions require the --object flag")
+		}
+		return getObjectVersions(cmd, fqtn, objID, objVersion, headers)
+	}
+
 	// execute command and print output
+	objType := fqtn // fqtn may get a query string appended below
+	filtered := false
 	var objStoreUrl string
 	if objID != "" {
 		objStoreUrl = getObjectUrl(fqtn, objID)
 	} else {
-		if cmd.Flags().Changed("filter") {
-			filterCriteria, err := cmd.Flags().GetString("filter")
+		filterCriteria, err := cmd.Flags().GetString("filter")
+		if err != nil {
+			log.Errorf("error trying to get %q flag value: %w", "filter", err)
+			return nil
+		}
+		createdAfter, _ := cmd.Flags().GetString("created-after")
+		createdBefore, _ := cmd.Flags().GetString("created-before")
+		timeFilter, err := buildCreatedAtFilter(createdAfter, createdBefore, time.Now())
+		if err != nil {
+			return err
+		}
+		filterCriteria = combineFilters(filterCriteria, timeFilter)
+		if filterCriteria != "" {
+			filtered = true
 			query := fmt.Sprintf("filter=%s", url.QueryEscape(filterCriteria))
-			if err != nil {
-				log.Errorf("error trying to get %q flag value: %w", "filter", err)
-				return nil
-			}
 			fqtn = fqtn + "?" + query
 		}
 		objStoreUrl = getObjectListUrl(fqtn)
 	}
 
-	cmdkit.FetchAndPrint(cmd, objStoreUrl, &cmdkit.FetchAndPrintOptions{Headers: headers})
+	if idsOnly, _ := cmd.Flags().GetBool("ids-only"); idsOnly {
+		if objID != "" {
+			return fmt.Errorf("--ids-only can only be used when listing objects, without --object")
+		}
+		return getObjectIDs(cmd, objStoreUrl, headers)
+	}
+
+	refsGraph, _ := cmd.Flags().GetBool("include-refs-graph")
+	if refsGraph && (objID == "" || !cmd.Flags().Changed("expand")) {
+		return fmt.Errorf("--include-refs-graph requires the --object flag and the reference fields to follow, given with --expand")
+	}
+	if cmd.Flags().Changed("expand") {
+		expandValues, _ := cmd.Flags().GetStringArray("expand")
+		specs := make([]expandSpec, 0, len(expandValues))
+		for _, value := range expandValues {
+			spec, err := parseExpandSpec(value, objType)
+			if err != nil {
+				return err
+			}
+			specs = append(specs, spec)
+		}
+		if refsGraph {
+			return getRefsGraph(cmd, objType, objID, headers, specs)
+		}
+		return getExpandedObject(cmd, objStoreUrl, headers, objID == "", specs)
+	}
+
+	if raw, _ := cmd.Flags().GetBool("raw"); raw {
+		outputFile, _ := cmd.Flags().GetString("output-file")
+		return getRawObject(cmd, objStoreUrl, headers, outputFile)
+	}
+	if cmd.Flags().Changed("output-file") {
+		return fmt.Errorf("--output-file can only be used together with --raw")
+	}
+
+	if format, _ := cmd.Flags().GetString("output"); format == "yaml" && objID != "" {
+		return getYamlObject(cmd, objStoreUrl, headers)
+	}
+
+	withMetadata, _ := cmd.Flags().GetBool("with-metadata")
+	if objID == "" {
+		format, _ := cmd.Flags().GetString("output")
+		if format != "jsonl" {
+			return getObjectList(cmd, objStoreUrl, headers, objectListInfo{Type: objType, Layer: layerType, LayerID: layerID, Filtered: filtered}, withMetadata)
+		} else if withMetadata {
+			return fmt.Errorf("--with-metadata cannot be used with the jsonl output format")
+		}
+	}
+
+	cmdkit.FetchAndPrint(cmd, objStoreUrl, &cmdkit.FetchAndPrintOptions{Headers: headers, IsCollection: objID == ""})
 	return nil
 }
 
+// getRawObject fetches the object(s) and writes the response body verbatim
+// to the command's output or to the output file, if specified
+func getRawObject(cmd *cobra.Command, objStoreUrl string, headers map[string]string, outputFile string) error {
+	var body []byte
+	if err := apiClient(cmd).JSONGet(objStoreUrl, &body, &api.Options{Headers: headers}); err != nil {
+		log.Fatalf("Platform API call failed: %v", err)
+	}
+
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, body, 0644); err != nil {
+			return fmt.Errorf("failed to write the response to %q: %w", outputFile, err)
+		}
+		log.Infof("Response written to %q", outputFile)
+		return nil
+	}
+
+	_, err := output.GetOutWriter(cmd).Write(body)
+	return err
+}
+
+// getYamlObject fetches a single object and displays it as YAML, preserving
+// integer values so that the output can be edited and submitted with update
+func getYamlObject(cmd *cobra.Command, objStoreUrl string, headers map[string]string) error {
+	var body []byte
+	if err := apiClient(cmd).JSONGet(objStoreUrl, &body, &api.Options{Headers: headers}); err != nil {
+		log.Fatalf("Platform API call failed: %v", err)
+	}
+
+	obj, err := decodeJSONPreservingNumbers(body)
+	if err != nil {
+		return fmt.Errorf("failed to parse the object returned by the server: %w", err)
+	}
+	return output.PrintYaml(cmd, obj)
+}
+
+func getTypeListUrl() string {
+	return fmt.Sprintf("%s/types", config.GetObjStoreBasePath())
+}
+
 func getTypeUrl(fqtn string) string {
-	return fmt.Sprintf("objstore/v1beta/types/%s", fqtn)
+	return fmt.Sprintf("%s/types/%s", config.GetObjStoreBasePath(), fqtn)
 }
 
 func getObjectUrl(fqtn, objId string) string {
-	return fmt.Sprintf("objstore/v1beta/objects/%s/%s", fqtn, objId)
+	return fmt.Sprintf("%s/objects/%s/%s", config.GetObjStoreBasePath(), fqtn, objId)
 }
 
 func getObjectListUrl(fqtn string) string {
-	return fmt.Sprintf("objstore/v1beta/objects/%s", fqtn)
+	return fmt.Sprintf("%s/objects/%s", config.GetObjStoreBasePath(), fqtn)
 }
 
 type layerType string
@@ -189,7 +439,19 @@ func (e *layerType) String() string {
 	return string(*e)
 }
 
+// canonicalLayerType returns the known layer type in its canonical (upper) case, so that
+// layer types can be specified in any case; unknown layer types are returned unchanged
+func canonicalLayerType(v string) string {
+	for _, l := range layerOrder {
+		if strings.EqualFold(v, string(l)) {
+			return string(l)
+		}
+	}
+	return v
+}
+
 func (e *layerType) Set(v string) error {
+	v = canonicalLayerType(v)
 	switch v {
 	case string(solution), string(account), string(globalUser), string(tenant), string(localUser):
 		*e = layerType(v)
@@ -0,0 +1,126 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// defaultBatchConcurrency is the default number of objects fetched at the same time in batch mode
+const defaultBatchConcurrency = 8
+
+// readObjectIDs reads object IDs, one per line, ignoring blank lines and lines starting with #.
+// The file name "-" stands for the command's standard input.
+func readObjectIDs(cmd *cobra.Command, fileName string) ([]string, error) {
+	var r io.Reader
+	if fileName == "-" {
+		r = cmd.InOrStdin()
+	} else {
+		file, err := os.Open(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the IDs file: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+	return parseObjectIDs(r)
+}
+
+func parseObjectIDs(r io.Reader) ([]string, error) {
+	ids := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the object IDs: %w", err)
+	}
+	return ids, nil
+}
+
+// fetchObjects fetches the objects with the given IDs, running up to concurrency fetches at
+// the same time; fewer fetches run while the server is throttling them. The objects are
+// returned in the order of the IDs; an object that could not be fetched is replaced by an
+// error row with its ID and the error. Returns the number of failures.
+func fetchObjects(ids []string, concurrency int, fetch func(id string) (any, error)) ([]any, int) {
+	items := make([]any, len(ids))
+	errs := make([]error, len(ids))
+	limiter := newConcurrencyLimiter(concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		if i == 0 {
+			// fetch the first object alone, so that an expired token is refreshed (which may
+			// require a browser login) before the other objects are fetched concurrently
+			items[i], errs[i] = fetch(id)
+			continue
+		}
+		wg.Add(1)
+		generation := limiter.acquire()
+		go func(i int, id string) {
+			defer wg.Done()
+			items[i], errs[i] = fetch(id)
+			limiter.release(generation, errs[i])
+		}(i, id)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			items[i] = map[string]any{"id": ids[i], "error": err.Error()}
+		}
+	}
+	return items, failed
+}
+
+// getObjectBatch fetches the objects with the given IDs and displays them as a list,
+// with error rows for the objects that could not be fetched
+func getObjectBatch(cmd *cobra.Command, fqtn string, ids []string, headers map[string]string, info objectListInfo) error {
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	client := apiClient(cmd)
+	output.EnableConcurrentOutput(cmd)
+	typeUrl := getObjectListUrl(fqtn) + "/" // nb: the config is not read by the concurrent fetches
+	items, failed := fetchObjects(ids, concurrency, func(id string) (any, error) {
+		var obj any
+		err := client.JSONGet(typeUrl+id, &obj, &api.Options{Headers: headers})
+		return obj, err
+	})
+
+	footer := objectListSummary(len(items)-failed, info)
+	if failed > 0 {
+		footer += fmt.Sprintf("; %v could not be fetched", failed)
+	}
+	output.PrintCmdOutputCustom(cmd, map[string]any{"items": items, "total": len(items)}, &output.Table{Footer: footer})
+
+	if failed > 0 {
+		return fmt.Errorf("%v of %v objects could not be fetched", failed, len(ids))
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func TestParseObjectIDs(t *testing.T) {
+	ids, err := parseObjectIDs(strings.NewReader("theme-1\n\n# comment\n  theme-2  \n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"theme-1", "theme-2"}, ids)
+}
+
+func TestFetchObjects(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning, runningWithFirst := 0, 0, 0
+	fetch := func(id string) (any, error) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		if id == "a" {
+			runningWithFirst = running
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		if id == "missing" {
+			return nil, errors.New("not found")
+		}
+		return map[string]any{"id": id}, nil
+	}
+
+	ids := []string{"a", "missing", "b", "c", "d", "e"}
+	items, failed := fetchObjects(ids, 2, fetch)
+
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, []any{
+		map[string]any{"id": "a"},
+		map[string]any{"id": "missing", "error": "not found"},
+		map[string]any{"id": "b"},
+		map[string]any{"id": "c"},
+		map[string]any{"id": "d"},
+		map[string]any{"id": "e"},
+	}, items)
+	assert.True(t, maxRunning <= 2)
+	assert.Equal(t, 1, runningWithFirst, "the first object is fetched alone")
+}
+
+func TestGetObjectBatch(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "` + r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:] + `"}`))
+	})
+	cmd := &cobra.Command{}
+	cmd.Flags().Int("concurrency", 4, "")
+	cmd.Flags().String("output", "json", "")
+	cmd.Flags().String("fields", "", "")
+	var out strings.Builder
+	cmd.SetOut(&out)
+	cmd.SetContext(api.WithClient(context.Background(), client))
+
+	err := getObjectBatch(cmd, "preferences:theme", []string{"a", "missing", "b", "c"}, nil, objectListInfo{Type: "preferences:theme"})
+	assert.ErrorContains(t, err, "1 of 4 objects could not be fetched")
+
+	// the output writers are safe for the concurrent fetches
+	_, ok := cmd.OutOrStdout().(*output.SyncWriter)
+	require.True(t, ok)
+	_, ok = cmd.ErrOrStderr().(*output.SyncWriter)
+	require.True(t, ok)
+	assert.Contains(t, out.String(), `{"id":"c"}`)
+	assert.Contains(t, out.String(), `"id":"missing"`)
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"crypto/rand"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// newIdempotencyKey generates a random (version 4) UUID to use as an idempotency key
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate an idempotency key: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// getIdempotencyKey returns the idempotency key specified with the --idempotency-key flag
+// or, if not specified, a newly generated one for this invocation. Only a key specified by
+// the user makes the create requests safe to retry (see keyRetrySafe).
+func getIdempotencyKey(cmd *cobra.Command) (string, error) {
+	if key, _ := cmd.Flags().GetString("idempotency-key"); key != "" {
+		return key, nil
+	}
+	return newIdempotencyKey()
+}
+
+// keyRetrySafe returns true if the user specified the idempotency key, vouching that the platform
+// honors it, so that create requests can be retried on any transient failure. A generated key is
+// still sent, but retrying with it could create duplicates if the platform ignores it.
+func keyRetrySafe(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("idempotency-key")
+}
+
+// fileIdempotencyKey derives the idempotency key for an object created from a file in a
+// directory, so that each object gets a distinct key that is stable across invocations
+// with the same base key
+func fileIdempotencyKey(baseKey string, dir string, file string) string {
+	rel, err := filepath.Rel(dir, file)
+	if err != nil {
+		rel = file
+	}
+	return baseKey + "-" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestGetIdempotencyKey(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("idempotency-key", "", "")
+
+	// generated keys are UUIDs, unique per invocation
+	key1, err := getIdempotencyKey(cmd)
+	require.Nil(t, err)
+	assert.True(t, uuidPattern.MatchString(key1), "not a UUID: %q", key1)
+	key2, err := getIdempotencyKey(cmd)
+	require.Nil(t, err)
+	assert.NotEqual(t, key1, key2)
+
+	// user-supplied key is used as is
+	require.Nil(t, cmd.Flags().Set("idempotency-key", "my-key"))
+	key, err := getIdempotencyKey(cmd)
+	require.Nil(t, err)
+	assert.Equal(t, "my-key", key)
+}
+
+func TestKeyRetrySafe(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("idempotency-key", "", "")
+	assert.False(t, keyRetrySafe(cmd)) // generated keys may not be honored
+
+	require.Nil(t, cmd.Flags().Set("idempotency-key", "my-key"))
+	assert.True(t, keyRetrySafe(cmd))
+}
+
+func TestFileIdempotencyKey(t *testing.T) {
+	dir := filepath.Join("objects")
+	assert.Equal(t, "my-key-a.json", fileIdempotencyKey("my-key", dir, filepath.Join(dir, "a.json")))
+	assert.Equal(t, "my-key-sub/b.yaml", fileIdempotencyKey("my-key", dir, filepath.Join(dir, "sub", "b.yaml")))
+}
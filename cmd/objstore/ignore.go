@@ -0,0 +1,211 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreFileName is the name of the file listing the files to skip in bulk operations
+const ignoreFileName = ".fsocignore"
+
+// ignoreRule is a single pattern of an ignore file, following the gitignore semantics
+type ignoreRule struct {
+	pattern  *regexp.Regexp
+	negate   bool // pattern starts with "!": re-include matching files
+	dirOnly  bool // pattern ends with "/": match only directories
+	anchored bool // pattern contains a "/": match against the full relative path rather than the name
+}
+
+type ignoreRules []ignoreRule
+
+// loadIgnoreFile reads the .fsocignore file in the directory, if present.
+// A missing file results in no rules.
+func loadIgnoreFile(dir string) (ignoreRules, error) {
+	file, err := os.Open(filepath.Join(dir, ignoreFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read the %v file: %v", ignoreFileName, err)
+	}
+	defer file.Close()
+
+	rules := ignoreRules{}
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		rule, ok, err := parseIgnoreRule(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern at %v line %v: %v", ignoreFileName, lineNo, err)
+		}
+		if ok {
+			rules = append(rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read the %v file: %v", ignoreFileName, err)
+	}
+	return rules, nil
+}
+
+// parseIgnoreRule parses a line of an ignore file; returns false if the line has no pattern
+func parseIgnoreRule(line string) (ignoreRule, bool, error) {
+	var rule ignoreRule
+
+	// skip blank lines and comments; trailing spaces are ignored unless escaped
+	if !strings.HasSuffix(line, `\ `) {
+		line = strings.TrimRight(line, " ")
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false, nil
+	}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return rule, false, nil
+	}
+
+	pattern, err := regexp.Compile(globToRegexp(line))
+	if err != nil {
+		return rule, false, err
+	}
+	rule.pattern = pattern
+	return rule, true, nil
+}
+
+// globToRegexp converts a gitignore glob to a regular expression. "*" and "?" do not match "/",
+// while "**" matches across directories.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			sb.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// Ignored returns true if the file or directory at the slash-separated path, relative
+// to the directory of the ignore file, is excluded. The last matching rule wins.
+func (rules ignoreRules) Ignored(relPath string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		target := relPath
+		if !rule.anchored {
+			target = path.Base(relPath)
+		}
+		if rule.pattern.MatchString(target) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// listObjectFiles returns the object files (.json, .yaml and .yml) in the directory
+// and its subdirectories, skipping those excluded by the directory's .fsocignore file
+func listObjectFiles(dir string) ([]string, error) {
+	rules, err := loadIgnoreFile(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	err = filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		if rules.Ignored(filepath.ToSlash(relPath), entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(filePath)) {
+		case ".json", ".yaml", ".yml":
+			files = append(files, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't list the object files in %q: %v", dir, err)
+	}
+	return files, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestRules(t *testing.T, lines ...string) ignoreRules {
+	rules := ignoreRules{}
+	for _, line := range lines {
+		rule, ok, err := parseIgnoreRule(line)
+		require.Nil(t, err, line)
+		if ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func TestIgnoreRules(t *testing.T) {
+	rules := parseTestRules(t,
+		"# scratch files",
+		"",
+		"*.tmp.json",
+		"!keep.tmp.json",
+		"templates/",
+		"/root-only.json",
+		"docs/**/draft-*.yaml",
+		`\#hash.json`,
+	)
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{path: "object.json", ignored: false},
+		{path: "a.tmp.json", ignored: true},
+		{path: "nested/b.tmp.json", ignored: true},
+		{path: "keep.tmp.json", ignored: false},
+		{path: "templates", isDir: true, ignored: true},
+		{path: "templates", isDir: false, ignored: false},
+		{path: "root-only.json", ignored: true},
+		{path: "nested/root-only.json", ignored: false},
+		{path: "docs/draft-1.yaml", ignored: true},
+		{path: "docs/a/b/draft-2.yaml", ignored: true},
+		{path: "docs/final.yaml", ignored: false},
+		{path: "#hash.json", ignored: true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.ignored, rules.Ignored(tt.path, tt.isDir), tt.path)
+	}
+}
+
+func TestListObjectFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.yaml", "notes.txt", "scratch.json", "templates/t.json", "sub/c.yml"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, os.WriteFile(path, []byte("{}"), 0644))
+	}
+	require.Nil(t, os.WriteFile(filepath.Join(dir, ignoreFileName), []byte("scratch.json\ntemplates/\n"), 0644))
+
+	files, err := listObjectFiles(dir)
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a.json"),
+		filepath.Join(dir, "b.yaml"),
+		filepath.Join(dir, "sub", "c.yml"),
+	}, files)
+}
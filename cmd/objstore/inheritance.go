@@ -0,0 +1,153 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// layerObject is the data of an object as seen from a layer; nil if the object is not visible from it
+type layerObject struct {
+	LayerType string
+	Data      any
+}
+
+// inheritedField is a field of an object annotated with the layer its value is resolved from
+type inheritedField struct {
+	Path      string `json:"path" yaml:"path"`
+	Value     any    `json:"value" yaml:"value"`
+	Layer     string `json:"layer" yaml:"layer"`
+	Inherited bool   `json:"inherited" yaml:"inherited"` // true if the value comes from a higher layer than the requested one
+}
+
+// getInheritanceTrace fetches the object as seen from each layer, from the highest down to the
+// requested layer, and displays each of its fields with the layer its value is resolved from
+func getInheritanceTrace(cmd *cobra.Command, fqtn string, objID string, targetLayer string, targetLayerID string) error {
+	targetRank := layerRank(targetLayer)
+	if targetRank < 0 {
+		return fmt.Errorf("--trace-inheritance is not supported for the %v layer", targetLayer)
+	}
+
+	client := apiClient(cmd)
+	layers := []layerObject{}
+	var target map[string]any
+	for _, lt := range layerOrder[:targetRank+1] {
+		layerID := targetLayerID
+		if string(lt) != targetLayer {
+			layerID = getCorrectLayerID(string(lt), fqtn)
+			if layerID == "" {
+				log.Infof("Skipping the %v layer, since its layer ID is not known", lt)
+				continue
+			}
+		}
+
+		var res map[string]any
+		err := client.JSONGet(getObjectUrl(fqtn, objID), &res, &api.Options{Headers: layerHeaders(string(lt), layerID)})
+		if api.IsNotFound(err) && string(lt) != targetLayer {
+			layers = append(layers, layerObject{LayerType: string(lt)})
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to fetch object %q as seen from the %v layer: %v", objID, lt, err)
+		}
+		layers = append(layers, layerObject{LayerType: string(lt), Data: res["data"]})
+		target = res
+	}
+
+	fields := traceInheritance(layers)
+	inherited := 0
+	lines := make([][]string, len(fields))
+	for i, f := range fields {
+		layer := f.Layer
+		if f.Inherited {
+			inherited++
+			layer += " (inherited)"
+		}
+		lines[i] = []string{f.Path, formatDiffValue(f.Value), layer}
+	}
+	out := map[string]any{
+		"id":        objID,
+		"type":      fqtn,
+		"layerType": targetLayer,
+		"layerId":   target["layerId"],
+		"fields":    fields,
+	}
+	output.PrintCmdOutputCustom(cmd, out, &output.Table{
+		Headers: []string{"Field", "Value", "Layer"},
+		Lines:   lines,
+		Footer:  fmt.Sprintf("%v of %v fields inherited from higher layers", inherited, len(fields)),
+	})
+	return nil
+}
+
+// traceInheritance annotates each field of the object as seen from the last (lowest) layer with the
+// layer its value is resolved from: the highest layer from which the object has the same value,
+// with no different value in between. The layers are ordered from the highest to the lowest.
+func traceInheritance(layers []layerObject) []inheritedField {
+	if len(layers) == 0 {
+		return []inheritedField{}
+	}
+	layerFields := make([]map[string]any, len(layers))
+	for i, l := range layers {
+		layerFields[i] = map[string]any{}
+		if l.Data != nil {
+			flattenFields(normalizeValue(l.Data), "", layerFields[i])
+		}
+	}
+
+	last := len(layers) - 1
+	fields := []inheritedField{}
+	for path, value := range layerFields[last] {
+		resolved := last
+		for i := last - 1; i >= 0; i-- {
+			other, found := layerFields[i][path]
+			if !found || !reflect.DeepEqual(other, value) {
+				break
+			}
+			resolved = i
+		}
+		fields = append(fields, inheritedField{
+			Path:      path,
+			Value:     value,
+			Layer:     layers[resolved].LayerType,
+			Inherited: resolved != last,
+		})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields
+}
+
+// flattenFields collects the leaf values of a JSON value by their dotted path,
+// descending into objects; arrays and empty objects are leaf values
+func flattenFields(value any, path string, fields map[string]any) {
+	m, isMap := value.(map[string]any)
+	if !isMap || (len(m) == 0 && path != "") {
+		if path != "" {
+			fields[path] = value
+		}
+		return
+	}
+	for key, field := range m {
+		flattenFields(field, joinPath(path, key), fields)
+	}
+}
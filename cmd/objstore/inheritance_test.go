@@ -0,0 +1,47 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceInheritance(t *testing.T) {
+	layers := []layerObject{
+		{LayerType: "SOLUTION", Data: map[string]any{"color": "blue", "font": map[string]any{"size": 12, "face": "serif"}, "tags": []any{"a"}}},
+		{LayerType: "TENANT", Data: map[string]any{"color": "green", "font": map[string]any{"size": 12, "face": "serif"}, "tags": []any{"a"}}},
+		{LayerType: "LOCALUSER", Data: map[string]any{"color": "green", "font": map[string]any{"size": 14, "face": "serif"}, "tags": []any{"a"}, "extra": true}},
+	}
+
+	assert.Equal(t, []inheritedField{
+		{Path: "color", Value: "green", Layer: "TENANT", Inherited: true},
+		{Path: "extra", Value: true, Layer: "LOCALUSER"},
+		{Path: "font.face", Value: "serif", Layer: "SOLUTION", Inherited: true},
+		{Path: "font.size", Value: float64(14), Layer: "LOCALUSER"},
+		{Path: "tags", Value: []any{"a"}, Layer: "SOLUTION", Inherited: true},
+	}, traceInheritance(layers))
+}
+
+func TestTraceInheritanceNotVisible(t *testing.T) {
+	// an object that exists only in the requested layer is not inherited
+	layers := []layerObject{
+		{LayerType: "SOLUTION"},
+		{LayerType: "TENANT", Data: map[string]any{"color": "green"}},
+	}
+	assert.Equal(t, []inheritedField{{Path: "color", Value: "green", Layer: "TENANT"}}, traceInheritance(layers))
+	assert.Empty(t, traceInheritance(nil))
+}
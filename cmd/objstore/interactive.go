@@ -0,0 +1,162 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+
+	"github.com/cisco-open/fsoc/output"
+)
+
+// promptForObject fetches the schema of a type and prompts the user for the value of
+// each top-level property, assembling an object of that type. Required properties are
+// prompted first and must have a value; optional properties can be skipped by leaving them empty
+// or, for the remaining ones, by ending the input.
+func promptForObject(cmd *cobra.Command, fqtn string) (map[string]interface{}, error) {
+	if err := output.CheckInputAllowed(); err != nil {
+		return nil, err
+	}
+
+	typeDef, err := fetchType(apiClient(cmd), fqtn, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch type %q: %v", fqtn, err)
+	}
+	typeMap, ok := typeDef.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected type definition format for type %q", fqtn)
+	}
+	schema, ok := typeMap["jsonSchema"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("type %q has no JSON schema", fqtn)
+	}
+
+	return promptForSchemaProperties(cmd, cmd.InOrStdin(), schema)
+}
+
+func promptForSchemaProperties(cmd *cobra.Command, in io.Reader, schema map[string]interface{}) (map[string]interface{}, error) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return nil, fmt.Errorf("the type's schema has no properties to prompt for")
+	}
+	required := []string{}
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				required = append(required, s)
+			}
+		}
+	}
+
+	// order the properties: required first, then alphabetically
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := slices.Contains(required, names[i]), slices.Contains(required, names[j])
+		if ri != rj {
+			return ri
+		}
+		return names[i] < names[j]
+	})
+
+	reader := bufio.NewReader(in)
+	object := map[string]interface{}{}
+prompts:
+	for _, name := range names {
+		propSchema, _ := properties[name].(map[string]interface{})
+		propType, _ := propSchema["type"].(string)
+		isRequired := slices.Contains(required, name)
+
+		label := propType
+		if isRequired {
+			label += ", required"
+		}
+		if description, ok := propSchema["description"].(string); ok && description != "" {
+			output.PrintCmdStatus(cmd, fmt.Sprintf("# %v\n", description))
+		}
+
+		for {
+			output.PrintCmdStatus(cmd, fmt.Sprintf("%v (%v): ", name, label))
+			answer, err := reader.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+			if err != nil && answer == "" {
+				if isRequired {
+					return nil, fmt.Errorf("input ended before all required properties were provided: missing %v", strings.Join(missingProperties(object, names, required), ", "))
+				}
+				// the remaining properties are optional, since required ones come first
+				output.PrintCmdStatus(cmd, "\n") // end the unanswered prompt's line
+				break prompts
+			}
+			if answer == "" {
+				if isRequired {
+					output.PrintCmdStatus(cmd, "A value is required\n")
+					continue
+				}
+				break // skip optional property
+			}
+			value, err := parsePropertyValue(answer, propType)
+			if err != nil {
+				output.PrintCmdStatus(cmd, fmt.Sprintf("Invalid value: %v\n", err))
+				continue
+			}
+			object[name] = value
+			break
+		}
+	}
+
+	return object, nil
+}
+
+// missingProperties returns the required properties, in prompt order, that have no value in the object
+func missingProperties(object map[string]interface{}, names []string, required []string) []string {
+	missing := []string{}
+	for _, name := range names {
+		if _, found := object[name]; !found && slices.Contains(required, name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// parsePropertyValue converts the user's answer to the JSON schema type of the property.
+// Objects and arrays are entered as JSON.
+func parsePropertyValue(answer string, propType string) (any, error) {
+	switch propType {
+	case "integer":
+		return strconv.ParseInt(answer, 10, 64)
+	case "number":
+		return strconv.ParseFloat(answer, 64)
+	case "boolean":
+		return strconv.ParseBool(answer)
+	case "object", "array":
+		var value any
+		if err := json.Unmarshal([]byte(answer), &value); err != nil {
+			return nil, fmt.Errorf("expected %v in JSON format: %v", propType, err)
+		}
+		return value, nil
+	default:
+		return answer, nil
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptForSchemaProperties(t *testing.T) {
+	schema := map[string]interface{}{
+		"required": []interface{}{"name", "size"},
+		"properties": map[string]interface{}{
+			"name":    map[string]interface{}{"type": "string"},
+			"size":    map[string]interface{}{"type": "integer"},
+			"color":   map[string]interface{}{"type": "string"},
+			"enabled": map[string]interface{}{"type": "boolean"},
+		},
+	}
+
+	// prompt order: name, size (required), then color, enabled (optional, alphabetically)
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:  "all properties",
+			input: "dark\n3\nblack\ntrue\n",
+			want:  map[string]interface{}{"name": "dark", "size": int64(3), "color": "black", "enabled": true},
+		},
+		{
+			name:  "optional properties skipped",
+			input: "dark\n3\n\n\n",
+			want:  map[string]interface{}{"name": "dark", "size": int64(3)},
+		},
+		{
+			name:  "required value and invalid value asked again",
+			input: "\ndark\nbig\n3\n\nmaybe\nfalse\n",
+			want:  map[string]interface{}{"name": "dark", "size": int64(3), "enabled": false},
+		},
+		{
+			name:  "input ends at optional properties",
+			input: "dark\n3\nblack",
+			want:  map[string]interface{}{"name": "dark", "size": int64(3), "color": "black"},
+		},
+		{
+			name:  "input ends right after required properties",
+			input: "dark\n3\n",
+			want:  map[string]interface{}{"name": "dark", "size": int64(3)},
+		},
+		{
+			name:    "input ends at required properties",
+			input:   "dark\n",
+			wantErr: "missing size",
+		},
+		{
+			name:    "input ends after invalid required value",
+			input:   "dark\nbig\n",
+			wantErr: "missing size",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.SetOut(&bytes.Buffer{})
+			object, err := promptForSchemaProperties(cmd, strings.NewReader(tt.input), schema)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, object)
+		})
+	}
+}
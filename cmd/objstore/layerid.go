@@ -15,7 +15,12 @@
 package objstore
 
 import (
+	"fmt"
 	"strings"
+	"text/template"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/cmd/config"
 )
@@ -36,3 +41,67 @@ func getCorrectLayerID(layerType string, fqtn string) string {
 
 	return layerID
 }
+
+// checkTenantLayerID warns if a layer ID specified for the TENANT layer differs from the
+// current context's tenant, which is usually a copy-paste mistake between environments.
+// With the --strict flag, the mismatch is an error instead.
+func checkTenantLayerID(cmd *cobra.Command, layerType string, layerID string) error {
+	if layerType != "TENANT" || layerID == "" {
+		return nil
+	}
+	cfg := config.GetCurrentContext()
+	if cfg == nil || cfg.Tenant == "" || cfg.Tenant == layerID {
+		return nil
+	}
+
+	message := fmt.Sprintf("layer ID %q differs from tenant %q of the current context %q", layerID, cfg.Tenant, cfg.Name)
+	if strict, _ := cmd.Flags().GetBool("strict"); strict {
+		return fmt.Errorf("%v; omit --layer-id to use the context's tenant", message)
+	}
+	log.Warnf("The %v; use --strict to treat this as an error", message)
+	return nil
+}
+
+// getLayerIDFlag returns the value of the --layer-id flag, rendered as a template against the
+// current context (see renderLayerID)
+func getLayerIDFlag(cmd *cobra.Command) (string, error) {
+	layerID, err := cmd.Flags().GetString("layer-id")
+	if err != nil {
+		return "", fmt.Errorf("error trying to get %q flag value: %w", "layer-id", err)
+	}
+	return renderLayerID(layerID, config.GetCurrentContext())
+}
+
+// layerIDTemplateVars returns the context values that can be used in --layer-id templates
+func layerIDTemplateVars(cfg *config.Context) map[string]string {
+	vars := map[string]string{"Name": "", "Server": "", "Tenant": "", "User": ""}
+	if cfg != nil {
+		vars["Name"] = cfg.Name
+		vars["Server"] = cfg.Server
+		vars["Tenant"] = cfg.Tenant
+		vars["User"] = cfg.User
+	}
+	return vars
+}
+
+// renderLayerID renders a layer ID that contains template variables referencing the context,
+// e.g., "{{.Tenant}}", so that scripts can be shared across contexts. Layer IDs without
+// templates are returned unchanged.
+func renderLayerID(layerID string, cfg *config.Context) (string, error) {
+	if !strings.Contains(layerID, "{{") {
+		return layerID, nil
+	}
+	available := "{{.Name}}, {{.Server}}, {{.Tenant}} and {{.User}}"
+	tmpl, err := template.New("layer-id").Option("missingkey=error").Parse(layerID)
+	if err != nil {
+		return "", fmt.Errorf("invalid --layer-id template %q: %v", layerID, err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, layerIDTemplateVars(cfg)); err != nil {
+		return "", fmt.Errorf("can't render the --layer-id template %q: %v; the available variables are %v", layerID, err, available)
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("the --layer-id template %q renders to an empty layer ID for context %q", layerID, layerIDTemplateVars(cfg)["Name"])
+	}
+	return sb.String(), nil
+}
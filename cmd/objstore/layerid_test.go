@@ -0,0 +1,74 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+)
+
+func TestCheckTenantLayerID(t *testing.T) {
+	viper.Set("contexts", []map[string]any{{"name": "test", "tenant": "tenant-1"}})
+	viper.Set("current_context", "test")
+	t.Cleanup(func() {
+		viper.Set("contexts", nil)
+		viper.Set("current_context", nil)
+	})
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("strict", false, "")
+
+	// matching, derived or non-tenant layer IDs are fine
+	assert.Nil(t, checkTenantLayerID(cmd, "TENANT", "tenant-1"))
+	assert.Nil(t, checkTenantLayerID(cmd, "TENANT", ""))
+	assert.Nil(t, checkTenantLayerID(cmd, "SOLUTION", "tenant-2"))
+
+	// mismatch is a warning by default and an error with --strict
+	assert.Nil(t, checkTenantLayerID(cmd, "TENANT", "tenant-2"))
+	require.Nil(t, cmd.Flags().Set("strict", "true"))
+	err := checkTenantLayerID(cmd, "TENANT", "tenant-2")
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), `"tenant-1"`)
+}
+
+func TestRenderLayerID(t *testing.T) {
+	cfg := &config.Context{Name: "prod", Tenant: "tenant-1", User: "user-1", Token: "secret"}
+
+	layerID, err := renderLayerID("{{.Tenant}}", cfg)
+	require.Nil(t, err)
+	assert.Equal(t, "tenant-1", layerID)
+
+	layerID, err = renderLayerID("{{.User}}-{{.Name}}", cfg)
+	require.Nil(t, err)
+	assert.Equal(t, "user-1-prod", layerID)
+
+	layerID, err = renderLayerID("plain-id", cfg)
+	require.Nil(t, err)
+	assert.Equal(t, "plain-id", layerID)
+
+	// unknown variables (including the context's secrets) are errors
+	_, err = renderLayerID("{{.Token}}", cfg)
+	assert.ErrorContains(t, err, "available variables")
+	_, err = renderLayerID("{{.Tenant", cfg)
+	assert.ErrorContains(t, err, "invalid --layer-id template")
+	_, err = renderLayerID("{{.Server}}", cfg)
+	assert.ErrorContains(t, err, "empty layer ID")
+}
@@ -0,0 +1,250 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// Severity levels of the lint findings; only errors make the lint command fail
+const (
+	lintError   = "error"
+	lintWarning = "warning"
+)
+
+// lintFinding is an issue found in an object definition; Path is the JSON Pointer of the
+// offending field ("/" for the whole object)
+type lintFinding struct {
+	Severity string `json:"severity" yaml:"severity"`
+	Path     string `json:"path" yaml:"path"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+// layerReferenceFields are fields that specify an object's layer in the apply manifests and
+// the platform's responses, but not in the object data sent to the create command
+var layerReferenceFields = []string{"layerType", "layerId"}
+
+func newLintCmd() *cobra.Command {
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check an object definition file before creating the object",
+		Long: `Check an object definition file locally before creating the object, reporting all the issues found.
+
+The checks are:
+  - the file is a valid JSON or YAML object
+  - the object conforms to its type's JSON schema
+  - no immutable (readOnly) fields are set
+  - required string fields are not empty
+  - the layer type, if specified, is allowed for the type, and the object does not specify
+    its layer in layerType/layerId fields that the type does not define
+
+The command exits with an error if any error-level issue is found; warnings are reported only.
+The type's definition is fetched from the platform, which only resends it if the locally cached
+copy is out of date. A type that does not exist is an error; if the platform can't be reached,
+only the file itself is checked and a warning is reported.`,
+		Example: `  # Check an object file before creating it
+  fsoc obj lint --type preferences:theme --object-file theme.json
+
+  # Also check that the object can be created in the tenant layer
+  fsoc obj lint --type preferences:theme --object-file theme.yaml --layer-type TENANT`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return lintObjectFile(cmd)
+		},
+	}
+
+	lintCmd.Flags().
+		String("type", "", "The fully qualified type name of the object (default is the object file's \"$type\" field)")
+
+	lintCmd.Flags().
+		String("object-file", "", "The json or yaml file containing the object definition")
+	_ = lintCmd.MarkFlagRequired("object-file")
+
+	lintCmd.Flags().
+		String("layer-type", "", "The layer type the object is meant to be created in, checked against the type's allowed layers")
+
+	lintCmd.Flags().
+		Bool("refresh", false, "Ignore the locally cached copy of the type and fetch it from the server")
+
+	return lintCmd
+}
+
+func lintObjectFile(cmd *cobra.Command) error {
+	path := objectFilePath(cmd, "object-file")
+	fqtn, _ := cmd.Flags().GetString("type")
+	layerType, _ := cmd.Flags().GetString("layer-type")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+
+	findings := []lintFinding{}
+	obj, err := readObjectFile(path)
+	if err == nil {
+		fqtn, err = resolveObjectType(fqtn, obj)
+	}
+	if err != nil {
+		findings = append(findings, lintFinding{Severity: lintError, Path: "/", Message: err.Error()})
+	} else {
+		typeDef, err := fetchType(apiClient(cmd), fqtn, refresh)
+		if api.IsNotFound(err) {
+			findings = append(findings, lintFinding{Severity: lintError, Path: "/", Message: fmt.Sprintf("type %q does not exist", fqtn)})
+		} else if err != nil {
+			findings = append(findings, lintFinding{
+				Severity: lintWarning,
+				Path:     "/",
+				Message:  fmt.Sprintf("type %q is not available, the type checks are skipped: %v", fqtn, err),
+			})
+		} else {
+			findings = append(findings, lintObject(obj, typeDef, canonicalLayerType(layerType))...)
+		}
+	}
+
+	errorCount := 0
+	lines := make([][]string, len(findings))
+	for i, f := range findings {
+		if f.Severity == lintError {
+			errorCount++
+		}
+		lines[i] = []string{f.Severity, f.Path, f.Message}
+	}
+	footer := fmt.Sprintf("%v: %v error(s), %v warning(s)", path, errorCount, len(findings)-errorCount)
+	if len(findings) == 0 {
+		footer = fmt.Sprintf("%v: no issues found", path)
+	}
+	output.PrintCmdOutputCustom(cmd, map[string]any{"file": path, "type": fqtn, "findings": findings}, &output.Table{
+		Headers: []string{"Severity", "Path", "Message"},
+		Lines:   lines,
+		Footer:  footer,
+	})
+
+	if errorCount > 0 {
+		return cmdkit.ExitWithCode(cmd, 1) // the errors are reported in the findings
+	}
+	return nil
+}
+
+// lintObject checks the object against its type definition; layerType is the layer type
+// the object is meant to be created in, empty if not known
+func lintObject(obj map[string]any, typeDef any, layerType string) []lintFinding {
+	var findings []lintFinding
+
+	if layerType != "" {
+		allowed := allowedLayers(typeDef)
+		found := false
+		for _, l := range allowed {
+			found = found || l == layerType
+		}
+		if len(allowed) > 0 && !found {
+			findings = append(findings, lintFinding{
+				Severity: lintError,
+				Path:     "/",
+				Message:  fmt.Sprintf("layer type %v is not allowed for the type, use one of: %v", layerType, strings.Join(allowed, ", ")),
+			})
+		}
+	}
+
+	typeMap, _ := typeDef.(map[string]any)
+	schema, ok := typeMap["jsonSchema"].(map[string]any)
+	if !ok {
+		return append(findings, lintFinding{Severity: lintWarning, Path: "/", Message: "the type has no JSON schema, the schema checks are skipped"})
+	}
+	findings = append(findings, schemaFindings(schema, obj)...)
+
+	immutable := immutableFields(schema, obj, "")
+	sort.Strings(immutable)
+	for _, field := range immutable {
+		findings = append(findings, lintFinding{
+			Severity: lintError,
+			Path:     "/" + strings.ReplaceAll(field, ".", "/"),
+			Message:  "immutable (readOnly) field is set; it is assigned by the platform",
+		})
+	}
+
+	findings = append(findings, emptyRequiredStrings(schema, obj, "")...)
+
+	properties, _ := schema["properties"].(map[string]any)
+	for _, field := range layerReferenceFields {
+		if _, set := obj[field]; set && properties[field] == nil {
+			findings = append(findings, lintFinding{
+				Severity: lintWarning,
+				Path:     "/" + field,
+				Message:  "the object's layer is specified with --layer-type and --layer-id, not in the object data",
+			})
+		}
+	}
+	return findings
+}
+
+// schemaFindings validates the object against the type's JSON schema, reporting each violation as an error
+func schemaFindings(schema map[string]any, obj map[string]any) []lintFinding {
+	schemaBytes, err := json.Marshal(schema)
+	if err != nil {
+		return []lintFinding{{Severity: lintWarning, Path: "/", Message: fmt.Sprintf("failed to read the type's JSON schema: %v", err)}}
+	}
+	objBytes, err := json.Marshal(obj)
+	if err != nil {
+		return []lintFinding{{Severity: lintError, Path: "/", Message: fmt.Sprintf("failed to encode the object: %v", err)}}
+	}
+	violations, err := cmdkit.ValidateJSONSchema(gojsonschema.NewStringLoader(string(schemaBytes)), gojsonschema.NewStringLoader(string(objBytes)), "")
+	if err != nil {
+		return []lintFinding{{Severity: lintWarning, Path: "/", Message: fmt.Sprintf("failed to validate against the type's JSON schema: %v", err)}}
+	}
+
+	var findings []lintFinding
+	for _, v := range violations {
+		findings = append(findings, lintFinding{Severity: lintError, Path: v.Pointer, Message: v.Description})
+	}
+	return findings
+}
+
+// emptyRequiredStrings returns a warning for each required string field that is set to an
+// empty string, which satisfies the schema but is rarely intended; descends into nested objects
+func emptyRequiredStrings(schema map[string]any, obj map[string]any, prefix string) []lintFinding {
+	properties, _ := schema["properties"].(map[string]any)
+	required, _ := schema["required"].([]any)
+
+	var findings []lintFinding
+	for _, entry := range required {
+		name, _ := entry.(string)
+		if value, ok := obj[name].(string); ok && strings.TrimSpace(value) == "" {
+			findings = append(findings, lintFinding{
+				Severity: lintWarning,
+				Path:     prefix + "/" + name,
+				Message:  "required field is an empty string",
+			})
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, _ := properties[name].(map[string]any)
+		if nested, ok := obj[name].(map[string]any); ok && property != nil {
+			findings = append(findings, emptyRequiredStrings(property, nested, prefix+"/"+name)...)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,116 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func TestLintObject(t *testing.T) {
+	typeDef := map[string]any{
+		"allowedLayers": []any{"SOLUTION", "TENANT"},
+		"jsonSchema": map[string]any{
+			"type":     "object",
+			"required": []any{"name"},
+			"properties": map[string]any{
+				"id":   map[string]any{"type": "string", "readOnly": true},
+				"name": map[string]any{"type": "string"},
+				"config": map[string]any{
+					"type":     "object",
+					"required": []any{"url"},
+					"properties": map[string]any{
+						"url": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+
+	obj := map[string]any{
+		"id":        "theme-1",
+		"name":      " ",
+		"config":    map[string]any{"url": ""},
+		"layerType": "TENANT",
+	}
+	assert.Equal(t, []lintFinding{
+		{Severity: lintError, Path: "/", Message: "layer type LOCALUSER is not allowed for the type, use one of: SOLUTION, TENANT"},
+		{Severity: lintError, Path: "/id", Message: "immutable (readOnly) field is set; it is assigned by the platform"},
+		{Severity: lintWarning, Path: "/name", Message: "required field is an empty string"},
+		{Severity: lintWarning, Path: "/config/url", Message: "required field is an empty string"},
+		{Severity: lintWarning, Path: "/layerType", Message: "the object's layer is specified with --layer-type and --layer-id, not in the object data"},
+	}, lintObject(obj, typeDef, "LOCALUSER"))
+
+	assert.Empty(t, lintObject(map[string]any{"name": "dark"}, typeDef, "TENANT"))
+}
+
+func TestLintObjectWithoutSchema(t *testing.T) {
+	findings := lintObject(map[string]any{"name": "dark"}, map[string]any{}, "")
+	assert.Equal(t, []lintFinding{
+		{Severity: lintWarning, Path: "/", Message: "the type has no JSON schema, the schema checks are skipped"},
+	}, findings)
+}
+
+// runLint lints an object file with the type served by the handler and returns the output
+func runLint(t *testing.T, handler http.HandlerFunc) (string, error) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir()) // isolate the type cache
+	client := newTestClient(t, handler)
+
+	path := filepath.Join(t.TempDir(), "theme.json")
+	require.Nil(t, os.WriteFile(path, []byte(`{"name": "dark"}`), 0600))
+
+	cmd := newLintCmd()
+	cmd.Flags().String("output", "json", "")
+	cmd.Flags().String("fields", "", "")
+	require.Nil(t, cmd.Flags().Set("type", "preferences:theme"))
+	require.Nil(t, cmd.Flags().Set("object-file", path))
+	var out strings.Builder
+	cmd.SetOut(&out)
+	cmd.SetContext(api.WithClient(context.Background(), client))
+
+	err := cmd.RunE(cmd, nil)
+	return out.String(), err
+}
+
+func TestLintObjectFileTypeNotFound(t *testing.T) {
+	out, err := runLint(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	var exitErr cmdkit.ExitCodeError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 1, exitErr.Code)
+	assert.Contains(t, out, `"severity":"error"`)
+	assert.Contains(t, out, `type \"preferences:theme\" does not exist`)
+}
+
+func TestLintObjectFileTypeUnavailable(t *testing.T) {
+	out, err := runLint(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, out, `"severity":"warning"`)
+	assert.Contains(t, out, "the type checks are skipped")
+}
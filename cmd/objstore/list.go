@@ -0,0 +1,127 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// objectListInfo describes a list of objects
+type objectListInfo struct {
+	Type     string
+	Layer    string
+	LayerID  string
+	Filtered bool
+}
+
+// objectListEnvelope is the list output with --with-metadata
+type objectListEnvelope struct {
+	Count       int    `json:"count" yaml:"count"`
+	Layer       string `json:"layer" yaml:"layer"`
+	LayerID     string `json:"layerId" yaml:"layerId"`
+	Type        string `json:"type" yaml:"type"`
+	ServerTotal int    `json:"serverTotal" yaml:"serverTotal"` // total number of objects reported by the server
+	PageSize    int    `json:"pageSize" yaml:"pageSize"`       // number of objects in the first page returned by the server
+	Truncated   bool   `json:"truncated" yaml:"truncated"`     // the list was truncated to --max-items
+	Items       []any  `json:"items" yaml:"items"`
+}
+
+// getObjectList fetches a list of objects and displays it, followed by a summary of
+// the count, type and layer of the objects. With metadata, the list is wrapped in an
+// envelope with the same information and the pagination details, for consumers that page
+// through the objects themselves.
+func getObjectList(cmd *cobra.Command, objStoreUrl string, headers map[string]string, info objectListInfo, withMetadata bool) error {
+	items := []any{}
+	options := api.Options{Headers: headers, ItemHandler: func(item any) error {
+		items = append(items, item)
+		return nil
+	}}
+	var res any
+	if err := apiClient(cmd).JSONGetCollection(objStoreUrl, &res, &options); err != nil {
+		return fmt.Errorf("Platform API call failed: %w", err)
+	}
+
+	if withMetadata {
+		output.PrintCmdOutput(cmd, objectListEnvelope{
+			Count:       len(items),
+			Layer:       info.Layer,
+			LayerID:     info.LayerID,
+			Type:        info.Type,
+			ServerTotal: options.CollectionTotal,
+			PageSize:    options.CollectionPageSize,
+			Truncated:   options.CollectionTruncated,
+			Items:       items,
+		})
+		return nil
+	}
+
+	footer := objectListSummary(len(items), info)
+	if options.CollectionTruncated {
+		footer += fmt.Sprintf("\n(results truncated to %v items; use --max-items to raise the limit)", api.GetMaxCollectionItems())
+	}
+	output.PrintCmdOutputCustom(cmd, map[string]any{"items": items, "total": len(items)}, &output.Table{Footer: footer})
+	return nil
+}
+
+// objectListSummary describes a list of objects, e.g., "3 objects of type preferences:theme at layer TENANT (<id>)"
+func objectListSummary(count int, info objectListInfo) string {
+	noun := "objects"
+	if count == 1 {
+		noun = "object"
+	}
+	summary := fmt.Sprintf("%v %v of type %v at layer %v", count, noun, info.Type, info.Layer)
+	if info.LayerID != "" {
+		summary += fmt.Sprintf(" (%v)", info.LayerID)
+	}
+	if info.Filtered {
+		summary += " matching the filter"
+	}
+	return summary
+}
+
+// getObjectIDs fetches a list of objects and writes their IDs, one per line, bypassing the
+// output formatting. IDs are written as the objects are received.
+func getObjectIDs(cmd *cobra.Command, objStoreUrl string, headers map[string]string) error {
+	w := output.GetOutWriter(cmd)
+	options := api.Options{Headers: headers, ItemHandler: func(item any) error {
+		return writeObjectID(w, item)
+	}}
+	var res any
+	if err := apiClient(cmd).JSONGetCollection(objStoreUrl, &res, &options); err != nil {
+		return fmt.Errorf("Platform API call failed: %w", err)
+	}
+	if options.CollectionTruncated {
+		log.Warnf("Results truncated to %v items; use --max-items to raise the limit", api.GetMaxCollectionItems())
+	}
+	return nil
+}
+
+// writeObjectID writes the object's ID on a line
+func writeObjectID(w io.Writer, item any) error {
+	obj, _ := item.(map[string]any)
+	id, ok := obj["id"].(string)
+	if !ok {
+		return fmt.Errorf("object without an ID in the list: %v", item)
+	}
+	_, err := fmt.Fprintln(w, id)
+	return err
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func TestObjectListSummary(t *testing.T) {
+	info := objectListInfo{Type: "preferences:theme", Layer: "TENANT", LayerID: "t1"}
+	assert.Equal(t, "3 objects of type preferences:theme at layer TENANT (t1)", objectListSummary(3, info))
+
+	info.Filtered = true
+	assert.Equal(t, "1 object of type preferences:theme at layer TENANT (t1) matching the filter", objectListSummary(1, info))
+
+	assert.Equal(t, "0 objects of type t:a at layer SOLUTION", objectListSummary(0, objectListInfo{Type: "t:a", Layer: "SOLUTION"}))
+}
+
+func TestWriteObjectID(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, writeObjectID(&buf, map[string]any{"id": "theme-1", "data": map[string]any{}}))
+	assert.Nil(t, writeObjectID(&buf, map[string]any{"id": "theme-2"}))
+	assert.Equal(t, "theme-1\ntheme-2\n", buf.String())
+
+	assert.NotNil(t, writeObjectID(&buf, map[string]any{"data": map[string]any{}}))
+}
+
+func TestGetObjectListFailure(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "type not found"}`))
+	})
+	cmd := &cobra.Command{}
+	cmd.SetContext(api.WithClient(context.Background(), client))
+
+	err := getObjectIDs(cmd, "objects/preferences:missing", nil)
+	assert.True(t, api.IsNotFound(err))
+	err = getObjectList(cmd, "objects/preferences:missing", nil, objectListInfo{}, false)
+	assert.True(t, api.IsNotFound(err))
+}
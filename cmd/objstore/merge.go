@@ -0,0 +1,60 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/apex/log"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// createOrMergeObject creates the object in the given layer. If the object already exists
+// and merging is enabled, the object is merged into the existing one instead. Returns the
+// ID of the object and whether it was merged.
+func createOrMergeObject(client *api.Client, objType string, objectStruct map[string]interface{}, layerType string, layerID string, idempotencyKey string, retrySafe bool, mergeExisting bool) (string, bool, error) {
+	id, err := postObject(client, objType, objectStruct, layerType, layerID, idempotencyKey, retrySafe)
+	if err == nil || !mergeExisting || !api.HasStatus(err, http.StatusConflict) {
+		return id, false, err
+	}
+
+	id, err = mergeIntoExistingObject(client, objType, objectStruct, layerType, layerID)
+	if err != nil {
+		return "", false, err
+	}
+	return id, true, nil
+}
+
+// mergeIntoExistingObject merges the object into the existing object with the same ID,
+// sending the object as a JSON merge patch
+func mergeIntoExistingObject(client *api.Client, objType string, objectStruct map[string]interface{}, layerType string, layerID string) (string, error) {
+	id, _ := objectStruct["id"].(string)
+	if id == "" {
+		return "", fmt.Errorf("the object already exists but cannot be merged into: the object does not specify its ID")
+	}
+	headers := map[string]string{
+		"layer-type": layerType,
+		"layer-id":   layerID,
+	}
+	log.Infof("The %s object %q already exists in %s layer %q; merging into it", objType, id, layerType, layerID)
+
+	var res any
+	if err := client.JSONPatch(getObjectUrl(objType, id), objectStruct, &res, &api.Options{Headers: headers}); err != nil {
+		return "", fmt.Errorf("failed to merge into the existing object %q: %w", id, err)
+	}
+	return id, nil
+}
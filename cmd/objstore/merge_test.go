@@ -0,0 +1,70 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// newConflictPlatform starts a server that reports a conflict when creating an object
+// and accepts merge patches, recording the requests it received
+func newConflictPlatform(t *testing.T) (*api.Client, *[]string) {
+	requests := []string{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("Content-Type"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message": "object already exists"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	return client, &requests
+}
+
+func TestCreateOrMergeObject(t *testing.T) {
+	client, requests := newConflictPlatform(t)
+	obj := map[string]interface{}{"id": "mytheme", "backgroundColor": "green"}
+
+	id, merged, err := createOrMergeObject(client, "preferences:theme", obj, "TENANT", "t1", "key", false, true)
+	require.Nil(t, err)
+	assert.True(t, merged)
+	assert.Equal(t, "mytheme", id)
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "PATCH /"+config.GetObjStoreBasePath()+"/objects/preferences:theme/mytheme application/merge-patch+json", (*requests)[1])
+}
+
+func TestCreateOrMergeObjectStrict(t *testing.T) {
+	client, requests := newConflictPlatform(t)
+	obj := map[string]interface{}{"id": "mytheme"}
+
+	_, merged, err := createOrMergeObject(client, "preferences:theme", obj, "TENANT", "t1", "key", false, false)
+	require.NotNil(t, err)
+	assert.False(t, merged)
+	assert.Len(t, *requests, 1)
+
+	// objects without an ID can't be merged
+	_, _, err = createOrMergeObject(client, "preferences:theme", map[string]interface{}{}, "TENANT", "t1", "key", false, true)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "does not specify its ID")
+}
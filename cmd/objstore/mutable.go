@@ -0,0 +1,71 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// validatePatchFields checks that the patch only contains fields that are mutable
+// according to the type's JSON schema, i.e., fields that are not marked readOnly.
+// If the type's schema is not available, the check is skipped and left to the server.
+func validatePatchFields(client *api.Client, fqtn string, patch map[string]interface{}) error {
+	typeDef, err := fetchType(client, fqtn, false)
+	if err != nil {
+		log.Warnf("Unable to fetch type %q, skipping the patch field check: %v", fqtn, err)
+		return nil
+	}
+	typeMap, _ := typeDef.(map[string]interface{})
+	schema, ok := typeMap["jsonSchema"].(map[string]interface{})
+	if !ok {
+		log.Infof("Type %q has no JSON schema, skipping the patch field check", fqtn)
+		return nil
+	}
+
+	fields := immutableFields(schema, patch, "")
+	if len(fields) > 0 {
+		sort.Strings(fields)
+		return fmt.Errorf("the patch contains immutable fields that cannot be patched: %v", strings.Join(fields, ", "))
+	}
+	return nil
+}
+
+// immutableFields returns the dot-separated paths of the patch's fields that are
+// marked readOnly in the schema, descending into nested object properties
+func immutableFields(schema map[string]interface{}, patch map[string]interface{}, prefix string) []string {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	var fields []string
+	for name, value := range patch {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue // unknown fields are left to the server to validate
+		}
+		if readOnly, _ := property["readOnly"].(bool); readOnly {
+			fields = append(fields, prefix+name)
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			fields = append(fields, immutableFields(property, nested, prefix+name+".")...)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImmutableFields(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":        map[string]interface{}{"type": "string", "readOnly": true},
+			"description": map[string]interface{}{"type": "string"},
+			"config": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id":      map[string]interface{}{"type": "string", "readOnly": true},
+					"enabled": map[string]interface{}{"type": "boolean"},
+				},
+			},
+		},
+	}
+
+	// only mutable fields
+	patch := map[string]interface{}{
+		"description": "new",
+		"config":      map[string]interface{}{"enabled": false},
+		"unknown":     1,
+	}
+	assert.Empty(t, immutableFields(schema, patch, ""))
+
+	// immutable fields, including nested ones
+	patch = map[string]interface{}{
+		"name":        "renamed",
+		"description": "new",
+		"config":      map[string]interface{}{"id": "x", "enabled": true},
+	}
+	fields := immutableFields(schema, patch, "")
+	sort.Strings(fields)
+	assert.Equal(t, []string{"config.id", "name"}, fields)
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+)
+
+// defaultProgressInterval is the default number of objects between progress reports
+const defaultProgressInterval = 1000
+
+// ndjsonCreator creates an object read from a line of an NDJSON file and returns its ID and whether
+// it was merged into an existing object; source identifies the line in messages and key is the
+// line's idempotency key
+type ndjsonCreator func(object map[string]interface{}, source string, key string) (string, bool, error)
+
+// insertObjectsFromNDJSON creates an object from each line of a newline-delimited JSON file,
+// reading the file line by line so that files of any size can be imported
+func insertObjectsFromNDJSON(cmd *cobra.Command, objType string, path string, transforms []transformRule, idempotencyKey string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Can't open the %s file: %v", path, err)
+	}
+	defer file.Close()
+
+	stopOnError, _ := cmd.Flags().GetBool("stop-on-error")
+	progressInterval, _ := cmd.Flags().GetInt("progress-interval")
+
+	create := func(object map[string]interface{}, source string, key string) (string, bool, error) {
+		if len(transforms) > 0 {
+			applyTransforms(object, transforms)
+		}
+		return createObject(cmd, objType, object, source, key)
+	}
+	report := newBulkReport(cmd, objType)
+	if err := createObjectsFromNDJSON(cmd, file, path, idempotencyKey, create, stopOnError, progressInterval, report); err != nil {
+		return fmt.Errorf("Failed to read the %s file: %v", path, err)
+	}
+	return report.finish()
+}
+
+// createObjectsFromNDJSON reads objects from the reader, one JSON object per line, and creates each of them,
+// adding each outcome to the report and reporting the progress every progressInterval objects (0 to disable).
+// Blank lines are skipped. Unless stopOnError is set, processing continues past objects that can't be parsed
+// or created. Processing stops early, without an error, if the command is interrupted.
+func createObjectsFromNDJSON(cmd *cobra.Command, r io.Reader, name string, baseKey string, create ndjsonCreator, stopOnError bool, progressInterval int, report *bulkReport) error {
+	reader := bufio.NewReader(r)
+	for lineNo := 1; !cmdkit.Interrupted(cmd); lineNo++ {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return readErr
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			source := fmt.Sprintf("%s:%d", name, lineNo)
+			var objectID string
+			var merged bool
+			object, err := parseObjectBytes(line)
+			if err == nil {
+				objectID, merged, err = create(object, source, fmt.Sprintf("%s-%d", baseKey, lineNo))
+			}
+			report.add(source, objectID, merged, err, "")
+			if err != nil && stopOnError {
+				report.result.Stopped = true
+				return nil
+			}
+
+			if processed := report.result.Total; progressInterval > 0 && processed%progressInterval == 0 {
+				report.status(fmt.Sprintf("Processed %v objects (%v failed)\n", processed, report.result.Failed))
+			}
+		}
+
+		if readErr != nil { // EOF
+			return nil
+		}
+	}
+	return nil
+}
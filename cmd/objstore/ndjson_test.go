@@ -0,0 +1,96 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateObjectsFromNDJSON(t *testing.T) {
+	input := `{"name": "a"}
+
+not json
+{"name": "fail"}
+{"name": "b"}`
+
+	var created, keys []string
+	create := func(object map[string]interface{}, source string, key string) (string, bool, error) {
+		if object["name"] == "fail" {
+			return "", false, fmt.Errorf("rejected")
+		}
+		created = append(created, object["name"].(string))
+		keys = append(keys, key)
+		return "id-" + object["name"].(string), false, nil
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", "", "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	report := newBulkReport(cmd, "test:type")
+	err := createObjectsFromNDJSON(cmd, strings.NewReader(input), "objects.ndjson", "key", create, false, 2, report)
+	require.Nil(t, err)
+	assert.Equal(t, 4, report.result.Total)
+	assert.Equal(t, 2, report.result.Created)
+	assert.Equal(t, 2, report.result.Failed)
+	assert.False(t, report.result.Stopped)
+	assert.Equal(t, []string{"a", "b"}, created)
+	assert.Equal(t, []string{"key-1", "key-5"}, keys)
+	assert.Contains(t, out.String(), "objects.ndjson:3: failed")
+	assert.Contains(t, out.String(), "objects.ndjson:4: failed: rejected")
+	assert.Contains(t, out.String(), "Processed 2 objects (1 failed)")
+	assert.Contains(t, out.String(), "Processed 4 objects (2 failed)")
+
+	// stop at the first failure
+	created = nil
+	report = newBulkReport(cmd, "test:type")
+	err = createObjectsFromNDJSON(cmd, strings.NewReader(input), "objects.ndjson", "key", create, true, 0, report)
+	require.Nil(t, err)
+	assert.Equal(t, 1, report.result.Created)
+	assert.Equal(t, 1, report.result.Failed)
+	assert.True(t, report.result.Stopped)
+	assert.Equal(t, []string{"a"}, created)
+}
+
+func TestCreateObjectsFromNDJSONInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	cmd.Flags().String("output", "", "")
+	cmd.SetOut(&bytes.Buffer{})
+
+	created := 0
+	create := func(object map[string]interface{}, source string, key string) (string, bool, error) {
+		created++
+		cancel() // interrupt while processing the first object
+		return "", false, nil
+	}
+
+	report := newBulkReport(cmd, "test:type")
+	err := createObjectsFromNDJSON(cmd, strings.NewReader("{}\n{}\n{}\n"), "objects.ndjson", "key", create, false, 0, report)
+	require.Nil(t, err)
+	assert.Equal(t, 1, report.result.Total)
+	assert.Equal(t, 1, report.result.Created)
+	assert.Equal(t, 1, created)
+}
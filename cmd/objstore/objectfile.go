@@ -0,0 +1,178 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// largeObjectFileSize is the size of object files above which a warning is displayed, since
+// the whole file is loaded (and parsed) in memory before the object is sent
+const largeObjectFileSize = 10 << 20 // 10MB
+
+// noSizeWarningFlag suppresses the warning about large object files
+const noSizeWarningFlag = "no-size-warning"
+
+// baseDirFlag is the directory that relative object file paths are resolved against
+const baseDirFlag = "base-dir"
+
+// objectFilePath returns the file or directory path given in the command's flag, resolved against
+// the --base-dir directory if the path is relative. Absolute paths and "-" (stdin) are used as is.
+func objectFilePath(cmd *cobra.Command, flag string) string {
+	path, _ := cmd.Flags().GetString(flag)
+	baseDir, _ := cmd.Flags().GetString(baseDirFlag)
+	return resolvePath(baseDir, path)
+}
+
+func resolvePath(baseDir string, path string) string {
+	if baseDir == "" || path == "" || path == "-" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+// useRequestBodyFileAlias makes the command accept --request-body-file as an alias of --object-file
+func useRequestBodyFileAlias(cmd *cobra.Command) {
+	cmd.SetGlobalNormalizationFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "request-body-file" {
+			name = "object-file"
+		}
+		return pflag.NormalizedName(name)
+	})
+}
+
+// warnLargeObjectFile warns if the object file is so large that loading it may use a lot of memory,
+// unless the warning is suppressed with --no-size-warning
+func warnLargeObjectFile(cmd *cobra.Command, path string) {
+	if suppress, _ := cmd.Flags().GetBool(noSizeWarningFlag); suppress {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() <= largeObjectFileSize {
+		return // errors are reported when the file is read
+	}
+	log.Warnf("The object file %q is %.1fMB and will be loaded in memory in full; consider splitting it into multiple objects and using --ndjson or --object-dir (use --%v to suppress this warning)",
+		path, float64(info.Size())/(1<<20), noSizeWarningFlag)
+}
+
+// readObjectFile reads an object definition file, which must contain a single JSON object.
+// Files with a .yaml or .yml extension are read as YAML, e.g., as produced by "get --output yaml".
+func readObjectFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read the object definition file: %v", err)
+	}
+	if isYamlFile(path) {
+		return parseYamlObjectBytes(data)
+	}
+	return parseObjectBytes(data)
+}
+
+func isYamlFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// parseObjectBytes parses the contents of an object definition file, returning
+// a clear error if the contents are empty or not a JSON object
+func parseObjectBytes(data []byte) (map[string]interface{}, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("object file is empty")
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("object file is not valid JSON: %v", err)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case nil:
+		return nil, fmt.Errorf("object file contains null; a JSON object is expected")
+	case []interface{}:
+		return nil, fmt.Errorf("object file contains an array; a JSON object is expected")
+	default:
+		return nil, fmt.Errorf("object file contains a %T value; a JSON object is expected", v)
+	}
+}
+
+// parseYamlObjectBytes parses the contents of a YAML object definition file, returning
+// a clear error if the contents are empty or not a mapping
+func parseYamlObjectBytes(data []byte) (map[string]interface{}, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("object file is empty")
+	}
+
+	var value any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("object file is not valid YAML: %v", err)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case nil:
+		return nil, fmt.Errorf("object file contains null; a YAML mapping is expected")
+	case []interface{}:
+		return nil, fmt.Errorf("object file contains a list; a YAML mapping is expected")
+	default:
+		return nil, fmt.Errorf("object file contains a %T value; a YAML mapping is expected", v)
+	}
+}
+
+// decodeJSONPreservingNumbers decodes a JSON document, keeping integers as integers
+// (rather than float64) so that converting the result to YAML and back is lossless
+func decodeJSONPreservingNumbers(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return convertJSONNumbers(value), nil
+}
+
+func convertJSONNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertJSONNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertJSONNumbers(item)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+	return value
+}
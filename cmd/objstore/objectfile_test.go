@@ -0,0 +1,119 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseObjectBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		errText string
+	}{
+		{name: "empty", input: "", errText: "object file is empty"},
+		{name: "whitespace", input: " \n\t\n", errText: "object file is empty"},
+		{name: "null", input: "null", errText: "contains null"},
+		{name: "array", input: `[{"a": 1}]`, errText: "contains an array"},
+		{name: "number", input: "42", errText: "a JSON object is expected"},
+		{name: "invalid", input: `{"a": `, errText: "not valid JSON"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseObjectBytes([]byte(tt.input))
+		require.NotNil(t, err, tt.name)
+		assert.Contains(t, err.Error(), tt.errText, tt.name)
+	}
+}
+
+func TestParseObjectBytesValid(t *testing.T) {
+	obj, err := parseObjectBytes([]byte(`{"name": "test", "count": 1}`))
+	require.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "test", "count": float64(1)}, obj)
+}
+
+func TestYamlObjectRoundTrip(t *testing.T) {
+	original := `{"id": "mytheme", "data": {"count": 1000000, "ratio": 0.5, "name": "dark", "tags": ["a", "b"], "enabled": true, "parent": null}}`
+
+	obj, err := decodeJSONPreservingNumbers([]byte(original))
+	require.Nil(t, err)
+	yamlData, err := yaml.Marshal(obj)
+	require.Nil(t, err)
+
+	parsed, err := parseYamlObjectBytes(yamlData)
+	require.Nil(t, err)
+	jsonData, err := json.Marshal(parsed)
+	require.Nil(t, err)
+	assert.JSONEq(t, original, string(jsonData))
+}
+
+func TestParseYamlObjectBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		errText string
+	}{
+		{name: "empty", input: "", errText: "object file is empty"},
+		{name: "null", input: "null", errText: "contains null"},
+		{name: "list", input: "- a\n- b\n", errText: "contains a list"},
+		{name: "invalid", input: "a: [", errText: "not valid YAML"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseYamlObjectBytes([]byte(tt.input))
+		require.NotNil(t, err, tt.name)
+		assert.Contains(t, err.Error(), tt.errText, tt.name)
+	}
+}
+
+func TestRequestBodyFileAlias(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("object-file", "", "")
+	useRequestBodyFileAlias(cmd)
+
+	require.Nil(t, cmd.Flags().Parse([]string{"--request-body-file", "object.json"}))
+	objectFile, _ := cmd.Flags().GetString("object-file")
+	assert.Equal(t, "object.json", objectFile)
+}
+
+func TestObjectFilePathBaseDir(t *testing.T) {
+	absPath, err := filepath.Abs("object.json")
+	require.Nil(t, err)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("object-file", "", "")
+	cmd.Flags().String(baseDirFlag, "", "")
+
+	// without --base-dir, paths are relative to the current directory
+	require.Nil(t, cmd.Flags().Parse([]string{"--object-file", "objects/theme.json"}))
+	assert.Equal(t, "objects/theme.json", objectFilePath(cmd, "object-file"))
+
+	require.Nil(t, cmd.Flags().Parse([]string{"--base-dir", "/repo", "--object-file", "objects/theme.json"}))
+	assert.Equal(t, filepath.Join("/repo", "objects/theme.json"), objectFilePath(cmd, "object-file"))
+
+	// absolute paths and stdin are used as is
+	require.Nil(t, cmd.Flags().Parse([]string{"--object-file", absPath}))
+	assert.Equal(t, absPath, objectFilePath(cmd, "object-file"))
+	assert.Equal(t, "-", resolvePath("/repo", "-"))
+	assert.Equal(t, "", resolvePath("/repo", ""))
+}
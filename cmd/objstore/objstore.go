@@ -18,6 +18,8 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/platform/api"
 )
 
 func NewSubCmd() *cobra.Command {
@@ -37,24 +39,51 @@ func NewSubCmd() *cobra.Command {
 ----------------------------------------------------------------
 Perform objectstore interactions.
 See <docs url>`,
-		Example: `# Get object type
+		Example: `# List object types
+  fsoc obj types
+# Get object type
   fsoc objstore get-type --type=<typeName> --solution=<solutionName>"
 # Get object
   fsoc obj get --type=<typeName> --object=<objectId> --layer-id=<layerId> --layer-type=SOLUTION|ACCOUNT|GLOBALUSER|TENANT|LOCALUSER
 # Get object
-  fsoc obj create --type=<fully-qualified-typename> --object-file=<fully-qualified-path> --layer-type=SOLUTION|ACCOUNT|GLOBALUSER|TENANT|LOCALUSER [--layer-id=<respective-layer-id>] `,
+  fsoc obj create --type=<fully-qualified-typename> --object-file=<fully-qualified-path> --layer-type=SOLUTION|ACCOUNT|GLOBALUSER|TENANT|LOCALUSER [--layer-id=<respective-layer-id>]
+# Use the current context's values in the layer ID, e.g., in scripts shared across contexts ({{.Name}}, {{.Server}}, {{.Tenant}} and {{.User}} are available)
+  fsoc obj get --type=<fully-qualified-typename> --object=<objectId> --layer-type=TENANT --layer-id='{{.Tenant}}'
+# Resolve relative object file paths against a directory, e.g., when running from elsewhere than the objects' repository
+  fsoc obj apply --base-dir=<objects-dir> --file=manifests `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("incomplete command")
 		},
 		TraverseChildren: true,
 	}
 
+	objStoreCmd.PersistentFlags().
+		Bool("strict", false, "Fail instead of warning when the --layer-id for the TENANT layer differs from the current context's tenant")
+	objStoreCmd.PersistentFlags().
+		Bool(noSizeWarningFlag, false, "Don't warn when an object file is too large to be loaded comfortably in memory")
+	objStoreCmd.PersistentFlags().
+		String(baseDirFlag, "", "The directory that relative object file and directory paths are resolved against (default is the current directory); absolute paths are used as is")
+
 	objStoreCmd.AddCommand(newGetObjectCmd())
 	objStoreCmd.AddCommand(newGetTypeCmd())
+	objStoreCmd.AddCommand(newTypesCmd())
+	objStoreCmd.AddCommand(newExistsCmd())
+	objStoreCmd.AddCommand(newDiffCmd())
+	objStoreCmd.AddCommand(newClearTypeCacheCmd())
+	objStoreCmd.AddCommand(newTypeLayersCmd())
+	objStoreCmd.AddCommand(newLintCmd())
 	objStoreCmd.AddCommand(getCreateObjectCmd())
 	objStoreCmd.AddCommand(getUpdateObjectCmd())
 	objStoreCmd.AddCommand(getDeleteObjectCmd())
 	objStoreCmd.AddCommand(getCreatePatchObjectCmd())
+	objStoreCmd.AddCommand(getPatchFieldObjectCmd())
+	objStoreCmd.AddCommand(newApplyCmd())
+	objStoreCmd.AddCommand(newExportCmd())
 
 	return objStoreCmd
 }
+
+// apiClient returns the client to use for the command's platform requests
+func apiClient(cmd *cobra.Command) *api.Client {
+	return api.ClientFromContext(cmd.Context())
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// newTestClient returns a client with a test token that sends its requests to the handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *api.Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &api.Client{Context: &config.Context{Name: "test", Token: "test-token"}, BaseURL: srv.URL}
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// objectTypeField is the top-level field of an object file that specifies the object's type,
+// so that self-describing files can be created without --type. Like JSON schema's "$schema",
+// the "$" prefix keeps it apart from the object's data fields.
+const objectTypeField = "$type"
+
+// fqtnPattern matches a well-formed fully qualified type name, <solution>:<type>
+var fqtnPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*:[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// resolveObjectType returns the type of the object: the type specified on the command line, if any,
+// or the type specified in the object's type field. The type field is removed from the object,
+// so that it is not sent to the platform.
+func resolveObjectType(flagType string, object map[string]interface{}) (string, error) {
+	value, found := object[objectTypeField]
+	delete(object, objectTypeField)
+
+	if flagType != "" {
+		return flagType, nil
+	}
+	if !found {
+		return "", fmt.Errorf("missing object type: specify it with the --type flag or in the object's %q field", objectTypeField)
+	}
+	fqtn, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("the object's %q field must be a string, found %T instead", objectTypeField, value)
+	}
+	if !fqtnPattern.MatchString(fqtn) {
+		return "", fmt.Errorf("the object's %q field %q is not a fully qualified type name, <solution>:<type>", objectTypeField, fqtn)
+	}
+	return fqtn, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveObjectType(t *testing.T) {
+	// inferred from the file, the field is removed
+	object := map[string]interface{}{"$type": "preferences:theme", "name": "dark"}
+	fqtn, err := resolveObjectType("", object)
+	require.Nil(t, err)
+	assert.Equal(t, "preferences:theme", fqtn)
+	assert.Equal(t, map[string]interface{}{"name": "dark"}, object)
+
+	// the flag takes precedence
+	object = map[string]interface{}{"$type": "preferences:theme"}
+	fqtn, err = resolveObjectType("preferences:iconSet", object)
+	require.Nil(t, err)
+	assert.Equal(t, "preferences:iconSet", fqtn)
+	assert.NotContains(t, object, "$type")
+
+	// a data field named "type" is not used
+	_, err = resolveObjectType("", map[string]interface{}{"type": "preferences:theme"})
+	assert.NotNil(t, err)
+
+	// malformed types
+	for _, value := range []interface{}{"theme", "preferences:", ":theme", "a:b:c", "pref erences:theme", 42} {
+		_, err = resolveObjectType("", map[string]interface{}{"$type": value})
+		assert.NotNil(t, err, value)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// layerOrder lists the layers from the highest to the lowest; objects in
+// lower layers inherit from (patch) objects in higher layers
+var layerOrder = []layerType{solution, account, globalUser, tenant, localUser}
+
+// layerRank returns the position of the layer in the inheritance order (0 is the highest),
+// or -1 if the layer is not known
+func layerRank(lt string) int {
+	for i, l := range layerOrder {
+		if string(l) == lt {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkPatchParent verifies that the parent object of a patch exists and is in a higher layer
+// than the target layer. The object is fetched as seen from the target layer, so the returned
+// object's layer is the layer from which it would be inherited.
+func checkPatchParent(client *api.Client, fqtn string, parentID string, targetHeaders map[string]string) error {
+	var res map[string]any
+	err := client.JSONGet(getObjectUrl(fqtn, parentID), &res, &api.Options{Headers: targetHeaders})
+	if api.IsNotFound(err) {
+		return fmt.Errorf("the parent object %q does not exist or is not visible from the %s layer", parentID, targetHeaders["layer-type"])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch the parent object %q: %v", parentID, err)
+	}
+
+	parentLayer, _ := res["layerType"].(string)
+	return checkParentLayer(parentID, parentLayer, targetHeaders["layer-type"])
+}
+
+// checkParentLayer verifies that the parent's layer is higher than the target layer
+func checkParentLayer(parentID string, parentLayer string, targetLayer string) error {
+	parentRank, targetRank := layerRank(parentLayer), layerRank(targetLayer)
+	if parentRank < 0 || targetRank < 0 {
+		return nil // can't tell, let the platform decide
+	}
+	if parentRank == targetRank {
+		return fmt.Errorf("the parent object %q is in the %s layer, the same as the target layer; a patch must be created in a lower layer than its parent (or use update to change the object itself)", parentID, parentLayer)
+	}
+	if parentRank > targetRank {
+		return fmt.Errorf("the parent object %q is in the %s layer, which is lower than the target layer %s; a patch must be created in a lower layer than its parent", parentID, parentLayer, targetLayer)
+	}
+	return nil
+}
+
+// defaultPatchLayer determines the target layer of a patch that was not specified on the command line:
+// it is the only layer allowed by the type that is lower than the parent object's layer. The parent
+// is fetched as seen from the lowest allowed layer, so its layer is the one it would be inherited from.
+// Returns an error if the target layer cannot be determined or is ambiguous.
+func defaultPatchLayer(client *api.Client, fqtn string, parentID string) (string, error) {
+	typeDef, err := fetchType(client, fqtn, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch type %q to determine the target layer: %v", fqtn, err)
+	}
+	allowed := allowedLayers(typeDef)
+	if len(allowed) == 0 {
+		return "", fmt.Errorf("type %q does not list its allowed layers", fqtn)
+	}
+
+	lowest := allowed[len(allowed)-1]
+	headers := map[string]string{
+		"layer-type": lowest,
+		"layer-id":   getCorrectLayerID(lowest, fqtn),
+	}
+	var res map[string]any
+	err = client.JSONGet(getObjectUrl(fqtn, parentID), &res, &api.Options{Headers: headers})
+	if api.IsNotFound(err) {
+		return "", fmt.Errorf("the parent object %q does not exist or is not visible from the %s layer", parentID, lowest)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the parent object %q: %v", parentID, err)
+	}
+
+	parentLayer, _ := res["layerType"].(string)
+	return choosePatchLayer(parentID, parentLayer, allowed)
+}
+
+// allowedLayers returns the known layers in which the type's objects can be created,
+// ordered from the highest to the lowest
+func allowedLayers(typeDef any) []string {
+	typeMap, _ := typeDef.(map[string]any)
+	list, _ := typeMap["allowedLayers"].([]any)
+
+	var layers []string
+	for _, l := range layerOrder {
+		for _, entry := range list {
+			if s, _ := entry.(string); s == string(l) {
+				layers = append(layers, s)
+				break
+			}
+		}
+	}
+	return layers
+}
+
+// choosePatchLayer returns the only allowed layer that is lower than the parent's layer
+func choosePatchLayer(parentID string, parentLayer string, allowed []string) (string, error) {
+	parentRank := layerRank(parentLayer)
+	if parentRank < 0 {
+		return "", fmt.Errorf("the layer of the parent object %q is not known", parentID)
+	}
+
+	var candidates []string
+	for _, l := range allowed {
+		if layerRank(l) > parentRank {
+			candidates = append(candidates, l)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("the parent object %q is in the %s layer and the type allows no lower layer", parentID, parentLayer)
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", fmt.Errorf("the parent object %q is in the %s layer and the patch can be created in any of the lower layers %v", parentID, parentLayer, strings.Join(candidates, ", "))
+	}
+}
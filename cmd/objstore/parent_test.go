@@ -0,0 +1,73 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckParentLayer(t *testing.T) {
+	assert.Nil(t, checkParentLayer("p", "SOLUTION", "TENANT"))
+	assert.Nil(t, checkParentLayer("p", "TENANT", "LOCALUSER"))
+	assert.NotNil(t, checkParentLayer("p", "TENANT", "TENANT"))
+	assert.NotNil(t, checkParentLayer("p", "LOCALUSER", "TENANT"))
+
+	// unknown layers are left to the platform
+	assert.Nil(t, checkParentLayer("p", "", "TENANT"))
+}
+
+func TestAllowedLayers(t *testing.T) {
+	typeDef := map[string]any{"allowedLayers": []any{"LOCALUSER", "SOLUTION", "TENANT", "OTHER"}}
+	assert.Equal(t, []string{"SOLUTION", "TENANT", "LOCALUSER"}, allowedLayers(typeDef))
+	assert.Nil(t, allowedLayers(map[string]any{}))
+	assert.Nil(t, allowedLayers(nil))
+}
+
+func TestChoosePatchLayer(t *testing.T) {
+	// a single lower layer is the default
+	layer, err := choosePatchLayer("p", "SOLUTION", []string{"SOLUTION", "TENANT"})
+	require.Nil(t, err)
+	assert.Equal(t, "TENANT", layer)
+
+	layer, err = choosePatchLayer("p", "TENANT", []string{"SOLUTION", "TENANT", "LOCALUSER"})
+	require.Nil(t, err)
+	assert.Equal(t, "LOCALUSER", layer)
+
+	// ambiguous
+	_, err = choosePatchLayer("p", "SOLUTION", []string{"SOLUTION", "TENANT", "LOCALUSER"})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "TENANT, LOCALUSER")
+
+	// no lower layer or unknown parent layer
+	_, err = choosePatchLayer("p", "TENANT", []string{"SOLUTION", "TENANT"})
+	assert.NotNil(t, err)
+	_, err = choosePatchLayer("p", "", []string{"TENANT"})
+	assert.NotNil(t, err)
+}
+
+func TestCanonicalLayerType(t *testing.T) {
+	assert.Equal(t, "TENANT", canonicalLayerType("tenant"))
+	assert.Equal(t, "GLOBALUSER", canonicalLayerType("GlobalUser"))
+	assert.Equal(t, "SOLUTION", canonicalLayerType("SOLUTION"))
+	assert.Equal(t, "nosuchlayer", canonicalLayerType("nosuchlayer"))
+
+	var lt layerType
+	assert.Nil(t, lt.Set("localuser"))
+	assert.Equal(t, localUser, lt)
+	assert.NotNil(t, lt.Set("nosuchlayer"))
+}
@@ -0,0 +1,221 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+const (
+	mergePatchFormat = "merge"
+	jsonPatchFormat  = "json"
+)
+
+var objStorePatchFieldCmd = &cobra.Command{
+	Use:   "patch-field",
+	Short: "Set or remove a single field of an existent knowledge object",
+	Long: `This command allows a single field of an existent knowledge object to be set to a new value or removed.
+
+	Usage:
+	fsoc objstore patch-field --type=<fully-qualified-typename>
+	--object-id=<object id>
+	--field=<dot-separated-field-path>
+	[--value=<json-value> | --remove]
+	[--patch-format=merge|json]
+	--layer-type=[SOLUTION|ACCOUNT|GLOBALUSER|TENANT|LOCALUSER]
+	--layer-id=<respective-layer-id>
+
+	Flags/Options:
+	--type - Flag to indicate the fully qualified type name of the object that you would like to patch
+	--object-id - Flag to indicate the ID of the object that you want to patch
+	--field - Flag to indicate the path of the field to patch, with nested fields separated by dots (e.g., data.obsolete)
+	--value - Flag to specify the new value of the field, in JSON format (strings that are not valid JSON are used as is)
+	--remove - Flag to remove the field instead of setting it. With merge patch, the field is set to null; with JSON patch, a remove operation is sent
+	--patch-format - OPTIONAL Flag to select the patch document format, merge (RFC 7386, default) or json (RFC 6902)
+	--layer-type - Flag to indicate the layer at which the object you would like to patch exists
+	--layer-id - OPTIONAL Flag to specify a custom layer ID for the object that you would like to patch.  This is calculated automatically for all layers currently supported but can be overridden with this flag`,
+
+	Args:             cobra.ExactArgs(0),
+	Run:              patchObjectField,
+	TraverseChildren: true,
+}
+
+func getPatchFieldObjectCmd() *cobra.Command {
+	objStorePatchFieldCmd.Flags().
+		String("type", "", "The fully qualified type name of the object")
+	_ = objStorePatchFieldCmd.MarkFlagRequired("type")
+
+	objStorePatchFieldCmd.Flags().
+		String("object-id", "", "The id of the knowledge object being patched")
+	_ = objStorePatchFieldCmd.MarkFlagRequired("object-id")
+
+	objStorePatchFieldCmd.Flags().
+		String("field", "", "The dot-separated path of the field to patch, e.g., data.obsolete")
+	_ = objStorePatchFieldCmd.MarkFlagRequired("field")
+
+	objStorePatchFieldCmd.Flags().
+		String("value", "", "The new value of the field, in JSON format")
+
+	objStorePatchFieldCmd.Flags().
+		Bool("remove", false, "Remove the field instead of setting a value")
+
+	objStorePatchFieldCmd.Flags().
+		String("patch-format", mergePatchFormat, fmt.Sprintf("The patch document format, one of %q or %q", mergePatchFormat, jsonPatchFormat))
+
+	objStorePatchFieldCmd.Flags().
+		String("layer-type", "", "The layer-type of the patched object")
+	_ = objStorePatchFieldCmd.MarkFlagRequired("layer-type")
+
+	objStorePatchFieldCmd.Flags().
+		String("layer-id", "", "The layer-id of the patched object. Optional for TENANT and SOLUTION layers ")
+
+	objStorePatchFieldCmd.MarkFlagsMutuallyExclusive("value", "remove")
+
+	return objStorePatchFieldCmd
+}
+
+func patchObjectField(cmd *cobra.Command, args []string) {
+	var err error
+
+	objType, _ := cmd.Flags().GetString("type")
+	objId, _ := cmd.Flags().GetString("object-id")
+	field, _ := cmd.Flags().GetString("field")
+	remove, _ := cmd.Flags().GetBool("remove")
+	patchFormat, _ := cmd.Flags().GetString("patch-format")
+
+	if !remove && !cmd.Flags().Changed("value") {
+		log.Error("Please specify either a new value with the --value flag or the --remove flag")
+		return
+	}
+	var value any
+	if !remove {
+		rawValue, _ := cmd.Flags().GetString("value")
+		value = parseFieldValue(rawValue)
+	}
+
+	path := splitFieldPath(field)
+	if len(path) == 0 {
+		log.Errorf("Invalid field path %q", field)
+		return
+	}
+
+	layerType, _ := cmd.Flags().GetString("layer-type")
+	layerType = canonicalLayerType(layerType)
+	layerID := getCorrectLayerID(layerType, objType)
+
+	if layerID == "" {
+		if !cmd.Flags().Changed("layer-id") {
+			log.Error("Unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
+			return
+		}
+		layerID, err = getLayerIDFlag(cmd)
+		if err != nil {
+			log.Errorf("%v", err)
+			return
+		}
+	}
+
+	headers := map[string]string{
+		"layer-type": layerType,
+		"layer-id":   layerID,
+	}
+
+	// build the patch document in the requested format
+	var patch any
+	switch patchFormat {
+	case mergePatchFormat:
+		patch = buildMergePatch(path, value) // nil value removes the field
+	case jsonPatchFormat:
+		patch = buildJSONPatch(path, value, remove)
+		headers["Content-Type"] = "application/json-patch+json"
+	default:
+		log.Errorf("Invalid --patch-format %q; must be one of %q or %q", patchFormat, mergePatchFormat, jsonPatchFormat)
+		return
+	}
+
+	var res any
+	objectUrl := fmt.Sprintf(getObjStoreObjectUrl()+"/%s/%s", objType, objId)
+
+	if remove {
+		output.PrintCmdStatus(cmd, fmt.Sprintf("Removing field %s from object %s\n", field, objId))
+	} else {
+		output.PrintCmdStatus(cmd, fmt.Sprintf("Setting field %s of object %s\n", field, objId))
+	}
+	err = apiClient(cmd).JSONPatch(objectUrl, patch, &res, &api.Options{Headers: headers})
+	if err != nil {
+		log.Errorf("Patching the object failed: %v", err.Error())
+		return
+	}
+	output.PrintCmdStatus(cmd, "Object was successfully patched!\n")
+}
+
+// parseFieldValue parses a value provided on the command line as JSON,
+// using it as a plain string if it is not valid JSON
+func parseFieldValue(rawValue string) any {
+	var value any
+	if err := json.Unmarshal([]byte(rawValue), &value); err != nil {
+		return rawValue
+	}
+	return value
+}
+
+func splitFieldPath(field string) []string {
+	path := []string{}
+	for _, segment := range strings.Split(field, ".") {
+		if segment == "" {
+			return nil
+		}
+		path = append(path, segment)
+	}
+	return path
+}
+
+// buildMergePatch creates a JSON merge patch (RFC 7386) document setting the field
+// at the path to the value; a nil value removes the field
+func buildMergePatch(path []string, value any) map[string]any {
+	patch := map[string]any{}
+	current := patch
+	for _, segment := range path[:len(path)-1] {
+		next := map[string]any{}
+		current[segment] = next
+		current = next
+	}
+	current[path[len(path)-1]] = value
+	return patch
+}
+
+// buildJSONPatch creates a JSON patch (RFC 6902) document with a single operation
+// that either removes the field at the path or adds/replaces it with the value
+func buildJSONPatch(path []string, value any, remove bool) []map[string]any {
+	// escape path segments per RFC 6901
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	pointer := ""
+	for _, segment := range path {
+		pointer += "/" + escaper.Replace(segment)
+	}
+
+	if remove {
+		return []map[string]any{{"op": "remove", "path": pointer}}
+	}
+	return []map[string]any{{"op": "add", "path": pointer, "value": value}}
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []string
+		value any
+		want  map[string]any
+	}{
+		{name: "top-level field", path: []string{"color"}, value: "red", want: map[string]any{"color": "red"}},
+		{
+			name:  "nested field",
+			path:  []string{"config", "display", "color"},
+			value: map[string]any{"r": float64(255)},
+			want:  map[string]any{"config": map[string]any{"display": map[string]any{"color": map[string]any{"r": float64(255)}}}},
+		},
+		{name: "removal", path: []string{"config", "color"}, value: nil, want: map[string]any{"config": map[string]any{"color": nil}}},
+		{name: "special characters kept", path: []string{"a/b", "c~d"}, value: true, want: map[string]any{"a/b": map[string]any{"c~d": true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildMergePatch(tt.path, tt.value))
+		})
+	}
+}
+
+func TestBuildJSONPatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   []string
+		value  any
+		remove bool
+		want   []map[string]any
+	}{
+		{name: "top-level field", path: []string{"color"}, value: "red", want: []map[string]any{{"op": "add", "path": "/color", "value": "red"}}},
+		{name: "nested field", path: []string{"config", "display", "color"}, value: float64(3), want: []map[string]any{{"op": "add", "path": "/config/display/color", "value": float64(3)}}},
+		{name: "removal", path: []string{"config", "color"}, remove: true, want: []map[string]any{{"op": "remove", "path": "/config/color"}}},
+		{name: "escaped tilde and slash", path: []string{"a/b", "c~d", "~/"}, value: "x", want: []map[string]any{{"op": "add", "path": "/a~1b/c~0d/~0~1", "value": "x"}}},
+		{name: "null value is set, not removed", path: []string{"color"}, value: nil, want: []map[string]any{{"op": "add", "path": "/color", "value": nil}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildJSONPatch(tt.path, tt.value, tt.remove))
+		})
+	}
+}
+
+func TestSplitFieldPath(t *testing.T) {
+	assert.Equal(t, []string{"config", "color"}, splitFieldPath("config.color"))
+	assert.Nil(t, splitFieldPath("config..color"))
+	assert.Nil(t, splitFieldPath(""))
+}
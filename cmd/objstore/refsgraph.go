@@ -0,0 +1,222 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// defaultRefsMaxDepth is the default number of reference levels followed by --include-refs-graph
+const defaultRefsMaxDepth = 3
+
+// refNode is an object in the references graph, identified by its type and ID
+type refNode struct {
+	Type       string `json:"type" yaml:"type"`
+	ID         string `json:"id" yaml:"id"`
+	Unresolved string `json:"unresolved,omitempty" yaml:"unresolved,omitempty"` // reason the object could not be fetched
+}
+
+func (n refNode) key() string {
+	return n.Type + "/" + n.ID
+}
+
+// refEdge is a reference from an object's field to another object; From and To are
+// the nodes' keys, <type>/<id>
+type refEdge struct {
+	From  string `json:"from" yaml:"from"`
+	To    string `json:"to" yaml:"to"`
+	Field string `json:"field" yaml:"field"`
+}
+
+// refsGraph is the graph of the objects reachable from the root object through reference fields
+type refsGraph struct {
+	Root      string    `json:"root" yaml:"root"`
+	MaxDepth  int       `json:"maxDepth" yaml:"maxDepth"`
+	Truncated bool      `json:"truncated" yaml:"truncated"` // references beyond the max depth were not followed
+	Nodes     []refNode `json:"nodes" yaml:"nodes"`
+	Edges     []refEdge `json:"edges" yaml:"edges"`
+}
+
+// buildRefsGraph follows the reference fields of the objects, breadth first, starting from the
+// root object and up to maxDepth references away from it. Each object is fetched only once, so
+// cycles end at an object already in the graph; objects that cannot be fetched are marked unresolved.
+func buildRefsGraph(rootType string, rootID string, specs []expandSpec, maxDepth int, fetch referenceFetcher) refsGraph {
+	root := refNode{Type: rootType, ID: rootID}
+	graph := refsGraph{Root: root.key(), MaxDepth: maxDepth, Nodes: []refNode{}, Edges: []refEdge{}}
+
+	type queued struct {
+		node  refNode
+		depth int
+	}
+	seen := map[string]bool{root.key(): true}
+	queue := []queued{{root, 0}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		obj, err := fetch(current.node.Type, current.node.ID)
+		if err != nil {
+			current.node.Unresolved = err.Error()
+			if api.IsNotFound(err) {
+				current.node.Unresolved = "not found"
+			}
+		}
+		graph.Nodes = append(graph.Nodes, current.node)
+		if err != nil {
+			continue
+		}
+
+		for _, spec := range specs {
+			ids := referenceIDs(obj, spec)
+			if current.depth >= maxDepth {
+				graph.Truncated = graph.Truncated || len(ids) > 0
+				continue
+			}
+			for _, id := range ids {
+				target := refNode{Type: spec.Type, ID: id}
+				graph.Edges = append(graph.Edges, refEdge{From: current.node.key(), To: target.key(), Field: strings.Join(spec.Path, ".")})
+				if !seen[target.key()] {
+					seen[target.key()] = true
+					queue = append(queue, queued{target, current.depth + 1})
+				}
+			}
+		}
+	}
+	return graph
+}
+
+// referenceIDs returns the IDs in the object's reference field, which may hold an ID or a list of IDs
+func referenceIDs(object any, spec expandSpec) []string {
+	objMap, _ := object.(map[string]any)
+	var value any = objMap["data"]
+	for _, key := range spec.Path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var ids []string
+		for _, item := range v {
+			if id, ok := item.(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	return nil
+}
+
+// formatRefsTree formats the graph as an indented tree from the root object. An object is
+// expanded only the first time it appears; later occurrences are marked as such.
+func formatRefsTree(graph refsGraph) string {
+	nodes := map[string]refNode{}
+	for _, n := range graph.Nodes {
+		nodes[n.key()] = n
+	}
+	edges := map[string][]refEdge{}
+	for _, e := range graph.Edges {
+		edges[e.From] = append(edges[e.From], e)
+	}
+
+	var sb strings.Builder
+	expanded := map[string]bool{}
+	onPath := map[string]bool{}
+	var visit func(key string, label string, indent string)
+	visit = func(key string, label string, indent string) {
+		sb.WriteString(indent + label + key)
+		switch {
+		case onPath[key]:
+			sb.WriteString(" (cycle)\n")
+			return
+		case expanded[key]:
+			sb.WriteString(" (see above)\n")
+			return
+		case nodes[key].Unresolved != "":
+			sb.WriteString(fmt.Sprintf(" (unresolved: %v)\n", nodes[key].Unresolved))
+			return
+		}
+		sb.WriteString("\n")
+
+		expanded[key] = true
+		onPath[key] = true
+		for _, e := range edges[key] {
+			visit(e.To, e.Field+": ", indent+"  ")
+		}
+		onPath[key] = false
+	}
+	visit(graph.Root, "", "")
+
+	if graph.Truncated {
+		sb.WriteString(fmt.Sprintf("References more than %v level(s) away from %v were not followed (use --max-depth to raise the limit)\n", graph.MaxDepth, graph.Root))
+	}
+	return sb.String()
+}
+
+// formatRefsDot formats the graph in the Graphviz DOT language
+func formatRefsDot(graph refsGraph) string {
+	var sb strings.Builder
+	sb.WriteString("digraph refs {\n")
+	for _, n := range graph.Nodes {
+		attrs := ""
+		if n.Unresolved != "" {
+			attrs = fmt.Sprintf(" [style=dashed, tooltip=%q]", "unresolved: "+n.Unresolved)
+		}
+		sb.WriteString(fmt.Sprintf("  %q%v;\n", n.key(), attrs))
+	}
+	for _, e := range graph.Edges {
+		sb.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", e.From, e.To, e.Field))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// getRefsGraph displays the graph of the objects referenced, transitively, by the object
+func getRefsGraph(cmd *cobra.Command, fqtn string, objID string, headers map[string]string, specs []expandSpec) error {
+	maxDepth, _ := cmd.Flags().GetInt("max-depth")
+	if maxDepth < 1 {
+		return fmt.Errorf("--max-depth must be at least 1")
+	}
+	graphFormat, _ := cmd.Flags().GetString("graph-format")
+	if graphFormat != "tree" && graphFormat != "dot" {
+		return fmt.Errorf("invalid --graph-format %q: valid values are tree and dot", graphFormat)
+	}
+
+	graph := buildRefsGraph(fqtn, objID, specs, maxDepth, newReferenceFetcher(apiClient(cmd), headers))
+	if reason := graph.Nodes[0].Unresolved; reason != "" {
+		return fmt.Errorf("failed to fetch object %q: %v", objID, reason)
+	}
+
+	switch {
+	case isMachineFormat(cmd):
+		output.PrintCmdOutput(cmd, graph)
+	case graphFormat == "dot":
+		output.PrintCmdStatus(cmd, formatRefsDot(graph))
+	default:
+		output.PrintCmdStatus(cmd, formatRefsTree(graph))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildRefsGraph(t *testing.T) {
+	objects := map[string]any{
+		"t:theme/a":  map[string]any{"data": map[string]any{"base": "b", "icons": map[string]any{"set": []any{"i1"}}}},
+		"t:theme/b":  map[string]any{"data": map[string]any{"base": "a"}}, // cycle back to a
+		"t:icons/i1": map[string]any{"data": map[string]any{}},
+	}
+	fetched := 0
+	fetch := func(fqtn string, id string) (any, error) {
+		fetched++
+		if obj, found := objects[fqtn+"/"+id]; found {
+			return obj, nil
+		}
+		return nil, errors.New("boom")
+	}
+	specs := []expandSpec{{Path: []string{"base"}, Type: "t:theme"}, {Path: []string{"icons", "set"}, Type: "t:icons"}}
+
+	graph := buildRefsGraph("t:theme", "a", specs, 3, fetch)
+	assert.Equal(t, 3, fetched)
+	assert.False(t, graph.Truncated)
+	assert.Equal(t, []refNode{{Type: "t:theme", ID: "a"}, {Type: "t:theme", ID: "b"}, {Type: "t:icons", ID: "i1"}}, graph.Nodes)
+	assert.Equal(t, []refEdge{
+		{From: "t:theme/a", To: "t:theme/b", Field: "base"},
+		{From: "t:theme/a", To: "t:icons/i1", Field: "icons.set"},
+		{From: "t:theme/b", To: "t:theme/a", Field: "base"},
+	}, graph.Edges)
+
+	assert.Equal(t, "t:theme/a\n  base: t:theme/b\n    base: t:theme/a (cycle)\n  icons.set: t:icons/i1\n", formatRefsTree(graph))
+	assert.Equal(t, `digraph refs {
+  "t:theme/a";
+  "t:theme/b";
+  "t:icons/i1";
+  "t:theme/a" -> "t:theme/b" [label="base"];
+  "t:theme/a" -> "t:icons/i1" [label="icons.set"];
+  "t:theme/b" -> "t:theme/a" [label="base"];
+}
+`, formatRefsDot(graph))
+}
+
+func TestBuildRefsGraphMaxDepth(t *testing.T) {
+	fetch := func(fqtn string, id string) (any, error) {
+		if id == "missing" {
+			return nil, errors.New("boom")
+		}
+		return map[string]any{"data": map[string]any{"next": id + "+", "other": "missing"}}, nil
+	}
+	specs := []expandSpec{{Path: []string{"next"}, Type: "t:x"}, {Path: []string{"other"}, Type: "t:x"}}
+
+	graph := buildRefsGraph("t:x", "a", specs, 1, fetch)
+	assert.True(t, graph.Truncated)
+	assert.Equal(t, []refNode{{Type: "t:x", ID: "a"}, {Type: "t:x", ID: "a+"}, {Type: "t:x", ID: "missing", Unresolved: "boom"}}, graph.Nodes)
+	assert.Equal(t, "t:x/a\n  next: t:x/a+\n  other: t:x/missing (unresolved: boom)\n"+
+		"References more than 1 level(s) away from t:x/a were not followed (use --max-depth to raise the limit)\n", formatRefsTree(graph))
+}
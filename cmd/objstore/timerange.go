@@ -0,0 +1,65 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+)
+
+// buildCreatedAtFilter creates a filter expression selecting objects created within
+// the given time range; either boundary may be empty. Returns an empty string if
+// no boundary is specified.
+func buildCreatedAtFilter(after, before string, now time.Time) (string, error) {
+	var afterTime, beforeTime time.Time
+	var err error
+	if after != "" {
+		if afterTime, err = cmdkit.ParseTimeBound(after, now); err != nil {
+			return "", fmt.Errorf("invalid --created-after value: %v", err)
+		}
+	}
+	if before != "" {
+		if beforeTime, err = cmdkit.ParseTimeBound(before, now); err != nil {
+			return "", fmt.Errorf("invalid --created-before value: %v", err)
+		}
+	}
+	if after != "" && before != "" && !afterTime.Before(beforeTime) {
+		return "", fmt.Errorf("--created-after (%v) must be earlier than --created-before (%v)",
+			afterTime.UTC().Format(time.RFC3339), beforeTime.UTC().Format(time.RFC3339))
+	}
+
+	filter := ""
+	if after != "" {
+		filter = fmt.Sprintf("createdAt gt %q", afterTime.UTC().Format(time.RFC3339))
+	}
+	if before != "" {
+		filter = combineFilters(filter, fmt.Sprintf("createdAt lt %q", beforeTime.UTC().Format(time.RFC3339)))
+	}
+	return filter, nil
+}
+
+// combineFilters joins two filter expressions with "and", either of which may be empty
+func combineFilters(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return fmt.Sprintf("(%v) and (%v)", a, b)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCreatedAtFilter(t *testing.T) {
+	now := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	filter, err := buildCreatedAtFilter("", "", now)
+	require.Nil(t, err)
+	assert.Equal(t, "", filter)
+
+	filter, err = buildCreatedAtFilter("24h", "", now)
+	require.Nil(t, err)
+	assert.Equal(t, `createdAt gt "2023-05-09T12:00:00Z"`, filter)
+
+	filter, err = buildCreatedAtFilter("2023-05-01T00:00:00Z", "1h", now)
+	require.Nil(t, err)
+	assert.Equal(t, `(createdAt gt "2023-05-01T00:00:00Z") and (createdAt lt "2023-05-10T11:00:00Z")`, filter)
+
+	_, err = buildCreatedAtFilter("1h", "24h", now)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "must be earlier than")
+
+	_, err = buildCreatedAtFilter("yesterday", "", now)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid --created-after value")
+}
+
+func TestCombineFilters(t *testing.T) {
+	assert.Equal(t, "a", combineFilters("a", ""))
+	assert.Equal(t, "b", combineFilters("", "b"))
+	assert.Equal(t, "(a) and (b)", combineFilters("a", "b"))
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// transformRule replaces all occurrences of a string in the values of an object
+type transformRule struct {
+	Find    string
+	Replace string
+}
+
+func (r transformRule) String() string {
+	return fmt.Sprintf("%q -> %q", r.Find, r.Replace)
+}
+
+// parseTransformRule parses a rule in the find=replace form
+func parseTransformRule(s string) (transformRule, error) {
+	find, replace, found := strings.Cut(s, "=")
+	if !found || find == "" {
+		return transformRule{}, fmt.Errorf("invalid transform %q: expected find=replace", s)
+	}
+	return transformRule{Find: find, Replace: replace}, nil
+}
+
+// loadTransformFile reads transform rules from a file, one find=replace rule
+// per line; blank lines and lines starting with # are skipped
+func loadTransformFile(path string) ([]transformRule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read the transform file: %v", err)
+	}
+	defer file.Close()
+
+	rules := []transformRule{}
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := parseTransformRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("%v at %v line %v", err, path, lineNo)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read the transform file: %v", err)
+	}
+	return rules, nil
+}
+
+// getTransformRules collects the transform rules from the --transform-file and --transform flags,
+// in this order
+func getTransformRules(cmd *cobra.Command) ([]transformRule, error) {
+	rules := []transformRule{}
+	if path := objectFilePath(cmd, "transform-file"); path != "" {
+		fileRules, err := loadTransformFile(path)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fileRules...)
+	}
+	values, _ := cmd.Flags().GetStringArray("transform")
+	for _, value := range values {
+		rule, err := parseTransformRule(value)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// applyTransforms applies the rules, in order, to all string values in the object, in place.
+// It returns the number of replacements made by each rule.
+func applyTransforms(object map[string]interface{}, rules []transformRule) []int {
+	counts := make([]int, len(rules))
+	for key, value := range object {
+		object[key] = transformValue(value, rules, counts)
+	}
+	return counts
+}
+
+func transformValue(value any, rules []transformRule, counts []int) any {
+	switch v := value.(type) {
+	case string:
+		for i, rule := range rules {
+			if n := strings.Count(v, rule.Find); n > 0 {
+				counts[i] += n
+				v = strings.ReplaceAll(v, rule.Find, rule.Replace)
+			}
+		}
+		return v
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = transformValue(item, rules, counts)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = transformValue(item, rules, counts)
+		}
+	}
+	return value
+}
+
+// describeTransforms lists the rules that were applied and their replacement counts
+func describeTransforms(rules []transformRule, counts []int) string {
+	applied := []string{}
+	for i, rule := range rules {
+		if counts[i] > 0 {
+			applied = append(applied, fmt.Sprintf("%v (%v)", rule, counts[i]))
+		}
+	}
+	if len(applied) == 0 {
+		return "no transforms applied"
+	}
+	return "transforms applied: " + strings.Join(applied, ", ")
+}
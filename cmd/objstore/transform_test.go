@@ -0,0 +1,59 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTransforms(t *testing.T) {
+	object := map[string]interface{}{
+		"id": "theme-tenant1",
+		"data": map[string]interface{}{
+			"owner":  "tenant1",
+			"refs":   []interface{}{"tenant1:a", "other", 42.0},
+			"count":  1.0,
+			"region": "us-east",
+		},
+	}
+	rules := []transformRule{{Find: "tenant1", Replace: "tenant2"}, {Find: "eu", Replace: "apac"}}
+
+	counts := applyTransforms(object, rules)
+	assert.Equal(t, []int{3, 0}, counts)
+	assert.Equal(t, map[string]interface{}{
+		"id": "theme-tenant2",
+		"data": map[string]interface{}{
+			"owner":  "tenant2",
+			"refs":   []interface{}{"tenant2:a", "other", 42.0},
+			"count":  1.0,
+			"region": "us-east",
+		},
+	}, object)
+	assert.Equal(t, `transforms applied: "tenant1" -> "tenant2" (3)`, describeTransforms(rules, counts))
+}
+
+func TestParseTransformRule(t *testing.T) {
+	rule, err := parseTransformRule("a=b=c")
+	require.Nil(t, err)
+	assert.Equal(t, transformRule{Find: "a", Replace: "b=c"}, rule)
+
+	_, err = parseTransformRule("=b")
+	require.NotNil(t, err)
+	_, err = parseTransformRule("ab")
+	require.NotNil(t, err)
+}
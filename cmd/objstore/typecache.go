@@ -0,0 +1,148 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// cachedType is the on-disk representation of a type definition, together with
+// the ETag the server returned for it
+type cachedType struct {
+	ETag string `json:"etag"`
+	Type any    `json:"type"`
+}
+
+// fetchType retrieves a type definition, using the on-disk cache when the server
+// confirms (via ETag) that the cached copy is still current. Set refresh to
+// ignore the cached copy and fetch the type unconditionally.
+func fetchType(client *api.Client, fqtn string, refresh bool) (any, error) {
+	cachePath := typeCachePath(client, fqtn)
+
+	// load cached copy, if any
+	var cached *cachedType
+	if !refresh && cachePath != "" {
+		cached = readCachedType(cachePath)
+	}
+
+	// fetch type, conditionally if there is a cached copy
+	headers := map[string]string{}
+	if cached != nil {
+		headers["If-None-Match"] = cached.ETag
+	}
+	var res any
+	options := api.Options{Headers: headers}
+	if err := client.JSONGet(getTypeUrl(fqtn), &res, &options); err != nil {
+		return nil, err
+	}
+	if options.ResponseStatusCode == http.StatusNotModified && cached != nil {
+		log.Infof("Type %q has not changed, using cached copy from %q", fqtn, cachePath)
+		return cached.Type, nil
+	}
+
+	// update cache if the server provided an ETag
+	etag := ""
+	if values := http.Header(options.ResponseHeaders).Values("ETag"); len(values) > 0 {
+		etag = values[0]
+	}
+	// nb: a type that changed (e.g., by a solution upgrade) replaces the cached copy; without an ETag,
+	// the cached copy is removed so that an outdated schema is never used
+	if cachePath != "" {
+		if etag != "" {
+			writeCachedType(cachePath, &cachedType{ETag: etag, Type: res})
+		} else if cached != nil {
+			_ = os.Remove(cachePath)
+		}
+	}
+
+	return res, nil
+}
+
+// typeCachePath returns the path of the cache file for a type, or empty string
+// if the cache is not available. Types are cached separately for each server and
+// tenant of the client's context, since solutions (and so their types) differ by tenant.
+func typeCachePath(client *api.Client, fqtn string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		log.Infof("Type cache is not available: %v", err)
+		return ""
+	}
+	cfg := client.Context
+	if cfg == nil {
+		cfg = config.GetCurrentContext()
+	}
+	server, tenant := "none", "none"
+	if cfg != nil && cfg.Server != "" {
+		server = cfg.Server
+	}
+	if client.BaseURL != "" {
+		server = client.BaseURL
+	}
+	if cfg != nil && cfg.Tenant != "" {
+		tenant = cfg.Tenant
+	}
+	return filepath.Join(dir, "fsoc", "types", sanitizeFileName(server), sanitizeFileName(tenant), sanitizeFileName(fqtn)+".json")
+}
+
+func sanitizeFileName(s string) string {
+	return strings.NewReplacer(":", "_", "/", "_", "\\", "_").Replace(s)
+}
+
+func readCachedType(path string) *cachedType {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil // not cached
+	}
+	var cached cachedType
+	if err := json.Unmarshal(data, &cached); err != nil || cached.ETag == "" {
+		log.Warnf("Ignoring invalid type cache file %q", path)
+		return nil
+	}
+	return &cached
+}
+
+func writeCachedType(path string, cached *cachedType) {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		log.Warnf("Failed to cache type: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		log.Warnf("Failed to create type cache directory: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		log.Warnf("Failed to write type cache file %q: %v", path, err)
+	}
+}
+
+// clearTypeCache removes all cached types for all servers and tenants
+func clearTypeCache() error {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return fmt.Errorf("type cache is not available: %v", err)
+	}
+	return os.RemoveAll(filepath.Join(dir, "fsoc", "types"))
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// typeVersion is a version of a type definition served by the test platform
+type typeVersion struct {
+	etag string
+	body string
+}
+
+// newTypePlatform starts a server that returns the current version of a type, honoring
+// conditional requests, and counts the full (not 304) responses
+func newTypePlatform(t *testing.T, current *typeVersion) (*api.Client, *int) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir()) // isolate the type cache
+	fullResponses := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if current.etag != "" && r.Header.Get("If-None-Match") == current.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses++
+		w.Header().Set("Content-Type", "application/json")
+		if current.etag != "" {
+			w.Header().Set("ETag", current.etag)
+		}
+		_, _ = w.Write([]byte(current.body))
+	})
+	return client, &fullResponses
+}
+
+func TestFetchTypeRefreshesChangedSchema(t *testing.T) {
+	current := &typeVersion{
+		etag: `"v1"`,
+		body: `{"name": "theme", "jsonSchema": {"properties": {"color": {"type": "string", "readOnly": true}}}}`,
+	}
+	client, fullResponses := newTypePlatform(t, current)
+	patch := map[string]interface{}{"color": "green"}
+
+	// the type is cached and revalidated
+	assert.ErrorContains(t, validatePatchFields(client, "preferences:theme", patch), "color")
+	assert.ErrorContains(t, validatePatchFields(client, "preferences:theme", patch), "color")
+	assert.Equal(t, 1, *fullResponses)
+
+	// a solution upgrade makes the field mutable; validation uses the new schema
+	current.etag = `"v2"`
+	current.body = `{"name": "theme", "jsonSchema": {"properties": {"color": {"type": "string"}}}}`
+	assert.Nil(t, validatePatchFields(client, "preferences:theme", patch))
+	assert.Equal(t, 2, *fullResponses)
+
+	cached := readCachedType(typeCachePath(client, "preferences:theme"))
+	require.NotNil(t, cached)
+	assert.Equal(t, `"v2"`, cached.ETag)
+}
+
+func TestFetchTypeDropsCacheWithoutETag(t *testing.T) {
+	current := &typeVersion{etag: `"v1"`, body: `{"name": "theme"}`}
+	client, _ := newTypePlatform(t, current)
+
+	_, err := fetchType(client, "preferences:theme", false)
+	require.Nil(t, err)
+	cachePath := typeCachePath(client, "preferences:theme")
+	require.NotNil(t, readCachedType(cachePath))
+
+	// a changed type without an ETag replaces the cached copy without being cached itself
+	current.etag = ""
+	current.body = `{"name": "theme", "version": 2}`
+	typeDef, err := fetchType(client, "preferences:theme", false)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "theme", "version": float64(2)}, typeDef)
+	_, err = os.Stat(cachePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestTypeCachePathByTenant(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	tenant1 := api.NewClient(&config.Context{Server: "https://example.com", Tenant: "tenant-1"})
+	tenant2 := api.NewClient(&config.Context{Server: "https://example.com", Tenant: "tenant-2"})
+	otherServer := api.NewClient(&config.Context{Server: "https://other.example.com", Tenant: "tenant-1"})
+
+	path := typeCachePath(tenant1, "preferences:theme")
+	assert.Equal(t, path, typeCachePath(api.NewClient(&config.Context{Server: "https://example.com", Tenant: "tenant-1"}), "preferences:theme"))
+	assert.NotEqual(t, path, typeCachePath(tenant2, "preferences:theme"))
+	assert.NotEqual(t, path, typeCachePath(otherServer, "preferences:theme"))
+}
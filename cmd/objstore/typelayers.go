@@ -0,0 +1,95 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+)
+
+// typeLayer describes a layer in which objects of a type can be created
+type typeLayer struct {
+	LayerType      string `json:"layerType" yaml:"layerType"`
+	DefaultLayerID string `json:"defaultLayerId,omitempty" yaml:"defaultLayerId,omitempty"` // layer ID used when --layer-id is omitted
+}
+
+func newTypeLayersCmd() *cobra.Command {
+	typeLayersCmd := &cobra.Command{
+		Use:   "type-layers",
+		Short: "List the layers in which objects of a type can be created.",
+		Long: `List the layers in which objects of a type can be created, as declared by the type definition.
+
+The layers are listed from the highest to the lowest; objects in lower layers inherit from (patch)
+objects in higher layers. For each layer, the layer ID used when --layer-id is omitted is shown;
+layers without a default require --layer-id to be specified. Use this to choose the --layer-type
+for the create and create-patch commands.`,
+		Example: `  # List the layers of a type
+  fsoc obj type-layers --type preferences:theme`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			listTypeLayers(cmd)
+		},
+	}
+
+	typeLayersCmd.Flags().String("type", "", "Fully qualified type name")
+	_ = typeLayersCmd.MarkFlagRequired("type")
+
+	typeLayersCmd.Flags().
+		Bool("refresh", false, "Ignore the locally cached copy of the type and fetch it from the server")
+
+	return typeLayersCmd
+}
+
+func listTypeLayers(cmd *cobra.Command) {
+	fqtn, _ := cmd.Flags().GetString("type")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+
+	typeDef, err := fetchType(apiClient(cmd), fqtn, refresh)
+	if err != nil {
+		log.Fatalf("Failed to fetch type %q: %v", fqtn, err)
+	}
+	layers := typeLayers(typeDef, fqtn)
+	if len(layers) == 0 {
+		log.Fatalf("Type %q does not list its allowed layers", fqtn)
+	}
+
+	lines := make([][]string, len(layers))
+	for i, l := range layers {
+		defaultID := l.DefaultLayerID
+		if defaultID == "" {
+			defaultID = "(--layer-id required)"
+		}
+		lines[i] = []string{l.LayerType, defaultID}
+	}
+	output.PrintCmdOutputCustom(cmd, map[string]any{"type": fqtn, "layers": layers}, &output.Table{
+		Headers: []string{"Layer Type", "Default Layer ID"},
+		Lines:   lines,
+		Footer:  fmt.Sprintf("Objects of type %v can be created in %v layer(s), listed from the highest to the lowest", fqtn, len(layers)),
+	})
+}
+
+// typeLayers returns the allowed layers of the type, from the highest to the lowest,
+// with the layer IDs used by default for the current context
+func typeLayers(typeDef any, fqtn string) []typeLayer {
+	layers := []typeLayer{}
+	for _, lt := range allowedLayers(typeDef) {
+		layers = append(layers, typeLayer{LayerType: lt, DefaultLayerID: getCorrectLayerID(lt, fqtn)})
+	}
+	return layers
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeLayers(t *testing.T) {
+	viper.Set("contexts", []map[string]any{{"name": "test", "tenant": "tenant-1", "user": "user-1"}})
+	viper.Set("current_context", "test")
+	t.Cleanup(func() {
+		viper.Set("contexts", nil)
+		viper.Set("current_context", nil)
+	})
+
+	typeDef := map[string]any{"allowedLayers": []any{"TENANT", "ACCOUNT", "SOLUTION", "LOCALUSER"}}
+	assert.Equal(t, []typeLayer{
+		{LayerType: "SOLUTION", DefaultLayerID: "preferences"},
+		{LayerType: "ACCOUNT"},
+		{LayerType: "TENANT", DefaultLayerID: "tenant-1"},
+		{LayerType: "LOCALUSER", DefaultLayerID: "user-1"},
+	}, typeLayers(typeDef, "preferences:theme"))
+
+	assert.Empty(t, typeLayers(map[string]any{}, "preferences:theme"))
+}
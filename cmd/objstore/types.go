@@ -0,0 +1,99 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func newTypesCmd() *cobra.Command {
+	typesCmd := &cobra.Command{
+		Use:     "types",
+		Short:   "List the types in the object store.",
+		Aliases: []string{"list-types"},
+		Long: `List the types defined in the object store, sorted by their fully qualified name.
+
+The table shows the fully qualified name of each type and the solution that defines it. Use
+--output json or --output yaml to get the complete type definitions, including their JSON
+schemas, e.g., to generate code from the type catalog. Use get-type (or describe-type) to
+display a single type.`,
+		Example: `  # List all types
+  fsoc obj types
+
+  # List the types defined by a solution, with their schemas
+  fsoc obj types --solution preferences --output json`,
+		Args: cobra.NoArgs,
+		RunE: listTypes,
+	}
+
+	typesCmd.Flags().String("solution", "", "List only the types defined by the given solution")
+
+	return typesCmd
+}
+
+func listTypes(cmd *cobra.Command, args []string) error {
+	solution, _ := cmd.Flags().GetString("solution")
+
+	types := []any{}
+	options := api.Options{ItemHandler: func(item any) error {
+		if solution == "" || typeSolution(item) == solution {
+			types = append(types, item)
+		}
+		return nil
+	}}
+	var res any
+	if err := apiClient(cmd).JSONGetCollection(getTypeListUrl(), &res, &options); err != nil {
+		return fmt.Errorf("Platform API call failed: %w", err)
+	}
+	sort.SliceStable(types, func(i, j int) bool { return typeName(types[i]) < typeName(types[j]) })
+
+	lines := make([][]string, len(types))
+	for i, t := range types {
+		lines[i] = []string{typeName(t), typeSolution(t)}
+	}
+	footer := fmt.Sprintf("%v type(s)", len(types))
+	if options.CollectionTruncated {
+		footer += fmt.Sprintf("\n(results truncated to %v items; use --max-items to raise the limit)", api.GetMaxCollectionItems())
+	}
+	output.PrintCmdOutputCustom(cmd, map[string]any{"items": types, "total": len(types)}, &output.Table{
+		Headers: []string{"Type", "Solution"},
+		Lines:   lines,
+		Footer:  footer,
+	})
+	return nil
+}
+
+// typeName returns the fully qualified name of a type definition, <solution>:<type>
+func typeName(typeDef any) string {
+	def, _ := typeDef.(map[string]any)
+	name, _ := def["name"].(string)
+	if solution := typeSolution(typeDef); solution != "" {
+		return solution + ":" + name
+	}
+	return name
+}
+
+// typeSolution returns the name of the solution that defines the type
+func typeSolution(typeDef any) string {
+	def, _ := typeDef.(map[string]any)
+	solution, _ := def["solution"].(string)
+	return solution
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func runTypes(t *testing.T, format string, solution string) string {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": [
+			{"name": "theme", "solution": "preferences", "jsonSchema": {"properties": {"color": {"type": "string"}}}},
+			{"name": "solution", "solution": "extensibility"},
+			{"name": "menu", "solution": "preferences"}
+		]}`))
+	})
+	cmd := newTypesCmd()
+	cmd.Flags().String("output", format, "")
+	cmd.Flags().String("fields", "", "")
+	if solution != "" {
+		require.Nil(t, cmd.Flags().Set("solution", solution))
+	}
+	var out strings.Builder
+	cmd.SetOut(&out)
+	cmd.SetContext(api.WithClient(context.Background(), client))
+
+	require.Nil(t, cmd.RunE(cmd, nil))
+	return out.String()
+}
+
+func TestListTypes(t *testing.T) {
+	out := runTypes(t, "table", "")
+	assert.Contains(t, out, "extensibility:solution")
+	assert.True(t, strings.Index(out, "preferences:menu") < strings.Index(out, "preferences:theme"), "sorted by name")
+	assert.Contains(t, out, "3 type(s)")
+
+	// the json form has the complete definitions
+	out = runTypes(t, "json", "preferences")
+	assert.Contains(t, out, `"jsonSchema":{"properties":{"color":{"type":"string"}}}`)
+	assert.Contains(t, out, `"total":2`)
+	assert.NotContains(t, out, "extensibility")
+}
+
+func TestTypeName(t *testing.T) {
+	assert.Equal(t, "preferences:theme", typeName(map[string]any{"name": "theme", "solution": "preferences"}))
+	assert.Equal(t, "theme", typeName(map[string]any{"name": "theme"}))
+	assert.Equal(t, "", typeName(nil))
+}
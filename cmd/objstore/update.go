@@ -15,10 +15,7 @@
 package objstore
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
-	"os"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
@@ -30,7 +27,7 @@ import (
 var objStoreUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update an existent knowledge object",
-	Long: `This command allows the an existent knowledge object to be updated according to the fields and values provided in a .json file.
+	Long: `This command allows the an existent knowledge object to be updated according to the fields and values provided in a .json or .yaml file.
 
 	Usage:
 	fsoc objstore update --type=<fully-qualified-typename> 
@@ -42,7 +39,9 @@ var objStoreUpdateCmd = &cobra.Command{
 	Flags/Options:
 	--type - Flag to indicate the fully qualified type name of the object that you would like to update
 	--object-id - Flag to indicate the ID of the object that you want to update
-	--object-file - Flag to indicate the fully qualified path (from your root directory) to the file containing the definition of the object that you want to update. Please note that update internally calls HTTP PUT so you will need to specify all fields in the object (even if you are updating just one field)
+	--object-file - Flag to indicate the fully qualified path (from your root directory) to the file containing the definition of the object that you want to update. Please note that update internally calls HTTP PUT so you will need to specify all fields in the object (even if you are updating just one field). Also available as --request-body-file
+	--changes-file - OPTIONAL Flag to update only some fields instead, specifying the path to a json or yaml file with the changes as a JSON merge patch (a null value removes a field). The object is fetched, the changes are applied to it and the result replaces the object only if it has not changed in the meantime
+	--retry-on-conflict - OPTIONAL Flag to specify how many times the changes are re-applied to the latest version of the object if it was changed concurrently (409 or 412 response; requires --changes-file)
 	--layer-type - Flag to indicate the layer at which the object you would like to update exists
 	--layer-id - OPTIONAL Flag to specify a custom layer ID for the object that you would like to update.  This is calculated automatically for all layers currently supported but can be overridden with this flag`,
 
@@ -61,7 +60,7 @@ func getUpdateObjectCmd() *cobra.Command {
 	_ = objStoreUpdateCmd.MarkPersistentFlagRequired("type")
 
 	objStoreUpdateCmd.Flags().
-		String("object-file", "", "The fully qualified path to the json file containing the knowledge object data definition")
+		String("object-file", "", "The fully qualified path to the json or yaml file containing the knowledge object data definition")
 	_ = objStoreUpdateCmd.MarkPersistentFlagRequired("objectFile")
 
 	objStoreUpdateCmd.Flags().
@@ -71,6 +70,14 @@ func getUpdateObjectCmd() *cobra.Command {
 	objStoreUpdateCmd.Flags().
 		String("layer-id", "", "The layer-id of the updated object. Optional for TENANT and SOLUTION layers ")
 
+	objStoreUpdateCmd.Flags().
+		String("changes-file", "", "The path to a json or yaml file with the fields to change (JSON merge patch), applied to the current object")
+	objStoreUpdateCmd.Flags().
+		Int("retry-on-conflict", 0, "Number of times to re-apply the changes to the latest object if it was changed concurrently (requires --changes-file)")
+	objStoreUpdateCmd.MarkFlagsMutuallyExclusive("object-file", "changes-file")
+
+	useRequestBodyFileAlias(objStoreUpdateCmd)
+
 	return objStoreUpdateCmd
 
 }
@@ -78,23 +85,26 @@ func getUpdateObjectCmd() *cobra.Command {
 func updateObject(cmd *cobra.Command, args []string) {
 	objType, _ := cmd.Flags().GetString("type")
 
-	objJsonFilePath, _ := cmd.Flags().GetString("object-file")
-	objectFile, err := os.Open(objJsonFilePath)
-	if err != nil {
-		log.Errorf("Can't find the object definition file named %s", objJsonFilePath)
+	changesFilePath := objectFilePath(cmd, "changes-file")
+	retries, _ := cmd.Flags().GetInt("retry-on-conflict")
+	if retries < 0 || (retries > 0 && changesFilePath == "") {
+		log.Errorf("--retry-on-conflict requires --changes-file and a non-negative number of retries")
 		return
 	}
-	defer objectFile.Close()
 
-	objectBytes, _ := io.ReadAll(objectFile)
-	var objectStruct map[string]interface{}
-	err = json.Unmarshal(objectBytes, &objectStruct)
+	objJsonFilePath := objectFilePath(cmd, "object-file")
+	if changesFilePath != "" {
+		objJsonFilePath = changesFilePath
+	}
+	warnLargeObjectFile(cmd, objJsonFilePath)
+	objectStruct, err := readObjectFile(objJsonFilePath)
 	if err != nil {
-		log.Errorf("Can't generate a %s object from the %s file. Make sure the object definition has all the required field and is valid according to the type definition.")
+		log.Errorf("Can't generate a %s object from the %s file: %v", objType, objJsonFilePath, err)
 		return
 	}
 
 	layerType, _ := cmd.Flags().GetString("layer-type")
+	layerType = canonicalLayerType(layerType)
 	layerID := getCorrectLayerID(layerType, objType)
 
 	if layerID == "" {
@@ -102,9 +112,9 @@ func updateObject(cmd *cobra.Command, args []string) {
 			log.Error("Unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
 			return
 		}
-		layerID, err = cmd.Flags().GetString("layer-id")
+		layerID, err = getLayerIDFlag(cmd)
 		if err != nil {
-			log.Errorf("error trying to get %q flag value: %w", "layer-id", err)
+			log.Errorf("%v", err)
 			return
 		}
 	}
@@ -114,13 +124,23 @@ func updateObject(cmd *cobra.Command, args []string) {
 		"layer-id":   layerID,
 	}
 
-	var res any
 	objId, _ := cmd.Flags().GetString("object-id")
+	if changesFilePath != "" {
+		output.PrintCmdStatus(cmd, fmt.Sprintf("Applying the changes from %s to object %s\n", changesFilePath, objId))
+		if err := updateWithChanges(apiClient(cmd), objType, objId, headers, objectStruct, retries); err != nil {
+			log.Errorf("Failed to update the object: %v", err)
+			return
+		}
+		output.PrintCmdStatus(cmd, "Object update was done successfully!\n")
+		return
+	}
+
+	var res any
 	urlStrf := getObjStoreObjectUrl() + "/%s/%s"
 	objectUrl := fmt.Sprintf(urlStrf, objType, objId)
 
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Replacing object %s with the new definition from %s \n", objId, objJsonFilePath))
-	err = api.JSONPut(objectUrl, objectStruct, &res, &api.Options{Headers: headers})
+	err = apiClient(cmd).JSONPut(objectUrl, objectStruct, &res, &api.Options{Headers: headers})
 	if err != nil {
 		log.Errorf("Solution command failed: %v", err.Error())
 		return
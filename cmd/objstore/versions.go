@@ -0,0 +1,67 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// getObjectVersions displays the given historical version of an object or, if
+// version is empty, the list of the object's available versions
+func getObjectVersions(cmd *cobra.Command, fqtn string, objID string, version string, headers map[string]string) error {
+	versionsUrl := getObjectVersionsUrl(fqtn, objID)
+	if version != "" {
+		versionsUrl += "/" + url.PathEscape(version)
+	}
+
+	var res any
+	if err := apiClient(cmd).JSONGet(versionsUrl, &res, &api.Options{Headers: headers}); err != nil {
+		if api.IsNotFound(err) {
+			return describeMissingVersion(apiClient(cmd), fqtn, objID, version, headers)
+		}
+		return fmt.Errorf("Platform API call failed: %w", err)
+	}
+	output.PrintCmdOutput(cmd, res)
+	return nil
+}
+
+// describeMissingVersion determines why the requested version (or list of versions)
+// was not found: the object doesn't exist, the version doesn't exist or the type
+// doesn't keep versions of its objects
+func describeMissingVersion(client *api.Client, fqtn string, objID string, version string, headers map[string]string) error {
+	var res any
+	if err := client.JSONGet(getObjectUrl(fqtn, objID), &res, &api.Options{Headers: headers}); err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("object %q of type %q does not exist", objID, fqtn)
+		}
+		return err
+	}
+	if version != "" {
+		if err := client.JSONGet(getObjectVersionsUrl(fqtn, objID), &res, &api.Options{Headers: headers}); err == nil {
+			return fmt.Errorf("version %q of object %q does not exist; use --list-versions to see the available versions", version, objID)
+		}
+	}
+	return fmt.Errorf("object type %q is not versioned", fqtn)
+}
+
+func getObjectVersionsUrl(fqtn, objId string) string {
+	return getObjectUrl(fqtn, objId) + "/versions"
+}
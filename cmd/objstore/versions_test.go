@@ -0,0 +1,81 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objstore
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// runVersions gets the object's version (or list of versions) from a platform that has the
+// given paths, matched by suffix, and returns the output
+func runVersions(t *testing.T, version string, paths map[string]string) (string, error) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		for suffix, body := range paths {
+			if strings.HasSuffix(r.URL.Path, suffix) {
+				if body == "" {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+				_, _ = w.Write([]byte(body))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", "json", "")
+	cmd.Flags().String("fields", "", "")
+	var out strings.Builder
+	cmd.SetOut(&out)
+	cmd.SetContext(api.WithClient(context.Background(), client))
+
+	err := getObjectVersions(cmd, "preferences:theme", "mytheme", version, nil)
+	return out.String(), err
+}
+
+func TestGetObjectVersions(t *testing.T) {
+	out, err := runVersions(t, "", map[string]string{"/mytheme/versions": `{"items": [{"version": "1"}]}`})
+	require.Nil(t, err)
+	assert.Contains(t, out, `"version":"1"`)
+
+	out, err = runVersions(t, "1", map[string]string{"/mytheme/versions/1": `{"id": "mytheme"}`})
+	require.Nil(t, err)
+	assert.Contains(t, out, `"id":"mytheme"`)
+}
+
+func TestGetObjectVersionsMissing(t *testing.T) {
+	_, err := runVersions(t, "", map[string]string{})
+	assert.ErrorContains(t, err, `object "mytheme" of type "preferences:theme" does not exist`)
+
+	_, err = runVersions(t, "2", map[string]string{"/mytheme": `{}`, "/mytheme/versions": `{"items": []}`})
+	assert.ErrorContains(t, err, `version "2" of object "mytheme" does not exist`)
+
+	_, err = runVersions(t, "", map[string]string{"/mytheme": `{}`})
+	assert.ErrorContains(t, err, `object type "preferences:theme" is not versioned`)
+}
+
+func TestGetObjectVersionsFailure(t *testing.T) {
+	_, err := runVersions(t, "", map[string]string{"/mytheme/versions": ""})
+	assert.True(t, api.HasStatus(err, http.StatusBadRequest))
+}
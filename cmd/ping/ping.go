@@ -0,0 +1,103 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ping
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// pingType is a small type that exists on every tenant; fetching it verifies
+// that the platform is reachable and that the credentials are accepted
+const pingType = "extensibility:solution"
+
+// pingCmd represents the ping command
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check that the platform is reachable and the credentials work",
+	Long: `This command issues a lightweight authenticated request to the platform and reports
+whether it succeeded and how long it took. It exits with a non-zero status if the request fails,
+making it useful as a preflight check in scripts and CI.
+
+Usage:
+	fsoc ping`,
+	Args:             cobra.NoArgs,
+	Run:              ping,
+	TraverseChildren: true,
+}
+
+type pingResult struct {
+	Server    string `json:"server"`
+	Tenant    string `json:"tenant"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+func NewSubCmd() *cobra.Command {
+	return pingCmd
+}
+
+func ping(cmd *cobra.Command, args []string) {
+	cfg := config.GetCurrentContext()
+
+	var res any
+	start := time.Now()
+	err := api.JSONGet(config.GetObjStoreBasePath()+"/types/"+pingType, &res, nil)
+	latency := time.Since(start)
+	if err != nil {
+		log.Fatalf("Ping failed: %v", describePingError(err))
+	}
+
+	result := pingResult{Server: cfg.Server, Tenant: cfg.Tenant, LatencyMs: latency.Milliseconds()}
+	output.PrintCmdOutputCustom(cmd, result, &output.Table{
+		Headers: []string{"Server", "Tenant", "Latency"},
+		Lines:   [][]string{{result.Server, result.Tenant, latency.Round(time.Millisecond).String()}},
+		Detail:  true,
+	})
+}
+
+// describePingError explains the reason of a failed ping
+func describePingError(err error) error {
+	var netErr net.Error
+	var problem api.Problem
+	var respErr api.ResponseError
+	status := 0
+	switch {
+	case errors.As(err, &netErr):
+		return fmt.Errorf("could not reach the platform: %w", err)
+	case errors.As(err, &problem):
+		status = problem.Status
+	case errors.As(err, &respErr):
+		status = respErr.StatusCode
+	default:
+		return err
+	}
+
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("the platform rejected the credentials (status %v); try 'fsoc login': %w", status, err)
+	default:
+		return fmt.Errorf("the platform returned status %v: %w", status, err)
+	}
+}
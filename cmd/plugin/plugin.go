@@ -0,0 +1,256 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugin provides support for running external fsoc-<name> executables
+// found on the PATH as fsoc subcommands
+package plugin
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/output"
+)
+
+// pluginPrefix is the file name prefix of plugin executables
+const pluginPrefix = "fsoc-"
+
+// tokenPluginsEnv is the environment variable that lists the plugins that receive the access token
+const tokenPluginsEnv = "FSOC_PLUGIN_TOKEN"
+
+// Plugin is an external executable that can be run as an fsoc subcommand
+type Plugin struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Shadowed bool   `json:"shadowed,omitempty"` // true if a built-in command has the same name
+}
+
+// NewSubCmd returns the plugin command, which manages plugins
+func NewSubCmd() *cobra.Command {
+	pluginCmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage fsoc plugins",
+		Long: `fsoc can be extended with external commands (plugins). Any executable on the PATH whose name
+starts with "fsoc-" can be run as an fsoc subcommand, e.g., "fsoc-hello" can be run as "fsoc hello".
+All arguments after the plugin name are passed to the plugin as is. Built-in commands take precedence
+over plugins with the same name.
+
+The plugin receives the current fsoc context in the following environment variables (those that
+are not defined in the context are not set): FSOC_PROFILE, FSOC_SERVER, FSOC_TENANT, FSOC_USER
+and FSOC_CONFIG (path of the config file).
+
+The context's access token is passed in FSOC_TOKEN only to the plugins you trust with it, listed
+by name in the ` + tokenPluginsEnv + ` environment variable, separated by commas (e.g.,
+` + tokenPluginsEnv + `=hello,deploy).`,
+	}
+	pluginCmd.AddCommand(newListCmd())
+	return pluginCmd
+}
+
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the plugins found on the PATH",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			plugins := Discover()
+			shadowed := map[string]bool{}
+			for _, c := range cmd.Root().Commands() {
+				shadowed[c.Name()] = true
+			}
+			lines := [][]string{}
+			for i := range plugins {
+				plugins[i].Shadowed = shadowed[plugins[i].Name] && !isPluginCmd(cmd.Root(), plugins[i].Name)
+				note := ""
+				if plugins[i].Shadowed {
+					note = "shadowed by built-in command"
+				}
+				lines = append(lines, []string{plugins[i].Name, plugins[i].Path, note})
+			}
+			output.PrintCmdOutputCustom(cmd, plugins, &output.Table{
+				Headers: []string{"Name", "Path", "Note"},
+				Lines:   lines,
+			})
+		},
+		Annotations: map[string]string{config.AnnotationForConfigBypass: ""},
+	}
+}
+
+// Discover returns the plugins found on the PATH, sorted by name. If several
+// executables have the same name, the first one on the PATH is used.
+func Discover() []Plugin {
+	found := map[string]Plugin{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // missing or unreadable directories are skipped, as the shell does
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry)
+			if !ok {
+				continue
+			}
+			if _, exists := found[name]; !exists {
+				found[name] = Plugin{Name: name, Path: filepath.Join(dir, entry.Name())}
+			}
+		}
+	}
+
+	plugins := make([]Plugin, 0, len(found))
+	for _, p := range found {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// pluginName returns the command name of a plugin executable
+func pluginName(entry os.DirEntry) (string, bool) {
+	fileName := entry.Name()
+	if !strings.HasPrefix(fileName, pluginPrefix) || entry.IsDir() {
+		return "", false
+	}
+	info, err := entry.Info()
+	if err != nil {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		if !strings.EqualFold(filepath.Ext(fileName), ".exe") {
+			return "", false
+		}
+		fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	} else if info.Mode()&0111 == 0 {
+		return "", false // not executable
+	}
+	name := strings.TrimPrefix(fileName, pluginPrefix)
+	return name, name != ""
+}
+
+// pluginAnnotation marks the commands that run plugins
+const pluginAnnotation = "plugin/path"
+
+func isPluginCmd(root *cobra.Command, name string) bool {
+	for _, c := range root.Commands() {
+		if c.Name() == name {
+			_, ok := c.Annotations[pluginAnnotation]
+			return ok
+		}
+	}
+	return false
+}
+
+// RegisterPlugins adds a subcommand to the root command for each plugin found on the PATH,
+// except for plugins whose name is the same as the name of a built-in command
+func RegisterPlugins(root *cobra.Command) {
+	builtin := map[string]bool{"help": true, "completion": true}
+	for _, c := range root.Commands() {
+		builtin[c.Name()] = true
+		for _, alias := range c.Aliases {
+			builtin[alias] = true
+		}
+	}
+
+	for _, p := range Discover() {
+		if !builtin[p.Name] {
+			root.AddCommand(newPluginCmd(p))
+		}
+	}
+}
+
+func newPluginCmd(p Plugin) *cobra.Command {
+	return &cobra.Command{
+		Use:                p.Name,
+		Short:              "Plugin " + p.Path,
+		DisableFlagParsing: true,
+		Annotations: map[string]string{
+			pluginAnnotation:                 p.Path,
+			config.AnnotationForConfigBypass: "", // plugins may work without a context
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if code := runPlugin(p, args); code != 0 {
+				return cmdkit.ExitWithCode(cmd, code)
+			}
+			return nil
+		},
+	}
+}
+
+// runPlugin runs the plugin with the given arguments and the current context
+// in its environment, returning the plugin's exit code
+func runPlugin(p Plugin, args []string) int {
+	log.WithFields(log.Fields{"plugin": p.Path, "args": args}).Info("Running plugin")
+
+	c := exec.Command(p.Path, args...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(), contextEnv(p)...)
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		log.Errorf("Failed to run plugin %q: %v", p.Path, err)
+		return 1
+	}
+	return 0
+}
+
+// contextEnv returns the environment variables that pass the current context to a plugin.
+// The access token is included only if the plugin is allowed to receive it (see tokenAllowed).
+func contextEnv(p Plugin) []string {
+	env := []string{}
+	add := func(name, value string) {
+		if value != "" {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	add("FSOC_CONFIG", viper.ConfigFileUsed())
+	if cfg := config.GetCurrentContext(); cfg != nil {
+		add("FSOC_PROFILE", cfg.Name)
+		add("FSOC_SERVER", cfg.Server)
+		add("FSOC_TENANT", cfg.Tenant)
+		add("FSOC_USER", cfg.User)
+		if tokenAllowed(p.Name) {
+			add("FSOC_TOKEN", cfg.Token)
+		} else if cfg.Token != "" {
+			log.Infof("Not passing the access token to plugin %q; add it to %v to pass it", p.Name, tokenPluginsEnv)
+		}
+	}
+	return env
+}
+
+// tokenAllowed returns true if the plugin is listed in the tokenPluginsEnv environment variable
+func tokenAllowed(name string) bool {
+	for _, allowed := range strings.Split(os.Getenv(tokenPluginsEnv), ",") {
+		if strings.TrimSpace(allowed) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+)
+
+func writeFile(t *testing.T, dir, name string, mode os.FileMode) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 0\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses unix file modes")
+	}
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	hello := writeFile(t, dir1, "fsoc-hello", 0755)
+	writeFile(t, dir2, "fsoc-hello", 0755) // shadowed by the one earlier on the PATH
+	world := writeFile(t, dir2, "fsoc-world", 0755)
+	writeFile(t, dir1, "fsoc-notexec", 0644)
+	writeFile(t, dir1, "other", 0755)
+	t.Setenv("PATH", dir1+string(os.PathListSeparator)+dir2)
+
+	assert.Equal(t, []Plugin{
+		{Name: "hello", Path: hello},
+		{Name: "world", Path: world},
+	}, Discover())
+}
+
+func TestRegisterPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses unix file modes")
+	}
+	dir := t.TempDir()
+	writeFile(t, dir, "fsoc-hello", 0755)
+	writeFile(t, dir, "fsoc-solution", 0755)
+	t.Setenv("PATH", dir)
+
+	root := &cobra.Command{Use: "fsoc"}
+	builtin := &cobra.Command{Use: "solution", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(builtin)
+	RegisterPlugins(root)
+
+	names := []string{}
+	for _, c := range root.Commands() {
+		names = append(names, c.Name())
+	}
+	assert.Equal(t, []string{"hello", "solution"}, names)
+	assert.True(t, isPluginCmd(root, "hello"))
+	assert.False(t, isPluginCmd(root, "solution"))
+}
+
+func TestPluginExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "fsoc-fail")
+	require.Nil(t, os.WriteFile(path, []byte("#!/bin/sh\nexit 3\n"), 0755))
+
+	cmd := newPluginCmd(Plugin{Name: "fail", Path: path})
+	err := cmd.RunE(cmd, nil)
+	var exitErr cmdkit.ExitCodeError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.Code)
+
+	ok := newPluginCmd(Plugin{Name: "hello", Path: writeFile(t, t.TempDir(), "fsoc-hello", 0755)})
+	assert.Nil(t, ok.RunE(ok, nil))
+}
+
+func TestContextEnvToken(t *testing.T) {
+	viper.Set("contexts", []map[string]any{{"name": "test", "server": "platform.invalid", "token": "secret"}})
+	viper.Set("current_context", "test")
+	t.Cleanup(func() {
+		viper.Set("contexts", nil)
+		viper.Set("current_context", "")
+	})
+
+	assert.NotContains(t, contextEnv(Plugin{Name: "hello"}), "FSOC_TOKEN=secret")
+
+	t.Setenv(tokenPluginsEnv, "deploy, hello")
+	assert.Contains(t, contextEnv(Plugin{Name: "hello"}), "FSOC_TOKEN=secret")
+	assert.Contains(t, contextEnv(Plugin{Name: "hello"}), "FSOC_SERVER=platform.invalid")
+	assert.NotContains(t, contextEnv(Plugin{Name: "hell"}), "FSOC_TOKEN=secret")
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
@@ -26,13 +27,20 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/cmd/plugin"
 	"github.com/cisco-open/fsoc/cmd/version"
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
 )
 
 var cfgFile string
 var cfgProfile string
 var outputFormat string
 
+// cancelDeadline releases the resources of the command's deadline, if any (see --deadline)
+var cancelDeadline context.CancelFunc = func() {}
+
 // rootCmd represents the base command when called without any subcommands
 // TODO: replace github link "for more info" with Cisco DevNet link for fsoc once published
 var rootCmd = &cobra.Command{
@@ -49,6 +57,7 @@ $ fsoc login
 $ fsoc uql query "FETCH id, type, attributes FROM entities(k8s:workload)"
 $ fsoc solution list
 $ fsoc solution list -o json
+$ fsoc --profile prod solution list
 
 For more information, see https://github.com/cisco-open/fsoc 
 
@@ -61,7 +70,26 @@ NOTE: fsoc is in alpha; breaking changes may occur`,
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute(ctx context.Context) error {
-	return rootCmd.ExecuteContext(ctx)
+	// plugins are registered last, so that built-in commands take precedence
+	plugin.RegisterPlugins(rootCmd)
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	defer cancelDeadline()
+
+	// a command stopped by displaying its request in explain mode has not failed
+	if api.RequestExplained() {
+		return nil
+	}
+
+	// report a deadline even if the command stopped cleanly when its requests were aborted
+	if cmd != nil {
+		if deadlineErr := cmdkit.DeadlineError(cmd.Context()); deadlineErr != nil {
+			deadline, _ := cmd.Flags().GetDuration("deadline")
+			log.Errorf("Command deadline exceeded: the command did not complete within %v", deadline)
+			return deadlineErr
+		}
+	}
+	return err
 }
 
 func init() {
@@ -72,10 +100,34 @@ func init() {
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.fsoc.yaml)")
-	rootCmd.PersistentFlags().StringVar(&cfgProfile, "profile", "", "access profile (default is current or \"default\")")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "auto", "output format (auto, table, detail, json, yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgProfile, "profile", "", "access profile to use for this command only (default is current or \"default\")")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "auto", fmt.Sprintf("output format (%v; default is the context's default output, if set, or auto)", strings.Join(output.Formats, ", ")))
 	rootCmd.PersistentFlags().String("fields", "", "perform specified fields transform/extract JQ expression")
+	rootCmd.PersistentFlags().String(output.SelectFlag, "", "jq expression (not JMESPath) to extract or reshape the output data before it is displayed, e.g., '[.items[] | select(.data.isSystem) | .id]' (overrides --fields)")
+	rootCmd.PersistentFlags().Bool(output.NoHeadersFlag, false, "omit the headers and footers of table output, e.g., for scripting")
+	rootCmd.PersistentFlags().Bool(output.CompactFlag, false, "display tables as tab-separated rows without padding, e.g., for pasting into tickets, and json without indentation")
+	rootCmd.PersistentFlags().Bool(output.PrettyFlag, false, "indent json output, also when it is not displayed on a terminal (default is to indent only on a terminal); with jsonl, each item is indented")
+	rootCmd.PersistentFlags().Bool(output.UTCFlag, false, "display timestamps in UTC instead of the local timezone (json, jsonl and yaml output is not affected)")
+	rootCmd.PersistentFlags().Bool(output.RelativeTimeFlag, false, "display timestamps relative to now, e.g., \"2h ago\" (json, jsonl and yaml output is not affected)")
+	rootCmd.PersistentFlags().Int(output.MaxColWidthFlag, 0, "wrap table cells wider than this many characters (default fits tables to the terminal width; no wrapping when output is not a terminal)")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable detailed output")
+	rootCmd.PersistentFlags().String("objstore-api-version", "", fmt.Sprintf("object store API version to use (default is the context's or %q)", config.DefaultObjStoreAPIVersion))
+	rootCmd.PersistentFlags().Bool("trace", false, "Log the network activity of each request (DNS, connection, TLS and response timing)")
+	rootCmd.PersistentFlags().Bool("explain", false, "Display the request that would be sent to the platform instead of executing it (works without a configured context or network access)")
+	rootCmd.PersistentFlags().String("user-agent", "", "User-Agent header value to send to the platform (default is fsoc/<version> (<os>/<arch>))")
+	rootCmd.PersistentFlags().Int("retries", 0, "Number of times to retry a request that failed due to a connection error or a temporarily unavailable service (502, 503, 504); POST and PATCH requests are retried only if the connection could not be established, unless --retry-writes is specified")
+	rootCmd.PersistentFlags().Duration("retry-max-elapsed", 0, "Maximum total time for retrying a request, counted from its first attempt (e.g., 2m); retrying stops when either --retries or this limit is reached (0 for no limit)")
+	rootCmd.PersistentFlags().Bool("retry-writes", false, "Retry POST and PATCH requests on any transient failure, like other requests (may repeat a request that has already been processed, e.g., creating an object twice)")
+	rootCmd.PersistentFlags().Int("max-idle-conns", api.DefaultMaxIdleConnsPerHost, "Number of idle connections to the platform kept open for reuse, e.g., by bulk commands")
+	rootCmd.PersistentFlags().Int("max-conns", api.DefaultMaxConnsPerHost, "Maximum number of connections to the platform, including active ones (0 for no limit)")
+	rootCmd.PersistentFlags().Duration("keep-alive", api.DefaultKeepAlive, "How long idle connections to the platform are kept open for reuse (0 to open a new connection for each request)")
+	rootCmd.PersistentFlags().String("field-manager", "", fmt.Sprintf("identifier sent with each create, update and delete request to attribute the change, e.g., to a pipeline or person (default is the context's field manager, if set, or %q)", config.DefaultFieldManager))
+	rootCmd.PersistentFlags().String("audit-log", "", "file to append a JSON record of each create, update and delete request to, with its result (default is the context's audit log, if set)")
+	rootCmd.PersistentFlags().Duration("deadline", 0, "Maximum time for the whole command, including retries and waiting (e.g., 10m); the command is stopped with exit code 124 when exceeded (0 for no limit)")
+	rootCmd.PersistentFlags().Bool("show-url", false, "Display the method, full URL and status of each request made to the platform, without the rest of the --verbose logging")
+	rootCmd.PersistentFlags().Bool("explain-error", false, "When the command fails because of a platform error, explain the error and suggest a fix")
+	rootCmd.PersistentFlags().Bool("no-input", false, "Fail instead of prompting for input (confirmations, interactive login), e.g., in CI jobs")
+	rootCmd.PersistentFlags().Int("max-items", api.DefaultMaxCollectionItems, "Maximum number of items to retrieve for list commands (0 for no limit)")
 	rootCmd.SetOut(os.Stdout)
 	rootCmd.SetErr(os.Stderr)
 	rootCmd.SetIn(os.Stdin)
@@ -117,6 +169,21 @@ func helperFlagFormatter(fs *pflag.FlagSet) string {
 	return "[" + s + "]"
 }
 
+// explainedRequestHandler returns a log handler that drops the errors logged by a command after
+// its request was displayed in explain mode and, for commands that exit on the error (log.Fatalf),
+// exits successfully instead
+func explainedRequestHandler(next log.Handler) log.Handler {
+	return log.HandlerFunc(func(e *log.Entry) error {
+		if e.Level >= log.ErrorLevel && api.RequestExplained() {
+			if e.Level == log.FatalLevel {
+				os.Exit(0)
+			}
+			return nil
+		}
+		return next.HandleLog(e)
+	})
+}
+
 // preExecHook is executed after the command line is parsed but
 // before the command's handler is executed
 func preExecHook(cmd *cobra.Command, args []string) {
@@ -125,6 +192,22 @@ func preExecHook(cmd *cobra.Command, args []string) {
 	} else {
 		log.SetLevel(log.WarnLevel)
 	}
+	if trace, _ := cmd.Flags().GetBool("trace"); trace {
+		log.SetLevel(log.DebugLevel)
+		api.SetTraceMode(true)
+	}
+
+	if showURL, _ := cmd.Flags().GetBool("show-url"); showURL {
+		api.SetShowURLMode(true)
+	}
+
+	// explain platform errors after the command reports them, if requested
+	if explain, _ := cmd.Flags().GetBool("explain-error"); explain {
+		api.SetExplainErrors(true, cmd.ErrOrStderr())
+		if logger, ok := log.Log.(*log.Logger); ok {
+			logger.Handler = api.ExplainErrorHandler(logger.Handler)
+		}
+	}
 
 	log.WithFields(version.GetVersion()).Info("fsoc version")
 
@@ -142,9 +225,87 @@ func preExecHook(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// make prompts fail instead of blocking, if requested
+	if noInput, _ := cmd.Flags().GetBool("no-input"); noInput {
+		output.SetNoInput(true)
+	}
+
+	// override the object store API version, if requested
+	if cmd.Flags().Changed("objstore-api-version") {
+		version, _ := cmd.Flags().GetString("objstore-api-version")
+		config.SetSelectedObjStoreAPIVersion(version)
+	}
+
+	// display requests instead of executing them, if requested; no context is needed for that
+	explain, _ := cmd.Flags().GetBool("explain")
+	if explain {
+		api.SetExplainMode(true)
+		if !bypassConfig(cmd) {
+			config.SetOfflineMode(true)
+		}
+		// the command stops with an error at its first request; it is not reported (see Execute)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		if logger, ok := log.Log.(*log.Logger); ok {
+			logger.Handler = explainedRequestHandler(logger.Handler)
+		}
+	}
+
+	// identify fsoc to the platform, unless overridden
+	if userAgent, _ := cmd.Flags().GetString("user-agent"); userAgent != "" {
+		api.SetUserAgent(userAgent)
+	} else {
+		api.SetUserAgent(api.DefaultUserAgent(version.GetVersion().Version))
+	}
+
+	// bound the whole command, if requested
+	if deadline, _ := cmd.Flags().GetDuration("deadline"); deadline != 0 {
+		if deadline < 0 {
+			log.Fatalf("--deadline cannot be negative")
+		}
+		parent := cmd.Context()
+		if parent == nil {
+			parent = context.Background()
+		}
+		var ctx context.Context
+		ctx, cancelDeadline = cmdkit.WithDeadline(parent, deadline)
+		cmd.SetContext(ctx)
+	}
+
+	// abort requests when the command is interrupted twice or its deadline is exceeded
+	api.SetContext(cmdkit.RequestContext(cmd.Context()))
+
+	// retry requests on transient failures, if requested
+	if retries, err := cmd.Flags().GetInt("retries"); err == nil && retries > 0 {
+		api.SetMaxRetries(retries)
+	}
+	if maxElapsed, err := cmd.Flags().GetDuration("retry-max-elapsed"); err == nil && maxElapsed > 0 {
+		api.SetMaxRetryElapsed(maxElapsed)
+	}
+	if retryWrites, _ := cmd.Flags().GetBool("retry-writes"); retryWrites {
+		api.SetRetryWrites(true)
+	}
+
+	// tune the reuse of connections, if requested
+	if cmd.Flags().Changed("max-idle-conns") || cmd.Flags().Changed("max-conns") || cmd.Flags().Changed("keep-alive") {
+		settings := api.GetConnectionSettings()
+		settings.MaxIdleConnsPerHost, _ = cmd.Flags().GetInt("max-idle-conns")
+		settings.MaxConnsPerHost, _ = cmd.Flags().GetInt("max-conns")
+		settings.KeepAlive, _ = cmd.Flags().GetDuration("keep-alive")
+		if settings.MaxIdleConnsPerHost < 0 || settings.MaxConnsPerHost < 0 || settings.KeepAlive < 0 {
+			log.Fatalf("--max-idle-conns, --max-conns and --keep-alive cannot be negative")
+		}
+		api.SetConnectionSettings(settings)
+	}
+
+	// set the limit for list commands
+	if maxItems, err := cmd.Flags().GetInt("max-items"); err == nil {
+		api.SetMaxCollectionItems(maxItems)
+	}
+
 	// Determine if a configured profile is required for this command
 	// (bypassed only for commands that must work or can safely work without it)
-	bypass := bypassConfig(cmd) || cmd.Name() == "help" || isCompletionCommand(cmd)
+	bypass := bypassConfig(cmd) || cmd.Name() == "help" || isCompletionCommand(cmd) || explain
 
 	// try to read the config file.and profile
 	err := viper.ReadInConfig()
@@ -152,8 +313,15 @@ func preExecHook(cmd *cobra.Command, args []string) {
 		profile := config.GetCurrentProfileName()
 		exists := config.GetCurrentContext() != nil
 		if !exists && !bypass {
+			if cmd.Flags().Changed("profile") {
+				log.Fatalf("Profile %q specified with --profile does not exist; use \"fsoc config list\" to see the available profiles", profile)
+			}
 			log.Fatalf("fsoc is not fully configured: missing profile %q; please use \"fsoc config set\" to configure it", profile)
 		}
+		// use the context's default output format, unless specified on the command line
+		if ctx := config.GetCurrentContext(); ctx != nil && ctx.DefaultOutput != "" && !cmd.Flags().Changed("output") {
+			_ = cmd.Flags().Set("output", ctx.DefaultOutput)
+		}
 		log.WithFields(log.Fields{
 			"config_file": viper.ConfigFileUsed(),
 			"profile":     profile,
@@ -167,6 +335,24 @@ func preExecHook(cmd *cobra.Command, args []string) {
 			log.Fatalf("fsoc is not configured, please use \"fsoc config set\" to configure an initial context")
 		}
 	}
+
+	// record mutating requests in the audit log, if enabled
+	auditLog, _ := cmd.Flags().GetString("audit-log")
+	if auditLog == "" {
+		if ctx := config.GetCurrentContext(); ctx != nil {
+			auditLog = ctx.AuditLog
+		}
+	}
+	api.SetAuditLog(auditLog, cmd.CommandPath())
+
+	// attribute mutating requests to fsoc, or to the given identifier
+	fieldManager, _ := cmd.Flags().GetString("field-manager")
+	if fieldManager == "" {
+		if ctx := config.GetCurrentContext(); ctx != nil {
+			fieldManager = ctx.FieldManager
+		}
+	}
+	api.SetFieldManager(fieldManager)
 }
 
 func bypassConfig(cmd *cobra.Command) bool {
@@ -12,6 +12,7 @@ import (
 	"github.com/xeipuuv/gojsonschema"
 
 	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/cmdkit"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
 )
@@ -76,14 +77,14 @@ func checkSolution(cmd *cobra.Command, args []string) {
 }
 
 func getTypeUrl(fqtn string) string {
-	return fmt.Sprintf("objstore/v1beta/types/%s", fqtn)
+	return fmt.Sprintf("%s/types/%s", config.GetObjStoreBasePath(), fqtn)
 }
 
-func Fetch(path string, httpOptions *api.Options) map[string]interface{} {
+func Fetch(client *api.Client, path string, httpOptions *api.Options) map[string]interface{} {
 	// finalize override fields
 	var res map[string]interface{}
 	// fetch data
-	if err := api.JSONGet(path, &res, httpOptions); err != nil {
+	if err := client.JSONGet(path, &res, httpOptions); err != nil {
 		log.Fatalf("Platform API call failed: %v", err)
 	}
 	return res
@@ -98,7 +99,7 @@ func checkComponentDef(cmd *cobra.Command, compDef ComponentDef, cfg *config.Con
 	}
 
 	objStoreUrl := getTypeUrl(compDef.Type)
-	typeDef := Fetch(objStoreUrl, &api.Options{Headers: headers})
+	typeDef := Fetch(apiClient(cmd), objStoreUrl, &api.Options{Headers: headers})
 
 	jsonSchema, err := json.Marshal(typeDef["jsonSchema"])
 	if err != nil {
@@ -114,6 +115,8 @@ func checkComponentDef(cmd *cobra.Command, compDef ComponentDef, cfg *config.Con
 
 	x := bytes.TrimLeft(compDefBytes, " \t\r\n")
 
+	// collect the violations of all components in the file
+	var violations []cmdkit.SchemaViolation
 	isArray := len(x) > 0 && x[0] == '['
 	// isObject := len(x) > 0 && x[0] == '{'
 	if isArray {
@@ -122,33 +125,32 @@ func checkComponentDef(cmd *cobra.Command, compDef ComponentDef, cfg *config.Con
 		if err != nil {
 			log.Errorf("Couldn't unmarshal json file content to object array: %v", err)
 		}
-		for _, object := range jsonArray {
+		for i, object := range jsonArray {
 			jsonObject, err := json.Marshal(object)
 			if err != nil {
 				log.Errorf("Couldn't marshal json object to []byte: %v", err)
 			}
 			documentLoader := gojsonschema.NewStringLoader(string(jsonObject))
-			validate(cmd, schemaLoader, documentLoader, compDef)
+			found, err := cmdkit.ValidateJSONSchema(schemaLoader, documentLoader, fmt.Sprintf("/%d", i))
+			if err != nil {
+				log.Fatalf("Failed to validate against the JSON schema of type %s: %v", compDef.Type, err)
+			}
+			violations = append(violations, found...)
 		}
 	} else {
 		documentLoader := gojsonschema.NewStringLoader(string(compDefBytes))
-		validate(cmd, schemaLoader, documentLoader, compDef)
-	}
-
-}
-
-func validate(cmd *cobra.Command, schemaLoader, documentLoader gojsonschema.JSONLoader, compDef ComponentDef) {
-	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
-	if err != nil {
-		panic(err.Error())
+		violations, err = cmdkit.ValidateJSONSchema(schemaLoader, documentLoader, "")
+		if err != nil {
+			log.Fatalf("Failed to validate against the JSON schema of type %s: %v", compDef.Type, err)
+		}
 	}
 
-	if result.Valid() {
+	if len(violations) == 0 {
 		output.PrintCmdStatus(cmd, fmt.Sprintf("The components defined in the file %s are valid definitions of type %s \n", compDef.ObjectsFile, compDef.Type))
 	} else {
 		output.PrintCmdStatus(cmd, fmt.Sprintf("The components defined in the file %s are invalid definitions of type %s ! \n", compDef.ObjectsFile, compDef.Type))
-		for _, desc := range result.Errors() {
-			output.PrintCmdStatus(cmd, fmt.Sprintf("- %s\n", desc))
+		for _, violation := range violations {
+			output.PrintCmdStatus(cmd, fmt.Sprintf("- %s\n", violation))
 		}
 	}
 }
@@ -0,0 +1,70 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// findDependents returns the names (sorted) of the solutions the tenant is subscribed to
+// that declare a dependency on the given solution
+func findDependents(client *api.Client, tenantID string, solutionName string) ([]string, error) {
+	headers := map[string]string{
+		"layer-type": "TENANT",
+		"layer-id":   tenantID,
+	}
+	query := map[string]string{
+		"filter": "data.isSubscribed eq true",
+	}
+
+	var res any
+	if err := client.JSONGetCollection(getSolutionListUrl(), &res, &api.Options{Headers: headers, QueryParams: query}); err != nil {
+		return nil, fmt.Errorf("failed to get the list of subscribed solutions: %w", err)
+	}
+
+	// convert the collection's items into solution definitions
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	var solutions struct {
+		Items []struct {
+			Data SolutionDef `json:"data"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &solutions); err != nil {
+		return nil, fmt.Errorf("failed to parse the list of solutions: %w", err)
+	}
+
+	dependents := []string{}
+	for _, item := range solutions.Items {
+		// nb: the filter is repeated here in case the platform ignores it
+		if !item.Data.IsSubscribed || item.Data.Name == solutionName {
+			continue
+		}
+		for _, dependency := range item.Data.Dependencies {
+			if dependency == solutionName {
+				dependents = append(dependents, item.Data.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents, nil
+}
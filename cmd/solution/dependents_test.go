@@ -0,0 +1,46 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDependents(t *testing.T) {
+	var filter string
+	startTestPlatform(t, func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("filter")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total": 4, "items": [
+			{"data": {"name": "base", "isSubscribed": true}},
+			{"data": {"name": "reports", "isSubscribed": true, "dependencies": ["other", "base"]}},
+			{"data": {"name": "alerts", "isSubscribed": true, "dependencies": ["base"]}},
+			{"data": {"name": "unused", "isSubscribed": false, "dependencies": ["base"]}}
+		]}`))
+	})
+
+	dependents, err := findDependents(testPlatformClient, "test-tenant", "base")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"alerts", "reports"}, dependents)
+	assert.Equal(t, "data.isSubscribed eq true", filter)
+
+	dependents, err = findDependents(testPlatformClient, "test-tenant", "reports")
+	require.Nil(t, err)
+	assert.Empty(t, dependents)
+}
@@ -57,7 +57,7 @@ func downloadSolution(cmd *cobra.Command, args []string) {
 	}
 	httpOptions := api.Options{Headers: headers}
 	bufRes := make([]byte, 0)
-	if err := api.HTTPGet(getSolutionDownloadUrl(solutionName), &bufRes, &httpOptions); err != nil {
+	if err := apiClient(cmd).HTTPGet(getSolutionDownloadUrl(solutionName), &bufRes, &httpOptions); err != nil {
 		log.Fatalf("Solution download command failed: %v", err.Error())
 	}
 
@@ -0,0 +1,154 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/output"
+)
+
+var solutionInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Upload and install a solution bundle",
+	Long: `This command uploads a solution bundle archive, subscribes the current tenant to the solution so that it gets installed
+and, with --wait, waits until the install completes. The command exits with a non-zero status if any of the steps fails,
+including a failed install, which makes it suitable for CI pipelines.
+
+Usage:
+	fsoc solution install --solution-bundle=<solution-bundle-archive-path> [--wait]
+
+Flags/Options:
+	--solution-bundle - Flag to indicate the path of the solution bundle .zip file, e.g., as created by "fsoc solution package"
+	--wait - OPTIONAL Flag to wait until the uploaded version of the solution has been installed
+	--poll-interval - OPTIONAL Flag to specify how often to check the install status while waiting (default 5s)
+	--poll-backoff - OPTIONAL Flag to specify the factor by which the poll interval grows after each check, up to 1m (default 1, i.e., fixed interval)
+	--timeout - OPTIONAL Flag to specify the maximum time to wait for the install to complete (default 10m, 0 for no limit)`,
+	Example: `  # Upload, install and wait for the install to complete
+  fsoc solution install --solution-bundle ./mysolution.zip --wait`,
+	Args:             cobra.ExactArgs(0),
+	Run:              installSolution,
+	TraverseChildren: true,
+}
+
+func getSolutionInstallCmd() *cobra.Command {
+	solutionInstallCmd.Flags().
+		String("solution-bundle", "", "The path name of the solution bundle .zip file")
+	_ = solutionInstallCmd.MarkFlagRequired("solution-bundle")
+	addWaitFlags(solutionInstallCmd, "Wait until the uploaded version of the solution has been installed")
+
+	return solutionInstallCmd
+}
+
+func installSolution(cmd *cobra.Command, args []string) {
+	solutionBundlePath, _ := cmd.Flags().GetString("solution-bundle")
+	wait, _ := cmd.Flags().GetBool("wait")
+	opts, err := getPollOptions(cmd)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	manifest, err := readBundleManifest(solutionBundlePath)
+	if err != nil {
+		log.Fatalf("Can't read the solution manifest from %s: %v", solutionBundlePath, err)
+	}
+	log.WithFields(log.Fields{
+		"solution": manifest.Name,
+		"version":  manifest.SolutionVersion,
+	}).Info("Installing solution")
+
+	// upload; status records created from now on belong to this install
+	startTime := time.Now()
+	if err := uploadSolutionBundle(apiClient(cmd), solutionBundlePath); err != nil {
+		log.Fatalf("Failed to upload solution bundle %s: %v", solutionBundlePath, err)
+	}
+	output.PrintCmdStatus(cmd, fmt.Sprintf("Uploaded solution %s version %s\n", manifest.Name, manifest.SolutionVersion))
+
+	// subscribing triggers the install for the tenant (no-op if already subscribed)
+	tenantID := config.GetCurrentContext().Tenant
+	if err := setSubscription(apiClient(cmd), tenantID, manifest.Name, true); err != nil {
+		log.Fatalf("Failed to subscribe tenant %s to solution %s: %v", tenantID, manifest.Name, err)
+	}
+
+	if !wait {
+		output.PrintCmdStatus(cmd, fmt.Sprintf("Install requested; use \"fsoc solution status --name %s --solution-version %s --wait\" to wait for it to complete\n", manifest.Name, manifest.SolutionVersion))
+		return
+	}
+
+	output.PrintCmdStatus(cmd, "Waiting for the install to complete...\n")
+	headers := map[string]string{
+		"layer-type": "TENANT",
+		"layer-id":   tenantID,
+	}
+	query := map[string]string{
+		"order":  "desc",
+		"filter": fmt.Sprintf(`data.solutionName eq "%s" and data.solutionVersion eq "%s"`, manifest.Name, manifest.SolutionVersion),
+		"max":    "1",
+	}
+	fetch := func() (StatusItem, StatusItem, error) {
+		return fetchStatusItems(apiClient(cmd), query, headers, startTime)
+	}
+	_, install, err := waitForInstall(cmd.Context(), fetch, opts)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if !install.StatusData.SuccessfulInstall {
+		log.Fatalf("Install of solution %s version %s failed: %s", manifest.Name, manifest.SolutionVersion, install.StatusData.InstallMessage)
+	}
+	output.PrintCmdStatus(cmd, fmt.Sprintf("Solution %s version %s was successfully installed\n", manifest.Name, manifest.SolutionVersion))
+}
+
+// readBundleManifest reads the solution manifest from a solution bundle archive; the
+// manifest is expected either at the root of the archive or in its top-level folder
+func readBundleManifest(bundlePath string) (*Manifest, error) {
+	archive, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	for _, f := range archive.File {
+		if path.Base(f.Name) != "manifest.json" || (path.Dir(f.Name) != "." && path.Dir(path.Dir(f.Name)) != ".") {
+			continue
+		}
+		file, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		manifestBytes, err := io.ReadAll(file)
+		if err != nil {
+			return nil, err
+		}
+		var manifest Manifest
+		if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid manifest.json: %w", err)
+		}
+		if manifest.Name == "" || manifest.SolutionVersion == "" {
+			return nil, fmt.Errorf("manifest.json must specify the solution's name and solutionVersion")
+		}
+		return &manifest, nil
+	}
+	return nil, fmt.Errorf("no manifest.json found in the archive")
+}
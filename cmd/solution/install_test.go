@@ -0,0 +1,62 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestBundle creates a solution bundle archive with the given files
+func writeTestBundle(t *testing.T, files map[string]string) string {
+	bundlePath := filepath.Join(t.TempDir(), "bundle.zip")
+	f, err := os.Create(bundlePath)
+	require.Nil(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		require.Nil(t, err)
+		_, err = fw.Write([]byte(content))
+		require.Nil(t, err)
+	}
+	require.Nil(t, w.Close())
+	return bundlePath
+}
+
+func TestReadBundleManifest(t *testing.T) {
+	bundle := writeTestBundle(t, map[string]string{
+		"mysolution/manifest.json":         `{"name": "mysolution", "solutionVersion": "1.2.3"}`,
+		"mysolution/objects/manifest.json": `{"name": "other", "solutionVersion": "0.0.1"}`,
+	})
+	manifest, err := readBundleManifest(bundle)
+	require.Nil(t, err)
+	assert.Equal(t, "mysolution", manifest.Name)
+	assert.Equal(t, "1.2.3", manifest.SolutionVersion)
+
+	bundle = writeTestBundle(t, map[string]string{"mysolution/objects/manifest.json": `{}`})
+	_, err = readBundleManifest(bundle)
+	assert.NotNil(t, err)
+
+	bundle = writeTestBundle(t, map[string]string{"manifest.json": `{"name": "mysolution"}`})
+	_, err = readBundleManifest(bundle)
+	assert.NotNil(t, err)
+}
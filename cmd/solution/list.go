@@ -35,6 +35,7 @@ Usage:
 	Annotations: map[string]string{
 		output.TableFieldsAnnotation:  "name:.data.name, isSystem:.data.isSystem, isSubscribed:.data.isSubscribed, dependencies:.data.dependencies",
 		output.DetailFieldsAnnotation: "name:.data.name, isSystem:.data.isSystem, isSubscribed:.data.isSubscribed, dependencies:.data.dependencies, installDate:.createdAt, updateDate:.updatedAt",
+		output.WideFieldsAnnotation:   "name:.data.name, id:.id, isSystem:.data.isSystem, isSubscribed:.data.isSubscribed, dependencies:.data.dependencies, installDate:.createdAt, updateDate:.updatedAt",
 	},
 }
 
@@ -54,5 +55,5 @@ func getSolutionList(cmd *cobra.Command, args []string) {
 }
 
 func getSolutionListUrl() string {
-	return "objstore/v1beta/objects/extensibility:solution"
+	return config.GetObjStoreBasePath() + "/objects/extensibility:solution"
 }
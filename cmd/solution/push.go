@@ -16,10 +16,7 @@ package solution
 
 import (
 	"archive/zip"
-	"bytes"
 	"fmt"
-	"io"
-	"mime/multipart"
 	"os"
 	"path/filepath"
 
@@ -82,45 +79,31 @@ func pushSolution(cmd *cobra.Command, args []string) {
 		"solution-package": solutionBundlePath,
 	}).Info(message)
 
-	file, err := os.Open(solutionArchivePath)
-	if err != nil {
-		log.Fatalf("Failed to open file %s - %v", solutionArchivePath, err.Error())
-	}
-	defer file.Close()
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	output.PrintCmdStatus(cmd, message)
 
-	fw, err := writer.CreateFormFile("file", solutionArchivePath)
-	if err != nil {
-		log.Fatalf("Failed to create form file - %v", err.Error())
+	if err := uploadSolutionBundle(apiClient(cmd), solutionArchivePath); err != nil {
+		log.Fatalf("Solution command failed: %v", err.Error())
 	}
+	// message = fmt.Sprintf("Solution %s - %s was successfully deployed.", manifest.Name, manifest.SolutionVersion)
+	message = fmt.Sprintf("Solution bundle %s was successfully deployed.\n", solutionArchivePath)
+	output.PrintCmdStatus(cmd, message)
+}
 
-	_, err = io.Copy(fw, file)
+// uploadSolutionBundle uploads the solution bundle archive to the platform
+func uploadSolutionBundle(client *api.Client, solutionArchivePath string) error {
+	file, err := os.Open(solutionArchivePath)
 	if err != nil {
-		log.Errorf("Failed to copy file %s into file writer - %v", solutionArchivePath, err.Error())
+		return fmt.Errorf("failed to open file %s - %v", solutionArchivePath, err.Error())
 	}
-
-	writer.Close()
+	defer file.Close()
 
 	headers := map[string]string{
-		"stage":        "STABLE",
-		"operation":    "UPLOAD",
-		"Content-Type": writer.FormDataContentType(),
+		"stage":     "STABLE",
+		"operation": "UPLOAD",
 	}
 
 	var res any
-
-	output.PrintCmdStatus(cmd, message)
-
-	err = api.HTTPPost(getSolutionPushUrl(), body.Bytes(), &res, &api.Options{Headers: headers})
-
-	if err != nil {
-		log.Fatalf("Solution command failed: %v", err.Error())
-	}
-	// message = fmt.Sprintf("Solution %s - %s was successfully deployed.", manifest.Name, manifest.SolutionVersion)
-	message = fmt.Sprintf("Solution bundle %s was successfully deployed.\n", solutionArchivePath)
-	output.PrintCmdStatus(cmd, message)
+	return client.Upload(getSolutionPushUrl(), nil, "file", file, &res, &api.Options{Headers: headers})
 }
 
 func getSolutionPushUrl() string {
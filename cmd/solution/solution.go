@@ -16,6 +16,8 @@ package solution
 
 import (
 	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/platform/api"
 )
 
 // loginCmd represents the login command
@@ -49,6 +51,7 @@ func NewSubCmd() *cobra.Command {
 	solutionCmd.AddCommand(getSolutionExtendCmd())
 	solutionCmd.AddCommand(getSolutionPackageCmd())
 	solutionCmd.AddCommand(getSolutionPushCmd())
+	solutionCmd.AddCommand(getSolutionInstallCmd())
 	solutionCmd.AddCommand(getAuthorCmd())
 	solutionCmd.AddCommand(getSolutionDownloadCmd())
 	solutionCmd.AddCommand(getSolutionValidateCmd())
@@ -59,3 +62,8 @@ func NewSubCmd() *cobra.Command {
 
 	return solutionCmd
 }
+
+// apiClient returns the client to use for the command's platform requests
+func apiClient(cmd *cobra.Command) *api.Client {
+	return api.ClientFromContext(cmd.Context())
+}
@@ -15,13 +15,22 @@
 package solution
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"net/url"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/apex/log"
+	"github.com/relvacode/iso8601"
 	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/cmdkit"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
 )
@@ -32,6 +41,7 @@ type StatusData struct {
 	SuccessfulInstall bool   `json:"isSuccessful,omitempty"`
 	SolutionName      string `json:"solutionName,omitempty"`
 	SolutionVersion   string `json:"solutionVersion,omitempty"`
+	InstalledBy       string `json:"installedBy,omitempty"` // actor or source of the install (e.g., fsoc, UI or automation), if recorded
 }
 
 type StatusItem struct {
@@ -39,10 +49,68 @@ type StatusItem struct {
 	CreatedAt  string     `json:"createdAt"`
 }
 
+// statusOutput is the status data displayed by the command; it adds the install
+// failure reason as a separate field so that it stands out in machine-readable output
+type statusOutput struct {
+	StatusData      `yaml:",inline"`
+	Error           string `json:"error,omitempty" yaml:"error,omitempty"`
+	ResolvedVersion string `json:"resolvedVersion,omitempty" yaml:"resolvedVersion,omitempty"` // latest version, if no version was requested
+	State           string `json:"state" yaml:"state"`                                         // one of the solution states below
+	ExpectedVersion string `json:"expectedVersion,omitempty" yaml:"expectedVersion,omitempty"` // version required by --expect-version
+	VersionMismatch bool   `json:"versionMismatch,omitempty" yaml:"versionMismatch,omitempty"` // the installed version is not the expected one
+}
+
+// States of the solution (version) reported by the status command
+const (
+	stateNotUploaded = "notUploaded"
+	stateUploaded    = "uploaded" // uploaded but not installed
+	stateInstalled   = "installed"
+)
+
+// Values displayed in place of the missing upload or install records
+const (
+	noUploadRecord  = "no upload record found for this version"
+	noInstallRecord = "no install record found for this version"
+)
+
+// solutionState returns the state of the solution given which of its records were found
+func solutionState(uploaded, installed bool) string {
+	switch {
+	case installed:
+		return stateInstalled
+	case uploaded:
+		return stateUploaded
+	}
+	return stateNotUploaded
+}
+
+// latestVersion is the --solution-version value that explicitly requests the latest version
+const latestVersion = "latest"
+
+// defaultHistoryMax is the default number of records of each type shown with --history
+const defaultHistoryMax = 10
+
+// historyRecord is an upload or install record displayed with --history
+type historyRecord struct {
+	Record     string `json:"record" yaml:"record"` // upload or install
+	CreatedAt  string `json:"createdAt" yaml:"createdAt"`
+	StatusData `yaml:",inline"`
+}
+
 type ResponseBlob struct {
 	Items []StatusItem `json:"items"`
 }
 
+// exit codes of the status command, reflecting the state of the solution
+const (
+	statusExitSuccess      = 0
+	statusExitError        = 1 // the status could not be retrieved
+	statusExitFailed       = 2
+	statusExitNotFound     = 3
+	statusExitInProgress   = 4
+	statusExitWrongVersion = 5 // the installed version is not the one given with --expect-version
+)
+
 var solutionStatusCmd = &cobra.Command{
 	Use:   "status [flags]",
 	Short: "Get the installation/upload status of a solution",
@@ -52,12 +120,41 @@ var solutionStatusCmd = &cobra.Command{
 	fsoc solution status --name <solution-name> --solution-version <optional-solution-version> --status-type [upload | install | all]
 	
 	Flags/Options:
-	--name - Flag to indicate the name of the solution for which you would like to fetch the upload/installation status
-	--solution-version - OPTIONAL Flag to indicate the version of the solution for which you would like to fetch the upload/installation status
+	--name - Flag to indicate the name of the solution for which you would like to fetch the upload/installation status. Optional with --install-id
+	--raw-json - OPTIONAL Flag to display the complete upload and/or install objects exactly as returned by the platform (with --history, up to --max records of each), instead of the status
+	--install-id - OPTIONAL Flag to show the install record with the given ID (e.g., from the platform's logs) instead of the latest install of the solution
+	--solution-version - OPTIONAL Flag to indicate the version of the solution for which you would like to fetch the upload/installation status. If not specified or "latest", the latest version is shown and reported
 	--status-type - OPTIONAL Flag to specify the status that you would like to view.  If not specified, the output will contain both solution upload and solution installation status information
+	--output wide - OPTIONAL Flag to also show who or what installed the solution (e.g., fsoc, the UI or automation), if the platform recorded it
+	--layer-type - OPTIONAL Flag to specify the layer at which the upload and install records are stored (default TENANT)
+	--layer-id - OPTIONAL Flag to specify the layer ID at which the upload and install records are stored; required for layers other than TENANT
+	--since - OPTIONAL Flag to only consider records created within a duration (e.g., 24h) or after an ISO 8601 timestamp
+	--history - OPTIONAL Flag to list the upload and install records, most recent first, instead of showing the latest status
+	--max - OPTIONAL Flag to specify how many records of each type to fetch with --history (default 10)
+	--wait - OPTIONAL Flag to wait until the latest uploaded version (or the version specified with --solution-version) has been installed
+	--poll-interval - OPTIONAL Flag to specify how often the status is checked while waiting (default 5s)
+	--poll-backoff - OPTIONAL Flag to specify a factor by which the poll interval grows after each check, up to 1m (default 1, i.e., no backoff)
+	--timeout - OPTIONAL Flag to specify the maximum time to wait (default 10m; 0 for no limit)
+	--expect-version - OPTIONAL Flag to check that the given version of the solution is the one installed (and successfully), e.g., to verify a deployment. The latest install is compared, ignoring a leading "v" (v1.2.3 matches 1.2.3); use with --status-type install to check the install only
+	--watch - OPTIONAL Flag to refresh the status every --poll-interval until interrupted (e.g., with Ctrl-C), redrawing it in place on a terminal and appending a timestamped snapshot otherwise. The exit code reflects the last status shown
+
+	Exit codes (for the status type shown):
+	0 - the solution was uploaded/installed successfully
+	1 - the status could not be retrieved
+	2 - the install failed
+	3 - no upload/install record was found (with --history, the only non-zero code)
+	4 - the install of the latest upload is still in progress
+	5 - the installed version is not the one given with --expect-version
 	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return getSolutionStatus(cmd, args)
+		exitCode, err := getSolutionStatus(cmd, args)
+		if err != nil {
+			return err
+		}
+		if exitCode != statusExitSuccess {
+			return cmdkit.ExitWithCode(cmd, exitCode)
+		}
+		return nil
 	},
 	Args:             cobra.ExactArgs(0),
 	TraverseChildren: true,
@@ -65,108 +162,538 @@ var solutionStatusCmd = &cobra.Command{
 
 func getSolutionStatusCmd() *cobra.Command {
 	solutionStatusCmd.Flags().
-		String("name", "", "The name of the solution for which you would like to retrieve the upload status")
-	_ = solutionStatusCmd.MarkFlagRequired("name")
+		String("name", "", "The name of the solution for which you would like to retrieve the upload status (optional with --install-id)")
+	solutionStatusCmd.Flags().
+		String("install-id", "", "The ID of the install record to show, instead of the latest install of the solution")
 	solutionStatusCmd.Flags().
-		String("solution-version", "", "The version of the solution for which you would like to retrieve the upload status")
+		String("solution-version", "", "The version of the solution for which you would like to retrieve the upload status (default or \"latest\" for the latest version)")
 	solutionStatusCmd.Flags().
 		String("status-type", "", "The status type that you want to see.  This can be one of [upload, install, all] and will default to all if not specified")
+	solutionStatusCmd.Flags().
+		String("since", "", "Only show records created within the given duration (e.g., 24h) or after the given ISO 8601 timestamp")
+	solutionStatusCmd.Flags().
+		String("layer-type", "TENANT", "The layer-type at which the solution's upload and install records are stored")
+	solutionStatusCmd.Flags().
+		String("layer-id", "", "The layer-id at which the solution's upload and install records are stored. Optional for the TENANT layer")
+	solutionStatusCmd.Flags().
+		Bool("history", false, "List the upload and install records, most recent first, instead of the latest status")
+	solutionStatusCmd.Flags().
+		Int("max", defaultHistoryMax, "The maximum number of records of each type to fetch with --history")
+	addWaitFlags(solutionStatusCmd, "Wait until the latest uploaded version of the solution has been installed")
+
+	solutionStatusCmd.Flags().
+		Bool("watch", false, "Refresh the status every --poll-interval until interrupted (redrawn in place on a terminal)")
+
+	solutionStatusCmd.Flags().
+		Bool("raw-json", false, "Display the complete upload and install objects as returned by the platform, instead of the status")
+
+	solutionStatusCmd.Flags().
+		String("expect-version", "", "Fail (exit code 5) unless the latest install is of the given version, e.g., to verify a deployment")
+
+	solutionStatusCmd.MarkFlagsMutuallyExclusive("history", "wait", "watch")
+	solutionStatusCmd.MarkFlagsMutuallyExclusive("expect-version", "solution-version", "history", "raw-json")
+	solutionStatusCmd.MarkFlagsMutuallyExclusive("raw-json", "wait", "watch")
+	solutionStatusCmd.MarkFlagsMutuallyExclusive("raw-json", "since")
+	solutionStatusCmd.MarkFlagsMutuallyExclusive("install-id", "history", "wait", "watch")
+	solutionStatusCmd.MarkFlagsMutuallyExclusive("install-id", "solution-version")
+	solutionStatusCmd.MarkFlagsMutuallyExclusive("install-id", "since")
 
 	return solutionStatusCmd
 }
 
-func getObject(url string, headers map[string]string) StatusItem {
-	var res ResponseBlob
+func getObject(client *api.Client, path string, headers map[string]string, query map[string]string, since time.Time) (StatusItem, error) {
 	var emptyData StatusItem
 
-	err := api.HTTPGet(url, &res, &api.Options{Headers: headers})
-
+	items, err := getObjects(client, path, headers, query, since)
 	if err != nil {
-		log.Fatalf("Issue fetching install/upload object: %v", err)
+		return emptyData, err
 	}
 
-	if len(res.Items) > 0 {
-		return res.Items[0]
+	if len(items) > 0 {
+		return items[0], nil
 	} else {
-		return emptyData
+		return emptyData, nil
+	}
+}
+
+// getObjects fetches the records matching the query that were created at or after the since time.
+// The query already selects them (see statusQuery); they are filtered again in case the
+// platform ignores the creation time in the filter.
+func getObjects(client *api.Client, path string, headers map[string]string, query map[string]string, since time.Time) ([]StatusItem, error) {
+	var res ResponseBlob
+
+	err := client.HTTPGet(path, &res, &api.Options{Headers: headers, QueryParams: query})
+	if err != nil {
+		return nil, describeFetchError(err)
+	}
+
+	return filterItemsSince(res.Items, since), nil
+}
+
+// describeFetchError distinguishes failures to reach the platform (connection
+// errors and timeouts) from error responses returned by the platform
+func describeFetchError(err error) error {
+	var netErr net.Error
+	var problem api.Problem
+	var respErr api.ResponseError
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Errorf("timed out trying to reach the platform: %w", err)
+	case errors.As(err, &netErr):
+		return fmt.Errorf("could not reach the platform: %w", err)
+	case errors.As(err, &problem):
+		return fmt.Errorf("platform returned %v: %w", problem.Status, err)
+	case errors.As(err, &respErr):
+		return fmt.Errorf("platform returned %v: %w", respErr.StatusCode, err)
+	default:
+		return fmt.Errorf("issue fetching install/upload object: %w", err)
+	}
+}
+
+// filterItemsSince returns the items created at or after the since time.
+// A zero since time disables the filtering. Items whose creation time
+// cannot be parsed are dropped when filtering is requested.
+func filterItemsSince(items []StatusItem, since time.Time) []StatusItem {
+	if since.IsZero() {
+		return items
+	}
+
+	filtered := []StatusItem{}
+	for _, item := range items {
+		createdAt, err := iso8601.ParseString(item.CreatedAt)
+		if err != nil {
+			log.Warnf("Skipping record with unparseable creation time %q: %v", item.CreatedAt, err)
+			continue
+		}
+		if !createdAt.Before(since) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+func fetchStatusItems(client *api.Client, query map[string]string, requestHeaders map[string]string, since time.Time) (StatusItem, StatusItem, error) {
+	uploadStatusItem, err := getObject(client, getSolutionReleaseUrl(), requestHeaders, query, since)
+	if err != nil {
+		return StatusItem{}, StatusItem{}, err
+	}
+	installStatusItem, err := getObject(client, getSolutionInstallUrl(), requestHeaders, query, since)
+	if err != nil {
+		return StatusItem{}, StatusItem{}, err
 	}
+	return uploadStatusItem, installStatusItem, nil
 }
 
-func fetchValuesAndPrint(operation string, query string, requestHeaders map[string]string, cmd *cobra.Command) {
-	uploadStatusItem := getObject(fmt.Sprintf(getSolutionReleaseUrl(), query), requestHeaders)
-	installStatusItem := getObject(fmt.Sprintf(getSolutionInstallUrl(), query), requestHeaders)
+// statusExitCode returns the exit code reflecting the state of the solution for the given status type
+func statusExitCode(operation string, upload StatusItem, install StatusItem) int {
+	uploaded := upload.StatusData.SolutionName != ""
+	installed := install.StatusData.SolutionName != ""
+	switch operation {
+	case "upload":
+		if !uploaded {
+			return statusExitNotFound
+		}
+		return statusExitSuccess
+	case "install":
+		if !installed {
+			return statusExitNotFound
+		}
+	default:
+		if !uploaded && !installed {
+			return statusExitNotFound
+		}
+		if !installCompleted(upload, install) {
+			return statusExitInProgress
+		}
+	}
+	if !install.StatusData.SuccessfulInstall {
+		return statusExitFailed
+	}
+	return statusExitSuccess
+}
 
+// fetchValuesAndPrint displays the status and returns the exit code reflecting it
+func fetchValuesAndPrint(operation string, query map[string]string, requestHeaders map[string]string, since time.Time, cmd *cobra.Command) (int, error) {
+	fetch := func() (StatusItem, StatusItem, error) {
+		return fetchStatusItems(apiClient(cmd), query, requestHeaders, since)
+	}
+
+	if watch, _ := cmd.Flags().GetBool("watch"); watch {
+		interval, _ := cmd.Flags().GetDuration("poll-interval")
+		if interval <= 0 {
+			return 0, fmt.Errorf("--poll-interval must be positive")
+		}
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return watchStatus(ctx, cmd, interval, fetch, func(upload, install StatusItem) int {
+			return printStatus(cmd, operation, upload, install)
+		}), nil
+	}
+
+	var uploadStatusItem, installStatusItem StatusItem
+	var err error
+	if wait, _ := cmd.Flags().GetBool("wait"); wait {
+		opts, err := getPollOptions(cmd)
+		if err != nil {
+			return 0, err
+		}
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		uploadStatusItem, installStatusItem, err = waitForInstall(ctx, fetch, opts)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		uploadStatusItem, installStatusItem, err = fetch()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return printStatus(cmd, operation, uploadStatusItem, installStatusItem), nil
+}
+
+// printStatus displays the upload and/or install status and returns the exit code reflecting it
+func printStatus(cmd *cobra.Command, operation string, uploadStatusItem StatusItem, installStatusItem StatusItem) int {
 	installStatusData := installStatusItem.StatusData
 	uploadStatusData := uploadStatusItem.StatusData
 	uploadStatusTimestamp := uploadStatusItem.CreatedAt
 
+	uploaded := uploadStatusData.SolutionName != ""
+	installed := installStatusData.SolutionName != ""
+
+	// nb: the name is taken from the command line if there are no records to take it from
+	solutionName := uploadStatusData.SolutionName
+	if !uploaded {
+		solutionName = installStatusData.SolutionName
+	}
+	if solutionName == "" {
+		solutionName, _ = cmd.Flags().GetString("name")
+	}
 	headers := []string{"Solution Name"}
-	values := []string{uploadStatusData.SolutionName}
+	values := []string{solutionName}
 
 	appendValue := func(header, value string) {
 		headers = append(headers, header)
 		values = append(values, value)
 	}
-
-	if operation == "upload" {
+	appendUpload := func() {
+		if !uploaded {
+			appendValue("Solution Upload", noUploadRecord)
+			return
+		}
 		appendValue("Solution Upload Version", uploadStatusData.SolutionVersion)
 		appendValue("Upload Timestamp", uploadStatusTimestamp)
-	} else if operation == "install" {
+	}
+	appendInstall := func() {
+		if !installed {
+			appendValue("Solution Install", noInstallRecord)
+			return
+		}
 		appendValue("Solution Install Version", installStatusData.SolutionVersion)
 		appendValue("Solution Install Successful?", fmt.Sprintf("%v", installStatusData.SuccessfulInstall))
 		appendValue("Solution Install Time", installStatusData.InstallTime)
 		appendValue("Solution Install Message", installStatusData.InstallMessage)
+	}
+
+	if operation == "upload" {
+		appendUpload()
+	} else if operation == "install" {
+		appendInstall()
 	} else {
-		appendValue("Solution Upload Version", uploadStatusData.SolutionVersion)
-		appendValue("Upload Timestamp", uploadStatusTimestamp)
-		appendValue("Solution Install Version", installStatusData.SolutionVersion)
-		appendValue("Solution Install Successful?", fmt.Sprintf("%v", installStatusData.SuccessfulInstall))
-		appendValue("Solution Install Time", installStatusData.InstallTime)
-		appendValue("Solution Install Message", installStatusData.InstallMessage)
+		appendUpload()
+		appendInstall()
+	}
+
+	// show who installed the solution, in the wide output only
+	if format, _ := cmd.Flags().GetString("output"); format == "wide" && operation != "upload" && installed {
+		installedBy := installStatusData.InstalledBy
+		if installedBy == "" {
+			installedBy = "(unknown)"
+		}
+		appendValue("Solution Installed By", installedBy)
+	}
+
+	// surface the reason of a failed install, which is usually too long for the table
+	out := statusOutput{StatusData: installStatusData, State: solutionState(uploaded, installed)}
+	footer := ""
+	if operation != "upload" && installStatusData.SolutionName != "" && !installStatusData.SuccessfulInstall {
+		out.Error = installStatusData.InstallMessage
+		if out.Error == "" {
+			out.Error = "the install failed without providing a reason"
+		}
+		footer = fmt.Sprintf("\nInstall failed:\n%v", out.Error)
+	}
+
+	// make it clear which version was picked when no specific version was requested
+	requestedVersion, _ := cmd.Flags().GetString("solution-version")
+	installID, _ := cmd.Flags().GetString("install-id")
+	if installID == "" && (requestedVersion == "" || requestedVersion == latestVersion) {
+		out.ResolvedVersion = uploadStatusData.SolutionVersion
+		if operation == "install" {
+			out.ResolvedVersion = installStatusData.SolutionVersion
+		}
+		if out.ResolvedVersion != "" {
+			footer = fmt.Sprintf("Showing latest: %v", out.ResolvedVersion) + footer
+		}
+	}
+
+	// check the installed version against the expected one, if requested
+	exitCode := statusExitCode(operation, uploadStatusItem, installStatusItem)
+	if expected, _ := cmd.Flags().GetString("expect-version"); expected != "" && installed {
+		out.ExpectedVersion = expected
+		if !sameVersion(installStatusData.SolutionVersion, expected) {
+			out.VersionMismatch = true
+			footer += fmt.Sprintf("\nInstalled version %v is not the expected version %v", installStatusData.SolutionVersion, expected)
+			if exitCode == statusExitSuccess {
+				exitCode = statusExitWrongVersion
+			}
+		} else if exitCode == statusExitSuccess {
+			footer += fmt.Sprintf("\nExpected version %v is installed", expected)
+		}
 	}
 
-	output.PrintCmdOutputCustom(cmd, installStatusData, &output.Table{
+	output.PrintCmdOutputCustom(cmd, out, &output.Table{
 		Headers: headers,
 		Lines:   [][]string{values},
 		Detail:  true,
+		Footer:  footer,
 	})
+	return exitCode
 }
 
-func getSolutionStatus(cmd *cobra.Command, args []string) error {
+// sameVersion returns true if the versions are the same, ignoring a leading "v" (e.g., v1.2.3 and 1.2.3)
+func sameVersion(a string, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+// getSolutionStatus displays the status of the solution and returns the exit code reflecting it
+func getSolutionStatus(cmd *cobra.Command, args []string) (int, error) {
 	var err error
-	var filterQuery string
 	cfg := config.GetCurrentContext()
 
-	layerType := "TENANT"
+	layerType, _ := cmd.Flags().GetString("layer-type")
+	layerType = strings.ToUpper(layerType) // nb: layer types are upper case, e.g., TENANT
+	layerID, _ := cmd.Flags().GetString("layer-id")
+	if layerID == "" {
+		if layerType != "TENANT" {
+			return 0, fmt.Errorf("please specify the layer ID for the %v layer with the --layer-id flag", layerType)
+		}
+		layerID = cfg.Tenant
+	}
 	solutionName, err := cmd.Flags().GetString("name")
 	if err != nil {
-		return fmt.Errorf("error trying to get %q flag value: %w", "name", err)
+		return 0, fmt.Errorf("error trying to get %q flag value: %w", "name", err)
+	}
+	installID, _ := cmd.Flags().GetString("install-id")
+	if solutionName == "" && installID == "" {
+		return 0, fmt.Errorf("please specify the solution with the --name flag (or an install record with --install-id)")
 	}
 
 	headers := map[string]string{
 		"layer-type": layerType,
-		"layer-id":   cfg.Tenant,
+		"layer-id":   layerID,
 	}
 	solutionVersion, _ := cmd.Flags().GetString("solution-version")
+	if solutionVersion == latestVersion {
+		solutionVersion = ""
+	}
 	statusTypeToFetch, _ := cmd.Flags().GetString("status-type")
+	statusTypeToFetch = strings.ToLower(statusTypeToFetch)
+
+	if cmd.Flags().Changed("expect-version") && statusTypeToFetch == "upload" {
+		return 0, fmt.Errorf("--expect-version cannot be used with --status-type upload")
+	}
+
+	if installID != "" {
+		if statusTypeToFetch == "upload" {
+			return 0, fmt.Errorf("--install-id cannot be used with --status-type upload")
+		}
+		if rawJSON, _ := cmd.Flags().GetBool("raw-json"); rawJSON {
+			return fetchRawAndPrint(cmd, map[string]string{"install": getSolutionInstallUrl() + "/" + installID}, nil, headers)
+		}
+		return fetchInstallAndPrint(cmd, installID, solutionName, headers)
+	}
+
+	var since time.Time
+	if cmd.Flags().Changed("since") {
+		sinceValue, _ := cmd.Flags().GetString("since")
+		since, err = cmdkit.ParseTimeBound(sinceValue, time.Now())
+		if err != nil {
+			return 0, fmt.Errorf("invalid --since value: %w", err)
+		}
+	}
 
+	history, _ := cmd.Flags().GetBool("history")
+	maxRecords := 1
+	if history {
+		maxRecords, _ = cmd.Flags().GetInt("max")
+		if maxRecords <= 0 {
+			return 0, fmt.Errorf("invalid --max value %v: must be a positive number", maxRecords)
+		}
+	} else if cmd.Flags().Changed("max") {
+		return 0, fmt.Errorf("--max can only be used together with --history")
+	}
+
+	query := statusQuery(solutionName, solutionVersion, maxRecords, since)
+	if rawJSON, _ := cmd.Flags().GetBool("raw-json"); rawJSON {
+		paths := map[string]string{}
+		if statusTypeToFetch != "install" {
+			paths["upload"] = getSolutionReleaseUrl()
+		}
+		if statusTypeToFetch != "upload" {
+			paths["install"] = getSolutionInstallUrl()
+		}
+		return fetchRawAndPrint(cmd, paths, query, headers)
+	}
+	if history {
+		return fetchHistoryAndPrint(statusTypeToFetch, query, headers, since, cmd)
+	}
+	return fetchValuesAndPrint(statusTypeToFetch, query, headers, since, cmd)
+}
+
+// fetchRawAndPrint displays the response bodies of the upload and/or install requests (keyed by
+// the record type) as returned by the platform: a single body as is, several bodies as an object
+// with a field for each record type. Returns the exit code reflecting whether any records were found.
+func fetchRawAndPrint(cmd *cobra.Command, paths map[string]string, query map[string]string, requestHeaders map[string]string) (int, error) {
+	bodies := map[string]json.RawMessage{}
+	found := false
+	for record, path := range paths {
+		var body []byte
+		err := apiClient(cmd).JSONGet(path, &body, &api.Options{Headers: requestHeaders, QueryParams: query})
+		if api.IsNotFound(err) && query == nil {
+			log.Errorf("No %v record found at %q", record, path)
+			continue
+		}
+		if err != nil {
+			return 0, describeFetchError(err)
+		}
+		bodies[record] = json.RawMessage(body)
+
+		// a collection has items, a single record has data
+		var contents struct {
+			Items []any `json:"items"`
+			Data  any   `json:"data"`
+		}
+		if json.Unmarshal(body, &contents) == nil && (len(contents.Items) > 0 || contents.Data != nil) {
+			found = true
+		}
+	}
+
+	var v any = bodies
+	if len(paths) == 1 {
+		for _, body := range bodies {
+			v = body
+		}
+	}
+	if len(bodies) > 0 {
+		if err := output.PrintJson(cmd, v); err != nil {
+			return 0, fmt.Errorf("failed to display the response: %w", err)
+		}
+	}
+
+	if !found {
+		return statusExitNotFound, nil
+	}
+	return statusExitSuccess, nil
+}
+
+// fetchInstallAndPrint displays the install record with the given ID and returns the exit code
+// reflecting it. If the solution name is specified, the record is expected to belong to it.
+func fetchInstallAndPrint(cmd *cobra.Command, installID string, solutionName string, requestHeaders map[string]string) (int, error) {
+	var item StatusItem
+	err := apiClient(cmd).JSONGet(getSolutionInstallUrl()+"/"+installID, &item, &api.Options{Headers: requestHeaders})
+	if err != nil {
+		if api.IsNotFound(err) {
+			log.Errorf("No install record found with ID %q", installID)
+			return statusExitNotFound, nil
+		}
+		return 0, fmt.Errorf("failed to fetch install record %q: %w", installID, err)
+	}
+	if solutionName != "" && item.StatusData.SolutionName != solutionName {
+		log.Warnf("The install record %q is for solution %q, not %q", installID, item.StatusData.SolutionName, solutionName)
+	}
+
+	return printStatus(cmd, "install", StatusItem{}, item), nil
+}
+
+// statusQuery returns the query parameters to fetch the most recent records
+// of the solution (and version, if not empty), up to maxRecords of them. If since
+// is not zero, only the records created at or after it are fetched, so that the
+// limit applies to those records rather than to the most recent ones.
+func statusQuery(solutionName string, solutionVersion string, maxRecords int, since time.Time) map[string]string {
+	var filterQuery string
 	if solutionVersion != "" {
 		filterQuery = fmt.Sprintf(`data.solutionName eq "%s" and data.solutionVersion eq "%s"`, solutionName, solutionVersion)
 	} else {
 		filterQuery = fmt.Sprintf(`data.solutionName eq "%s"`, solutionName)
 	}
+	if !since.IsZero() {
+		filterQuery += fmt.Sprintf(` and createdAt ge "%s"`, since.UTC().Format(time.RFC3339))
+	}
+
+	return map[string]string{
+		"order":  "desc",
+		"filter": filterQuery,
+		"max":    strconv.Itoa(maxRecords),
+	}
+}
+
+// fetchHistoryAndPrint displays the upload and/or install records, most recent first,
+// and returns the exit code reflecting whether any records were found
+func fetchHistoryAndPrint(operation string, query map[string]string, requestHeaders map[string]string, since time.Time, cmd *cobra.Command) (int, error) {
+	var records []historyRecord
+	addRecords := func(record string, path string) error {
+		items, err := getObjects(apiClient(cmd), path, requestHeaders, query, since)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			records = append(records, historyRecord{Record: record, CreatedAt: item.CreatedAt, StatusData: item.StatusData})
+		}
+		return nil
+	}
 
-	query := fmt.Sprintf("?order=%s&filter=%s&max=1", url.QueryEscape("desc"), url.QueryEscape(filterQuery))
+	if operation != "install" {
+		if err := addRecords("upload", getSolutionReleaseUrl()); err != nil {
+			return 0, err
+		}
+	}
+	if operation != "upload" {
+		if err := addRecords("install", getSolutionInstallUrl()); err != nil {
+			return 0, err
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].CreatedAt > records[j].CreatedAt })
 
-	fetchValuesAndPrint(statusTypeToFetch, query, headers, cmd)
+	lines := make([][]string, 0, len(records))
+	for _, r := range records {
+		successful, message := "", ""
+		if r.Record == "install" {
+			successful = fmt.Sprintf("%v", r.SuccessfulInstall)
+			message = r.InstallMessage
+		}
+		lines = append(lines, []string{r.Record, r.SolutionVersion, r.CreatedAt, successful, message})
+	}
+	output.PrintCmdOutputCustom(cmd, records, &output.Table{
+		Headers: []string{"Record", "Version", "Created At", "Successful?", "Message"},
+		Lines:   lines,
+	})
 
-	return nil
+	if len(records) == 0 {
+		return statusExitNotFound, nil
+	}
+	return statusExitSuccess, nil
 }
 
 func getSolutionReleaseUrl() string {
-	return "objstore/v1beta/objects/extensibility:solutionRelease%s"
+	return config.GetObjStoreBasePath() + "/objects/extensibility:solutionRelease"
 }
 
 func getSolutionInstallUrl() string {
-	return "objstore/v1beta/objects/extensibility:solutionInstall%s"
+	return config.GetObjStoreBasePath() + "/objects/extensibility:solutionInstall"
 }
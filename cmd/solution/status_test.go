@@ -0,0 +1,472 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmdkit"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// testPlatformClient directs the requests of the commands created by newTestStatusCmd
+// to the server started by startTestPlatform
+var testPlatformClient *api.Client
+
+// startTestPlatform starts a local server with the given handler and directs
+// the status requests to it
+func startTestPlatform(t *testing.T, handler http.HandlerFunc) {
+	srv := httptest.NewServer(handler)
+
+	viper.Set("contexts", []map[string]any{
+		{"name": "test", "server": "platform.invalid", "tenant": "test-tenant", "token": "test-token"},
+	})
+	viper.Set("current_context", "test")
+	testPlatformClient = &api.Client{BaseURL: srv.URL}
+
+	t.Cleanup(func() {
+		testPlatformClient = nil
+		srv.Close()
+	})
+}
+
+// statusHandler responds to the solution release and install requests with the given bodies
+func statusHandler(releaseBody, installBody string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "extensibility:solutionRelease"):
+			_, _ = w.Write([]byte(releaseBody))
+		case strings.HasSuffix(r.URL.Path, "extensibility:solutionInstall"):
+			_, _ = w.Write([]byte(installBody))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func newTestStatusCmd(t *testing.T, statusType string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("name", "", "")
+	cmd.Flags().String("solution-version", "", "")
+	cmd.Flags().String("status-type", "", "")
+	cmd.Flags().String("since", "", "")
+	cmd.Flags().String("layer-type", "TENANT", "")
+	cmd.Flags().String("layer-id", "", "")
+	cmd.Flags().Bool("history", false, "")
+	cmd.Flags().Int("max", defaultHistoryMax, "")
+	require.Nil(t, cmd.Flags().Set("name", "mysolution"))
+	if statusType != "" {
+		require.Nil(t, cmd.Flags().Set("status-type", statusType))
+	}
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetContext(api.WithClient(context.Background(), testPlatformClient))
+	return cmd, &out
+}
+
+const (
+	testReleaseBody = `{"items": [{"createdAt": "2023-01-02T03:04:05Z", "data": {"solutionName": "mysolution", "solutionVersion": "1.2.3"}}]}`
+	testInstallBody = `{"items": [{"createdAt": "2023-01-02T03:05:05Z", "data": {"solutionName": "mysolution", "solutionVersion": "1.2.2", "isSuccessful": true, "installMessage": "installed ok"}}]}`
+)
+
+// runSolutionStatus runs the status command, which is expected to succeed, and returns its exit code
+func runSolutionStatus(t *testing.T, cmd *cobra.Command) int {
+	exitCode, err := getSolutionStatus(cmd, nil)
+	require.Nil(t, err)
+	return exitCode
+}
+
+func TestGetSolutionStatusUpload(t *testing.T) {
+	startTestPlatform(t, statusHandler(testReleaseBody, testInstallBody))
+	cmd, out := newTestStatusCmd(t, "upload")
+
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), "Solution Upload Version: 1.2.3")
+	assert.NotContains(t, out.String(), "Solution Install Version")
+}
+
+func TestGetSolutionStatusInstall(t *testing.T) {
+	startTestPlatform(t, statusHandler(testReleaseBody, testInstallBody))
+	cmd, out := newTestStatusCmd(t, "install")
+
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), "Solution Install Version: 1.2.2")
+	assert.Contains(t, out.String(), "installed ok")
+	assert.NotContains(t, out.String(), "Solution Upload Version")
+}
+
+func TestGetSolutionStatusAll(t *testing.T) {
+	startTestPlatform(t, statusHandler(testReleaseBody, testInstallBody))
+	cmd, out := newTestStatusCmd(t, "")
+
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), "Solution Upload Version: 1.2.3")
+	assert.Contains(t, out.String(), "Solution Install Version: 1.2.2")
+}
+
+func TestGetSolutionStatusEmpty(t *testing.T) {
+	startTestPlatform(t, statusHandler(`{"items": []}`, `{"items": []}`))
+
+	item, err := getObject(testPlatformClient, getSolutionReleaseUrl(), nil, nil, time.Time{})
+	require.Nil(t, err)
+	assert.Equal(t, StatusItem{}, item)
+
+	cmd, out := newTestStatusCmd(t, "")
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), "Solution Name: mysolution")
+	assert.Contains(t, out.String(), noUploadRecord)
+	assert.Contains(t, out.String(), noInstallRecord)
+	assert.NotContains(t, out.String(), "Solution Install Successful?")
+}
+
+func TestGetSolutionStatusUploadedNotInstalled(t *testing.T) {
+	startTestPlatform(t, statusHandler(testReleaseBody, `{"items": []}`))
+
+	cmd, out := newTestStatusCmd(t, "install")
+	assert.Equal(t, statusExitNotFound, runSolutionStatus(t, cmd))
+	assert.Contains(t, out.String(), "Solution Install: "+noInstallRecord)
+	assert.NotContains(t, out.String(), "Solution Install Time")
+
+	cmd, out = newTestStatusCmd(t, "")
+	cmd.Flags().String("output", "json", "")
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), `"state":"uploaded"`)
+}
+
+func TestSolutionStatusCmdExitCode(t *testing.T) {
+	startTestPlatform(t, statusHandler(testReleaseBody, `{"items": []}`))
+
+	// a status that is not successful is returned as the exit code, not by exiting the process
+	cmd, _ := newTestStatusCmd(t, "install")
+	err := solutionStatusCmd.RunE(cmd, nil)
+	var exitErr cmdkit.ExitCodeError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, statusExitNotFound, exitErr.Code)
+	assert.True(t, cmd.SilenceErrors)
+
+	cmd, _ = newTestStatusCmd(t, "upload")
+	assert.Nil(t, solutionStatusCmd.RunE(cmd, nil))
+}
+
+func TestGetObjectConnectionError(t *testing.T) {
+	startTestPlatform(t, func(w http.ResponseWriter, r *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		require.True(t, ok)
+		conn, _, err := hj.Hijack()
+		require.Nil(t, err)
+		conn.Close()
+	})
+
+	_, err := getObject(testPlatformClient, getSolutionReleaseUrl(), nil, nil, time.Time{})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "could not reach the platform")
+}
+
+func TestGetObjectServerError(t *testing.T) {
+	startTestPlatform(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("internal error"))
+	})
+
+	_, err := getObject(testPlatformClient, getSolutionReleaseUrl(), nil, nil, time.Time{})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "platform returned 500")
+}
+
+func TestGetSolutionStatusInstallFailed(t *testing.T) {
+	failedInstallBody := `{"items": [{"createdAt": "2023-01-02T03:05:05Z", "data": {"solutionName": "mysolution", "solutionVersion": "1.2.3", "isSuccessful": false, "installMessage": "dependency foo is not installed"}}]}`
+	startTestPlatform(t, statusHandler(testReleaseBody, failedInstallBody))
+
+	cmd, out := newTestStatusCmd(t, "install")
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), "Install failed:\ndependency foo is not installed\n")
+
+	cmd, out = newTestStatusCmd(t, "install")
+	cmd.Flags().String("output", "json", "")
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), `"error":"dependency foo is not installed"`)
+}
+
+func statusItem(name, version string) StatusItem {
+	return StatusItem{StatusData: StatusData{SolutionName: name, SolutionVersion: version}}
+}
+
+func TestWaitForInstall(t *testing.T) {
+	polls := 0
+	fetch := func() (StatusItem, StatusItem, error) {
+		polls++
+		if polls < 3 {
+			return statusItem("mysolution", "1.2.3"), statusItem("mysolution", "1.2.2"), nil
+		}
+		return statusItem("mysolution", "1.2.3"), statusItem("mysolution", "1.2.3"), nil
+	}
+
+	_, install, err := waitForInstall(context.Background(), fetch, pollOptions{Interval: time.Millisecond, Backoff: 2})
+	require.Nil(t, err)
+	assert.Equal(t, 3, polls)
+	assert.Equal(t, "1.2.3", install.StatusData.SolutionVersion)
+}
+
+func TestWaitForInstallTimeout(t *testing.T) {
+	fetch := func() (StatusItem, StatusItem, error) {
+		return statusItem("mysolution", "1.2.3"), StatusItem{}, nil
+	}
+
+	_, _, err := waitForInstall(context.Background(), fetch, pollOptions{Interval: time.Millisecond, Backoff: 1, Timeout: 20 * time.Millisecond})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "timed out after 20ms")
+}
+
+func TestWaitForInstallCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fetch := func() (StatusItem, StatusItem, error) {
+		cancel()
+		return statusItem("mysolution", "1.2.3"), StatusItem{}, nil
+	}
+
+	_, _, err := waitForInstall(ctx, fetch, pollOptions{Interval: time.Hour, Backoff: 1})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "stopped waiting")
+}
+
+func TestNextPollInterval(t *testing.T) {
+	opts := pollOptions{Interval: 10 * time.Second, Backoff: 2}
+	assert.Equal(t, 20*time.Second, nextPollInterval(10*time.Second, opts))
+	assert.Equal(t, maxPollInterval, nextPollInterval(40*time.Second, opts))
+
+	// a longer interval than the cap is not shortened when backing off
+	opts = pollOptions{Interval: 5 * time.Minute, Backoff: 2}
+	assert.Equal(t, 5*time.Minute, nextPollInterval(5*time.Minute, opts))
+	opts.Backoff = 1
+	assert.Equal(t, 5*time.Minute, nextPollInterval(5*time.Minute, opts))
+}
+
+func TestGetSolutionStatusResolvedVersion(t *testing.T) {
+	startTestPlatform(t, statusHandler(testReleaseBody, testInstallBody))
+
+	cmd, out := newTestStatusCmd(t, "")
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), "Showing latest: 1.2.3")
+
+	cmd, out = newTestStatusCmd(t, "")
+	require.Nil(t, cmd.Flags().Set("solution-version", "latest"))
+	cmd.Flags().String("output", "json", "")
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), `"resolvedVersion":"1.2.3"`)
+
+	cmd, out = newTestStatusCmd(t, "")
+	require.Nil(t, cmd.Flags().Set("solution-version", "1.2.3"))
+	runSolutionStatus(t, cmd)
+	assert.NotContains(t, out.String(), "Showing latest")
+}
+
+func TestSolutionStatusExitCode(t *testing.T) {
+	startTestPlatform(t, statusHandler(testReleaseBody, testInstallBody))
+	cmd, _ := newTestStatusCmd(t, "upload")
+	assert.Equal(t, statusExitSuccess, runSolutionStatus(t, cmd))
+	cmd, _ = newTestStatusCmd(t, "install")
+	assert.Equal(t, statusExitSuccess, runSolutionStatus(t, cmd))
+	cmd, _ = newTestStatusCmd(t, "all")
+	assert.Equal(t, statusExitInProgress, runSolutionStatus(t, cmd)) // 1.2.3 uploaded, 1.2.2 installed
+
+	failedInstallBody := `{"items": [{"createdAt": "2023-01-02T03:05:05Z", "data": {"solutionName": "mysolution", "solutionVersion": "1.2.3", "isSuccessful": false}}]}`
+	startTestPlatform(t, statusHandler(testReleaseBody, failedInstallBody))
+	cmd, _ = newTestStatusCmd(t, "")
+	assert.Equal(t, statusExitFailed, runSolutionStatus(t, cmd))
+
+	startTestPlatform(t, statusHandler(`{"items": []}`, `{"items": []}`))
+	cmd, _ = newTestStatusCmd(t, "")
+	assert.Equal(t, statusExitNotFound, runSolutionStatus(t, cmd))
+	cmd, _ = newTestStatusCmd(t, "install")
+	assert.Equal(t, statusExitNotFound, runSolutionStatus(t, cmd))
+}
+
+func TestGetSolutionStatusHistory(t *testing.T) {
+	var maxParams []string
+	handler := statusHandler(testReleaseBody, testInstallBody)
+	startTestPlatform(t, func(w http.ResponseWriter, r *http.Request) {
+		maxParams = append(maxParams, r.URL.Query().Get("max"))
+		handler(w, r)
+	})
+
+	cmd, out := newTestStatusCmd(t, "")
+	require.Nil(t, cmd.Flags().Set("history", "true"))
+	require.Nil(t, cmd.Flags().Set("max", "5"))
+	assert.Equal(t, statusExitSuccess, runSolutionStatus(t, cmd))
+	assert.Equal(t, []string{"5", "5"}, maxParams)
+	assert.Contains(t, out.String(), "upload")
+	assert.Contains(t, out.String(), "installed ok")
+
+	// --max must be positive
+	cmd, _ = newTestStatusCmd(t, "")
+	require.Nil(t, cmd.Flags().Set("history", "true"))
+	require.Nil(t, cmd.Flags().Set("max", "0"))
+	_, err := getSolutionStatus(cmd, nil)
+	assert.NotNil(t, err)
+
+	// --max requires --history
+	cmd, _ = newTestStatusCmd(t, "")
+	require.Nil(t, cmd.Flags().Set("max", "5"))
+	_, err = getSolutionStatus(cmd, nil)
+	assert.NotNil(t, err)
+
+	// no records
+	startTestPlatform(t, statusHandler(`{"items": []}`, `{"items": []}`))
+	cmd, _ = newTestStatusCmd(t, "install")
+	require.Nil(t, cmd.Flags().Set("history", "true"))
+	assert.Equal(t, statusExitNotFound, runSolutionStatus(t, cmd))
+}
+
+func TestStatusQuery(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"order":  "desc",
+		"filter": `data.solutionName eq "mysolution"`,
+		"max":    "1",
+	}, statusQuery("mysolution", "", 1, time.Time{}))
+	assert.Equal(t, `data.solutionName eq "mysolution" and data.solutionVersion eq "1.0.0"`, statusQuery("mysolution", "1.0.0", 10, time.Time{})["filter"])
+	assert.Equal(t, "10", statusQuery("mysolution", "1.0.0", 10, time.Time{})["max"])
+
+	// --since is applied by the platform, so that the latest record since then is found
+	since := time.Date(2023, 5, 10, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	assert.Equal(t, `data.solutionName eq "mysolution" and createdAt ge "2023-05-10T12:00:00Z"`, statusQuery("mysolution", "", 1, since)["filter"])
+}
+
+func TestGetSolutionStatusInstalledBy(t *testing.T) {
+	installBody := `{"items": [{"createdAt": "2023-01-02T03:05:05Z", "data": {"solutionName": "mysolution", "solutionVersion": "1.2.3", "isSuccessful": true, "installedBy": "fsoc"}}]}`
+	startTestPlatform(t, statusHandler(testReleaseBody, installBody))
+
+	// shown only in the wide output
+	cmd, out := newTestStatusCmd(t, "install")
+	cmd.Flags().String("output", "", "")
+	runSolutionStatus(t, cmd)
+	assert.NotContains(t, out.String(), "Installed By")
+
+	cmd, out = newTestStatusCmd(t, "install")
+	cmd.Flags().String("output", "wide", "")
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), "Solution Installed By: fsoc")
+
+	cmd, out = newTestStatusCmd(t, "install")
+	cmd.Flags().String("output", "json", "")
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), `"installedBy":"fsoc"`)
+}
+
+func TestGetSolutionStatusCaseInsensitive(t *testing.T) {
+	startTestPlatform(t, statusHandler(testReleaseBody, testInstallBody))
+	cmd, out := newTestStatusCmd(t, "Install")
+	require.Nil(t, cmd.Flags().Set("layer-type", "tenant"))
+	require.Nil(t, cmd.Flags().Set("layer-id", "tenant-1"))
+
+	runSolutionStatus(t, cmd)
+	assert.Contains(t, out.String(), "Solution Install Version: 1.2.2")
+	assert.NotContains(t, out.String(), "Solution Upload Version")
+}
+
+func TestGetSolutionStatusByInstallID(t *testing.T) {
+	startTestPlatform(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "extensibility:solutionInstall/inst-1") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "inst-1", "createdAt": "2023-01-02T03:05:05Z", "data": {"solutionName": "othersolution", "solutionVersion": "1.0.0", "isSuccessful": true}}`))
+	})
+
+	// the name is optional with --install-id
+	cmd, out := newTestStatusCmd(t, "")
+	cmd.Flags().String("install-id", "", "")
+	require.Nil(t, cmd.Flags().Set("name", ""))
+	require.Nil(t, cmd.Flags().Set("install-id", "inst-1"))
+	assert.Equal(t, statusExitSuccess, runSolutionStatus(t, cmd))
+	assert.Contains(t, out.String(), "Solution Name: othersolution")
+	assert.Contains(t, out.String(), "Solution Install Version: 1.0.0")
+	assert.NotContains(t, out.String(), "Solution Upload")
+	assert.NotContains(t, out.String(), "Showing latest")
+
+	cmd, _ = newTestStatusCmd(t, "")
+	cmd.Flags().String("install-id", "", "")
+	require.Nil(t, cmd.Flags().Set("install-id", "inst-2"))
+	assert.Equal(t, statusExitNotFound, runSolutionStatus(t, cmd))
+
+	// either the name or the install ID is required
+	cmd, _ = newTestStatusCmd(t, "")
+	require.Nil(t, cmd.Flags().Set("name", ""))
+	_, err := getSolutionStatus(cmd, nil)
+	assert.NotNil(t, err)
+}
+
+func TestGetSolutionStatusRawJSON(t *testing.T) {
+	releaseBody := `{"items": [{"id": "rel-1", "createdAt": "2023-01-02T03:04:05Z", "data": {"solutionName": "mysolution", "solutionVersion": "1.2.3", "manifest": {"dependencies": ["foo"]}}}]}`
+	startTestPlatform(t, statusHandler(releaseBody, testInstallBody))
+
+	// a single record type is displayed as returned, including the fields not in the status
+	cmd, out := newTestStatusCmd(t, "upload")
+	cmd.Flags().Bool("raw-json", false, "")
+	require.Nil(t, cmd.Flags().Set("raw-json", "true"))
+	assert.Equal(t, statusExitSuccess, runSolutionStatus(t, cmd))
+	assert.JSONEq(t, releaseBody, out.String())
+
+	// both record types are displayed as fields of an object
+	cmd, out = newTestStatusCmd(t, "")
+	cmd.Flags().Bool("raw-json", false, "")
+	require.Nil(t, cmd.Flags().Set("raw-json", "true"))
+	runSolutionStatus(t, cmd)
+	assert.JSONEq(t, `{"upload": `+releaseBody+`, "install": `+testInstallBody+`}`, out.String())
+}
+
+func TestGetSolutionStatusExpectVersion(t *testing.T) {
+	startTestPlatform(t, statusHandler(testReleaseBody, testInstallBody))
+
+	cmd, out := newTestStatusCmd(t, "install")
+	cmd.Flags().String("expect-version", "", "")
+	require.Nil(t, cmd.Flags().Set("expect-version", "v1.2.2"))
+	assert.Equal(t, statusExitSuccess, runSolutionStatus(t, cmd))
+	assert.Contains(t, out.String(), "Expected version v1.2.2 is installed")
+
+	cmd, out = newTestStatusCmd(t, "install")
+	cmd.Flags().String("expect-version", "", "")
+	cmd.Flags().String("output", "json", "")
+	require.Nil(t, cmd.Flags().Set("expect-version", "1.2.3"))
+	assert.Equal(t, statusExitWrongVersion, runSolutionStatus(t, cmd))
+	assert.Contains(t, out.String(), `"expectedVersion":"1.2.3"`)
+	assert.Contains(t, out.String(), `"versionMismatch":true`)
+
+	// the install being in progress takes precedence over the version check
+	cmd, _ = newTestStatusCmd(t, "")
+	cmd.Flags().String("expect-version", "", "")
+	require.Nil(t, cmd.Flags().Set("expect-version", "1.2.2"))
+	assert.Equal(t, statusExitInProgress, runSolutionStatus(t, cmd))
+
+	cmd, _ = newTestStatusCmd(t, "upload")
+	cmd.Flags().String("expect-version", "", "")
+	require.Nil(t, cmd.Flags().Set("expect-version", "1.2.2"))
+	_, err := getSolutionStatus(cmd, nil)
+	assert.ErrorContains(t, err, "--expect-version cannot be used with --status-type upload")
+}
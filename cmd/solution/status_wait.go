@@ -0,0 +1,122 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+)
+
+// maxPollInterval caps the poll interval when backing off, unless the initial interval is longer
+const maxPollInterval = time.Minute
+
+// pollOptions controls how often the status is re-queried while waiting for an install
+type pollOptions struct {
+	Interval time.Duration // time between the first queries
+	Backoff  float64       // factor by which the interval grows after each query (1 for a fixed interval)
+	Timeout  time.Duration // overall time limit for waiting; 0 for no limit
+}
+
+// addWaitFlags adds the flags for waiting for an install to complete
+func addWaitFlags(cmd *cobra.Command, waitUsage string) {
+	cmd.Flags().
+		Bool("wait", false, waitUsage)
+	cmd.Flags().
+		Duration("poll-interval", 5*time.Second, "How often to check the status while waiting")
+	cmd.Flags().
+		Float64("poll-backoff", 1, "Factor by which the poll interval grows after each check, up to 1m (1 for a fixed interval)")
+	cmd.Flags().
+		Duration("timeout", 10*time.Minute, "Maximum time to wait for the install to complete (0 for no limit)")
+}
+
+func getPollOptions(cmd *cobra.Command) (pollOptions, error) {
+	var opts pollOptions
+	opts.Interval, _ = cmd.Flags().GetDuration("poll-interval")
+	opts.Backoff, _ = cmd.Flags().GetFloat64("poll-backoff")
+	opts.Timeout, _ = cmd.Flags().GetDuration("timeout")
+
+	if opts.Interval <= 0 {
+		return opts, fmt.Errorf("--poll-interval must be positive")
+	}
+	if opts.Backoff < 1 {
+		return opts, fmt.Errorf("--poll-backoff must be at least 1")
+	}
+	if opts.Timeout < 0 {
+		return opts, fmt.Errorf("--timeout cannot be negative")
+	}
+	return opts, nil
+}
+
+// installCompleted returns true if there is an install record for the latest upload
+func installCompleted(upload, install StatusItem) bool {
+	if install.StatusData.SolutionName == "" {
+		return false // no install yet
+	}
+	return upload.StatusData.SolutionVersion == "" || install.StatusData.SolutionVersion == upload.StatusData.SolutionVersion
+}
+
+// waitForInstall queries the upload and install status until the latest upload has been installed
+// (successfully or not), the timeout expires or the context is cancelled
+func waitForInstall(ctx context.Context, fetch func() (StatusItem, StatusItem, error), opts pollOptions) (StatusItem, StatusItem, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	interval := opts.Interval
+	for {
+		upload, install, err := fetch()
+		if err != nil {
+			return upload, install, err
+		}
+		if installCompleted(upload, install) {
+			return upload, install, nil
+		}
+
+		log.Infof("Install of version %q not completed yet; checking again in %v", upload.StatusData.SolutionVersion, interval)
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return upload, install, fmt.Errorf("timed out after %v waiting for the solution install to complete", opts.Timeout)
+			}
+			return upload, install, fmt.Errorf("stopped waiting for the solution install: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = nextPollInterval(interval, opts)
+	}
+}
+
+// nextPollInterval backs off the poll interval, up to maxPollInterval or the initial interval,
+// whichever is longer, so that backing off never polls more often than requested
+func nextPollInterval(interval time.Duration, opts pollOptions) time.Duration {
+	limit := maxPollInterval
+	if opts.Interval > limit {
+		limit = opts.Interval
+	}
+	interval = time.Duration(float64(interval) * opts.Backoff)
+	if interval > limit {
+		interval = limit
+	}
+	return interval
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+)
+
+// watchStatus fetches and displays the status every interval until the context is canceled (e.g., by an
+// interrupt). On a terminal, the status is redrawn in place; otherwise, a timestamped snapshot is appended
+// each time. Failures to fetch the status are displayed and watching continues. Returns the exit code
+// reflecting the last status displayed.
+func watchStatus(ctx context.Context, cmd *cobra.Command, interval time.Duration, fetch func() (StatusItem, StatusItem, error), print func(upload, install StatusItem) int) int {
+	inPlace := output.IsTerminal(cmd)
+	exitCode := statusExitError
+	for {
+		upload, install, err := fetch()
+		if ctx.Err() != nil {
+			return exitCode // interrupted while fetching, keep the last status
+		}
+
+		if inPlace {
+			output.ClearScreen(cmd)
+			output.PrintCmdStatus(cmd, fmt.Sprintf("Every %v, until interrupted (Ctrl-C)\t%v\n\n", interval, time.Now().Format(time.RFC3339)))
+		} else {
+			output.PrintCmdStatus(cmd, fmt.Sprintf("--- %v\n", time.Now().Format(time.RFC3339)))
+		}
+		if err != nil {
+			log.Warnf("Failed to fetch the status: %v; retrying in %v", err, interval)
+			exitCode = statusExitError
+		} else {
+			exitCode = print(upload, install)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return exitCode
+		case <-timer.C:
+		}
+	}
+}
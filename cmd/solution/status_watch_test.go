@@ -0,0 +1,57 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchStatus(t *testing.T) {
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	fetches := 0
+	fetch := func() (StatusItem, StatusItem, error) {
+		fetches++
+		if fetches == 2 {
+			return StatusItem{}, StatusItem{}, errors.New("unreachable")
+		}
+		return StatusItem{}, StatusItem{}, nil
+	}
+	print := func(upload, install StatusItem) int {
+		if fetches == 3 {
+			cancel() // interrupted while displaying the third status
+		}
+		return statusExitInProgress
+	}
+
+	exitCode := watchStatus(ctx, cmd, time.Millisecond, fetch, print)
+	assert.Equal(t, statusExitInProgress, exitCode)
+	assert.Equal(t, 3, fetches)
+
+	// not a terminal: snapshots are appended, not redrawn
+	assert.Equal(t, 3, strings.Count(out.String(), "--- "))
+	assert.NotContains(t, out.String(), "\033[2J")
+}
@@ -69,15 +69,7 @@ func manageSubscription(cmd *cobra.Command, args []string, isSubscribed bool) {
 	cfg := config.GetCurrentContext()
 	layerID := cfg.Tenant
 
-	headers := map[string]string{
-		"layer-type": "TENANT",
-		"layer-id":   layerID,
-	}
-
-	subscribe := subscriptionStruct{IsSubscribed: isSubscribed}
-
-	var res any
-	err := api.JSONPatch(getSolutionSubscribeUrl()+"/"+solutionName, &subscribe, &res, &api.Options{Headers: headers})
+	err := setSubscription(apiClient(cmd), layerID, solutionName, isSubscribed)
 	if err != nil {
 		log.Errorf("Solution command failed: %v", err.Error())
 		return
@@ -92,10 +84,23 @@ func manageSubscription(cmd *cobra.Command, args []string, isSubscribed bool) {
 	output.PrintCmdStatus(cmd, message)
 }
 
+// setSubscription subscribes the tenant to the solution or unsubscribes it from the solution
+func setSubscription(client *api.Client, tenantID string, solutionName string, isSubscribed bool) error {
+	headers := map[string]string{
+		"layer-type": "TENANT",
+		"layer-id":   tenantID,
+	}
+
+	subscribe := subscriptionStruct{IsSubscribed: isSubscribed}
+
+	var res any
+	return client.JSONPatch(getSolutionSubscribeUrl()+"/"+solutionName, &subscribe, &res, &api.Options{Headers: headers})
+}
+
 func subscribeToSolution(cmd *cobra.Command, args []string) {
 	manageSubscription(cmd, args, true)
 }
 
 func getSolutionSubscribeUrl() string {
-	return "objstore/v1beta/objects/extensibility:solution"
+	return config.GetObjStoreBasePath() + "/objects/extensibility:solution"
 }
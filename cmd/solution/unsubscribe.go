@@ -16,11 +16,12 @@ package solution
 
 import (
 	"fmt"
+	"strings"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
 )
 
@@ -30,9 +31,12 @@ var solutionUnsubscribeCmd = &cobra.Command{
 	Long: `This command allows the current tenant specified in the profile to unsubscribe from a solution.
 
 Usage:
-	fsoc solution unsubscribe --name=<solution name>`,
+	fsoc solution unsubscribe --name=<solution name> [--yes] [--force]
+
+Unsubscribing from a solution that other subscribed solutions depend on is refused,
+listing the dependent solutions, unless --force is specified.`,
 	Args:             cobra.ExactArgs(0),
-	Run:              unsubscribeFromSolution,
+	RunE:             unsubscribeFromSolution,
 	TraverseChildren: true,
 }
 
@@ -40,30 +44,50 @@ func getUnsubscribeSolutionCmd() *cobra.Command {
 	solutionUnsubscribeCmd.Flags().
 		String("name", "", "The name of the solution the tenant is unsubscribing from")
 	_ = solutionUnsubscribeCmd.MarkFlagRequired("name")
+	solutionUnsubscribeCmd.Flags().
+		Bool("force", false, "Unsubscribe even if other subscribed solutions depend on the solution")
+	output.AddConfirmFlag(solutionUnsubscribeCmd)
 
 	return solutionUnsubscribeCmd
 
 }
 
-func unsubscribeFromSolution(cmd *cobra.Command, args []string) {
+func unsubscribeFromSolution(cmd *cobra.Command, args []string) error {
 	solutionName, _ := cmd.Flags().GetString("name")
 	if solutionName == "" {
-		log.Fatal("Solution name cannot be empty, use --name=SOLUTION")
+		return fmt.Errorf("Solution name cannot be empty, use --name=SOLUTION")
 	}
 
-	isSystemSolution, err := isSystemSolution(solutionName)
+	isSystemSolution, err := isSystemSolution(apiClient(cmd), solutionName)
 	if err != nil {
-		log.Fatalf("Failed to check solution status: %v", err.Error())
-		return
+		return fmt.Errorf("Failed to check solution status: %w", err)
 	}
 	if isSystemSolution {
-		log.Fatalf("Cannot unsubscribe tenant from solution %s because it is a system solution\n", solutionName)
-	} else {
-		manageSubscription(cmd, args, false)
+		return fmt.Errorf("Cannot unsubscribe tenant from solution %s because it is a system solution", solutionName)
+	}
+
+	// refuse to break solutions that depend on this one, unless forced
+	if force, _ := cmd.Flags().GetBool("force"); !force {
+		dependents, err := findDependents(apiClient(cmd), config.GetCurrentContext().Tenant, solutionName)
+		if err != nil {
+			return fmt.Errorf("Failed to check for dependent solutions: %w", err)
+		}
+		if len(dependents) > 0 {
+			return fmt.Errorf("Cannot unsubscribe tenant from solution %s because these subscribed solutions depend on it: %s; use --force to unsubscribe anyway", solutionName, strings.Join(dependents, ", "))
+		}
+	}
+
+	if ok, err := output.Confirm(cmd, fmt.Sprintf("Unsubscribe the tenant from solution %s?", solutionName)); err != nil {
+		return fmt.Errorf("Tenant not unsubscribed: %w", err)
+	} else if !ok {
+		output.PrintCmdStatus(cmd, "Unsubscribe cancelled\n")
+		return nil
 	}
+	manageSubscription(cmd, args, false)
+	return nil
 }
 
-func isSystemSolution(solutionName string) (bool, error) {
+func isSystemSolution(client *api.Client, solutionName string) (bool, error) {
 	cfg := config.GetCurrentContext()
 	layerID := cfg.Tenant
 
@@ -77,7 +101,7 @@ func isSystemSolution(solutionName string) (bool, error) {
 	}
 
 	getSolutionUrl := fmt.Sprintf(getSolutionListUrl()+"/%s", solutionName)
-	err := api.JSONGet(getSolutionUrl, &solData, &api.Options{Headers: headers})
+	err := client.JSONGet(getSolutionUrl, &solData, &api.Options{Headers: headers})
 	if err != nil {
 		return false, fmt.Errorf("Failed to get solution info: %v", err)
 	}
@@ -107,7 +107,7 @@ func validateSolution(cmd *cobra.Command, args []string) {
 
 	var res Result
 
-	err = api.HTTPPost(getSolutionValidateUrl(), body.Bytes(), &res, &api.Options{Headers: headers})
+	err = apiClient(cmd).HTTPPost(getSolutionValidateUrl(), body.Bytes(), &res, &api.Options{Headers: headers, ReadOnly: true})
 
 	if err != nil {
 		log.Fatalf("Solution validate command failed: %v", err.Error())
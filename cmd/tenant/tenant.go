@@ -0,0 +1,120 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// tenantsPath is the path of the tenant details API
+const tenantsPath = "/administration/v1beta/tenants/"
+
+// tenantInfo is the information displayed about the current tenant
+type tenantInfo struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name,omitempty"`
+	Server       string   `json:"server"`
+	Profile      string   `json:"profile"`
+	Entitlements []string `json:"entitlements,omitempty"`
+}
+
+func NewSubCmd() *cobra.Command {
+	tenantCmd := &cobra.Command{
+		Use:   "tenant",
+		Short: "Tenant information",
+		Long:  `View information about the tenant of the current context.`,
+	}
+	tenantCmd.AddCommand(newInfoCmd())
+	return tenantCmd
+}
+
+func newInfoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "info",
+		Short: "Display the details of the current tenant",
+		Long: `Fetch and display the details of the tenant of the current context, as known to the platform:
+its ID, name and entitlements, together with the server and profile used.
+
+Use this command to confirm which tenant the credentials map to before running commands that
+change or delete data. Unlike "fsoc config show", which displays the locally configured values,
+the details are obtained from the platform.`,
+		Example: `  fsoc tenant info
+  fsoc tenant info --output json`,
+		Args:             cobra.NoArgs,
+		Run:              tenantInfoCmd,
+		TraverseChildren: true,
+	}
+}
+
+func tenantInfoCmd(cmd *cobra.Command, args []string) {
+	cfg := config.GetCurrentContext()
+	if cfg.Tenant == "" {
+		log.Fatalf("The current context %q has no tenant ID; try 'fsoc login' first", cfg.Name)
+	}
+
+	var res map[string]any
+	if err := api.JSONGet(tenantsPath+cfg.Tenant, &res, nil); err != nil {
+		log.Fatalf("Failed to get the details of tenant %q: %v", cfg.Tenant, err)
+	}
+
+	info := parseTenantInfo(res)
+	if info.ID == "" {
+		info.ID = cfg.Tenant
+	} else if info.ID != cfg.Tenant {
+		log.Warnf("The platform returned tenant %q, which differs from the context's tenant %q", info.ID, cfg.Tenant)
+	}
+	info.Server = cfg.Server
+	info.Profile = cfg.Name
+
+	output.PrintCmdOutputCustom(cmd, info, &output.Table{
+		Headers: []string{"ID", "Name", "Server", "Profile", "Entitlements"},
+		Lines:   [][]string{{info.ID, info.Name, info.Server, info.Profile, strings.Join(info.Entitlements, ", ")}},
+		Detail:  true,
+	})
+}
+
+// parseTenantInfo extracts the tenant details from the platform's response
+func parseTenantInfo(res map[string]any) tenantInfo {
+	var info tenantInfo
+	info.ID, _ = res["id"].(string)
+	info.Name, _ = res["name"].(string)
+	if entitlements, ok := res["entitlements"].([]any); ok {
+		for _, e := range entitlements {
+			info.Entitlements = append(info.Entitlements, entitlementName(e))
+		}
+	}
+	return info
+}
+
+// entitlementName returns the name of an entitlement, which can be a string
+// or an object with a name or id field
+func entitlementName(e any) string {
+	if m, ok := e.(map[string]any); ok {
+		for _, field := range []string{"name", "id"} {
+			if name, ok := m[field].(string); ok {
+				return name
+			}
+		}
+	}
+	return fmt.Sprintf("%v", e)
+}
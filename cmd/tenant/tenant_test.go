@@ -0,0 +1,40 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTenantInfo(t *testing.T) {
+	info := parseTenantInfo(map[string]any{
+		"id":   "0f5b2f2b-0000-0000-0000-000000000000",
+		"name": "acme",
+		"entitlements": []any{
+			"apm",
+			map[string]any{"name": "logs", "id": "e1"},
+			map[string]any{"id": "e2"},
+		},
+	})
+	assert.Equal(t, tenantInfo{
+		ID:           "0f5b2f2b-0000-0000-0000-000000000000",
+		Name:         "acme",
+		Entitlements: []string{"apm", "logs", "e2"},
+	}, info)
+
+	assert.Equal(t, tenantInfo{}, parseTenantInfo(map[string]any{}))
+}
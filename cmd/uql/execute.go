@@ -106,7 +106,7 @@ func (b defaultBackend) Execute(query *Query, apiVersion ApiVersion) (parsedResp
 	log.WithFields(log.Fields{"query": query.Str, "apiVersion": apiVersion}).Info("executing UQL query")
 
 	var rawJson json.RawMessage
-	err := api.JSONPost("/monitoring/"+string(apiVersion)+"/query/execute", query, &rawJson, nil)
+	err := api.JSONPost("/monitoring/"+string(apiVersion)+"/query/execute", query, &rawJson, &api.Options{ReadOnly: true})
 	if err != nil {
 		if problem, ok := err.(api.Problem); ok {
 			return parsedResponse{}, makeUqlProblem(problem)
@@ -0,0 +1,47 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdkit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ExitCodeDeadlineExceeded is the exit code of a command stopped by its deadline (as in timeout(1))
+const ExitCodeDeadlineExceeded = 124
+
+// WithDeadline returns a context that expires after the given duration, bounding the whole
+// command, including retries and waiting loops. When the deadline is exceeded, requests in
+// progress are aborted (see RequestContext) and the command returns, running its deferred
+// cleanup; DeadlineError then reports the deadline with ExitCodeDeadlineExceeded.
+func WithDeadline(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	requestCtx, cancelRequests := context.WithTimeout(RequestContext(parent), timeout)
+	return context.WithValue(ctx, requestContextKey{}, requestCtx), func() {
+		cancelRequests()
+		cancel()
+	}
+}
+
+// DeadlineError returns an ExitCodeError with ExitCodeDeadlineExceeded if the command's context
+// expired because of its deadline, nil otherwise. Canceling the parent context (e.g., by an
+// interrupt) is not reported as a deadline.
+func DeadlineError(ctx context.Context) error {
+	if ctx == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil
+	}
+	return ExitCodeError{Code: ExitCodeDeadlineExceeded}
+}
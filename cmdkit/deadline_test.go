@@ -0,0 +1,54 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDeadlineExceeded(t *testing.T) {
+	ctx, cancel := WithDeadline(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Nil(t, DeadlineError(ctx))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("command was not stopped by its deadline")
+	}
+	var exitErr ExitCodeError
+	require.True(t, errors.As(DeadlineError(ctx), &exitErr))
+	assert.Equal(t, ExitCodeDeadlineExceeded, exitErr.Code)
+
+	// requests in progress are aborted as well
+	<-RequestContext(ctx).Done()
+	assert.ErrorIs(t, RequestContext(ctx).Err(), context.DeadlineExceeded)
+}
+
+func TestWithDeadlineInterrupted(t *testing.T) {
+	parent, interrupt := context.WithCancel(context.Background())
+	ctx, cancel := WithDeadline(parent, time.Hour)
+	defer cancel()
+	interrupt()
+
+	<-ctx.Done()
+	assert.Nil(t, DeadlineError(ctx), "interrupted command reported as if its deadline was exceeded")
+}
@@ -0,0 +1,40 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdkit
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// ExitCodeError is returned by a command that completed but reports its outcome with a
+// non-zero exit code (e.g., a status that is not successful). The command has already
+// displayed the outcome, so the error is not displayed again; main exits with the code.
+type ExitCodeError struct {
+	Code int
+}
+
+func (e ExitCodeError) Error() string {
+	return fmt.Sprintf("exit code %v", e.Code)
+}
+
+// ExitWithCode returns an ExitCodeError for the command, suppressing cobra's error and usage
+// messages for it. Use it as the return value of the command's RunE.
+func ExitWithCode(cmd *cobra.Command, code int) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return ExitCodeError{Code: code}
+}
@@ -15,6 +15,7 @@
 package cmdkit
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/apex/log"
@@ -65,6 +66,12 @@ func FetchAndPrint(cmd *cobra.Command, path string, options *FetchAndPrintOption
 		if method != "GET" {
 			log.Fatalf("bug: cannot request %q for a collection at %q, only GET is supported for collections", method, path)
 		}
+		if isJsonLinesOutput(cmd) {
+			// stream items as they arrive, without buffering the whole collection
+			httpOptions.ItemHandler = func(item any) error {
+				return output.PrintJsonLine(cmd, item)
+			}
+		}
 		err = api.JSONGetCollection(path, &res, httpOptions)
 	} else {
 		err = api.JSONRequest(method, path, body, &res, httpOptions)
@@ -73,6 +80,26 @@ func FetchAndPrint(cmd *cobra.Command, path string, options *FetchAndPrintOption
 		log.Fatalf("Platform API call failed: %v", err)
 	}
 
-	// print command output data
-	output.PrintCmdOutput(cmd, res)
+	// streamed items have already been displayed
+	if httpOptions != nil && httpOptions.ItemHandler != nil {
+		if httpOptions.CollectionTruncated {
+			log.Warnf("Results truncated to %v items; use --max-items to raise the limit", api.GetMaxCollectionItems())
+		}
+		return
+	}
+
+	// print command output data, noting if the collection was truncated
+	var table *output.Table
+	if httpOptions != nil && httpOptions.CollectionTruncated {
+		table = &output.Table{Footer: fmt.Sprintf("(results truncated to %v items; use --max-items to raise the limit)", api.GetMaxCollectionItems())}
+	}
+	output.PrintCmdOutputCustom(cmd, res, table)
+}
+
+func isJsonLinesOutput(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	format, _ := cmd.Flags().GetString("output")
+	return format == "jsonl"
 }
@@ -0,0 +1,102 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdkit
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+)
+
+// ExitCodeInterrupted is the exit code of a command stopped by an interrupt (128 + SIGINT, as in shells)
+const ExitCodeInterrupted = 130
+
+// requestContextKey is the key of the context for platform requests carried by a command's context
+type requestContextKey struct{}
+
+// WithInterrupt returns a context that is canceled when the process receives an interrupt (e.g., Ctrl-C)
+// or termination signal. Long-running commands check the context so that they stop dispatching new items,
+// finish the current one and display what was done; platform requests in progress are completed.
+// A second signal aborts the requests in progress (see RequestContext), so that the command stops
+// as soon as possible.
+func WithInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := withSignals(parent, signals)
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
+
+func withSignals(parent context.Context, signals <-chan os.Signal) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	requestCtx, cancelRequests := context.WithCancel(RequestContext(parent))
+
+	go func() {
+		select {
+		case <-signals:
+			log.Warnf("Interrupted; stopping after the current operation (interrupt again to abort it)")
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+		select {
+		case <-signals:
+			log.Warnf("Interrupted again; aborting the current operation")
+			cancelRequests()
+		case <-requestCtx.Done():
+		}
+	}()
+
+	return context.WithValue(ctx, requestContextKey{}, requestCtx), func() {
+		cancelRequests()
+		cancel()
+	}
+}
+
+// RequestContext returns the context for the platform requests of a command with the given context
+// (see api.SetContext). Unlike the command's context, it is not canceled by the first interrupt, so
+// that the current operation can complete; it is canceled by a second interrupt or by the deadline.
+func RequestContext(ctx context.Context) context.Context {
+	if requestCtx, ok := ctx.Value(requestContextKey{}).(context.Context); ok {
+		return requestCtx
+	}
+	return ctx
+}
+
+// Interrupted returns true if the command's context has been canceled, i.e., the command
+// should stop processing further items
+func Interrupted(cmd *cobra.Command) bool {
+	if cmd == nil || cmd.Context() == nil {
+		return false
+	}
+	return cmd.Context().Err() != nil
+}
+
+// InterruptedError returns an error exiting with ExitCodeInterrupted if the command's context has
+// been canceled, nil otherwise. Long-running commands return it after displaying the summary of the
+// work done before the interrupt.
+func InterruptedError(cmd *cobra.Command) error {
+	if !Interrupted(cmd) {
+		return nil
+	}
+	log.Warnf("Stopped before completion because of an interrupt")
+	return ExitWithCode(cmd, ExitCodeInterrupted)
+}
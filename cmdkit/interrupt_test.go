@@ -0,0 +1,60 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdkit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSignals(t *testing.T) {
+	signals := make(chan os.Signal, 2)
+	ctx, cancel := withSignals(context.Background(), signals)
+	defer cancel()
+	requestCtx := RequestContext(ctx)
+
+	// the first interrupt stops the command, but lets the current operation complete
+	signals <- os.Interrupt
+	<-ctx.Done()
+	assert.Nil(t, requestCtx.Err())
+
+	// the second interrupt aborts it
+	signals <- os.Interrupt
+	select {
+	case <-requestCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("requests were not aborted by the second interrupt")
+	}
+}
+
+func TestInterruptedError(t *testing.T) {
+	cmd := &cobra.Command{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd.SetContext(ctx)
+	assert.Nil(t, InterruptedError(cmd))
+
+	cancel()
+	var exitErr ExitCodeError
+	require.True(t, errors.As(InterruptedError(cmd), &exitErr))
+	assert.Equal(t, ExitCodeInterrupted, exitErr.Code)
+	assert.True(t, cmd.SilenceErrors)
+}
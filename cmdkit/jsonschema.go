@@ -0,0 +1,67 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdkit
+
+import (
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// SchemaViolation is a part of a document that does not conform to its JSON schema
+type SchemaViolation struct {
+	Pointer     string // JSON Pointer (RFC 6901) of the violation's location, "/" for the document root
+	Description string
+}
+
+func (v SchemaViolation) String() string {
+	return v.Pointer + ": " + v.Description
+}
+
+// ValidateJSONSchema validates the document against the schema and returns all violations;
+// pointerPrefix is the location of the document within its file, e.g., "/3" for the fourth
+// document of an array, or empty
+func ValidateJSONSchema(schemaLoader, documentLoader gojsonschema.JSONLoader, pointerPrefix string) ([]SchemaViolation, error) {
+	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := []SchemaViolation{}
+	for _, desc := range result.Errors() {
+		violations = append(violations, SchemaViolation{
+			Pointer:     JSONPointer(pointerPrefix, desc.Context()),
+			Description: desc.Description(),
+		})
+	}
+	return violations, nil
+}
+
+// JSONPointer converts the location of a validation error to a JSON Pointer, e.g., /data/config/port
+func JSONPointer(pointerPrefix string, context *gojsonschema.JsonContext) string {
+	pointer := pointerPrefix
+	if context != nil {
+		// the context is formatted as (root)/field/..., with unescaped field names
+		// (field names containing a slash cannot be told apart and appear as nested fields)
+		path := strings.Split(context.String("/"), "/")[1:]
+		for _, token := range path {
+			pointer += "/" + strings.ReplaceAll(token, "~", "~0")
+		}
+	}
+	if pointer == "" {
+		return "/" // the document root, made visible
+	}
+	return pointer
+}
@@ -0,0 +1,33 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdkit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestJSONPointer(t *testing.T) {
+	root := gojsonschema.NewJsonContext("(root)", nil)
+	port := gojsonschema.NewJsonContext("port", gojsonschema.NewJsonContext("config", gojsonschema.NewJsonContext("data", root)))
+
+	assert.Equal(t, "/data/config/port", JSONPointer("", port))
+	assert.Equal(t, "/3/data/config/port", JSONPointer("/3", port))
+	assert.Equal(t, "/", JSONPointer("", root))
+	assert.Equal(t, "/0", JSONPointer("/0", root))
+	assert.Equal(t, "/", JSONPointer("", nil))
+}
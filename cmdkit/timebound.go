@@ -0,0 +1,39 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdkit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/relvacode/iso8601"
+)
+
+// ParseTimeBound parses a time range boundary flag (e.g., --since), given either as a
+// duration counted back from now (e.g., 24h means 24 hours ago) or as an ISO 8601 timestamp
+// (which includes RFC 3339, e.g., 2023-05-10T12:00:00Z)
+func ParseTimeBound(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("duration %q cannot be negative", value)
+		}
+		return now.Add(-d), nil
+	}
+	t, err := iso8601.ParseString(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a duration (e.g., 24h) nor an ISO 8601 timestamp", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdkit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	bound, err := ParseTimeBound("90m", now)
+	require.Nil(t, err)
+	assert.Equal(t, now.Add(-90*time.Minute), bound)
+
+	bound, err = ParseTimeBound("2023-05-01T02:00:00+02:00", now)
+	require.Nil(t, err)
+	assert.True(t, bound.Equal(time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = ParseTimeBound("-1h", now)
+	assert.ErrorContains(t, err, "cannot be negative")
+
+	_, err = ParseTimeBound("yesterday", now)
+	assert.ErrorContains(t, err, "neither a duration")
+}
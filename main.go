@@ -16,12 +16,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"github.com/apex/log"
 	"github.com/apex/log/handlers/cli"
 
 	"github.com/cisco-open/fsoc/cmd"
+	"github.com/cisco-open/fsoc/cmdkit"
 )
 
 func main() {
@@ -29,11 +31,20 @@ func main() {
 }
 
 func realMain() int {
-	ctx := context.Background()
+	ctx, cancel := cmdkit.WithInterrupt(context.Background())
+	defer cancel()
 
 	log.SetHandler(cli.New(os.Stderr))
 
 	if err := cmd.Execute(ctx); err != nil {
+		var exitErr cmdkit.ExitCodeError
+		if errors.As(err, &exitErr) {
+			return exitErr.Code
+		}
+		if errors.Is(err, context.Canceled) {
+			log.Warnf("Stopped before completion because of an interrupt")
+			return cmdkit.ExitCodeInterrupted
+		}
 		log.WithFields(log.Fields{"error": err}).Error("command failed")
 		return 1
 	}
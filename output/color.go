@@ -0,0 +1,46 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// ANSI terminal colors for use with Colorize
+const (
+	ColorRed   = "\033[31m"
+	ColorGreen = "\033[32m"
+	colorReset = "\033[0m"
+)
+
+// ColorEnabled reports whether the command output can be colored, i.e., the output
+// is an interactive terminal and the NO_COLOR environment variable is not set
+// (see https://no-color.org)
+func ColorEnabled(cmd *cobra.Command) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if GetOutWriter(cmd) != os.Stdout {
+		return false // output is captured
+	}
+	return stdoutIsTerminal()
+}
+
+// Colorize wraps the string in the given color's escape sequences
+func Colorize(color string, s string) string {
+	return color + s + colorReset
+}
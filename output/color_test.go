@@ -0,0 +1,45 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorEnabled(t *testing.T) {
+	savedIsTerminal := stdoutIsTerminal
+	t.Cleanup(func() { stdoutIsTerminal = savedIsTerminal })
+	stdoutIsTerminal = func() bool { return true }
+
+	cmd := &cobra.Command{}
+	t.Setenv("NO_COLOR", "")
+	require.True(t, ColorEnabled(cmd))
+
+	t.Setenv("NO_COLOR", "1")
+	require.False(t, ColorEnabled(cmd))
+
+	// captured output is never colored
+	t.Setenv("NO_COLOR", "")
+	cmd.SetOut(&bytes.Buffer{})
+	require.False(t, ColorEnabled(cmd))
+
+	// nor is output that is not a terminal
+	stdoutIsTerminal = func() bool { return false }
+	require.False(t, ColorEnabled(&cobra.Command{}))
+}
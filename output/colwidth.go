@@ -0,0 +1,73 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// MaxColWidthFlag is the name of the flag that limits the width of table columns
+const MaxColWidthFlag = "max-col-width"
+
+const (
+	minAutoColWidth = 10 // narrowest column when fitting a table to the terminal
+	colPadding      = 3  // space between table columns
+)
+
+// stdoutIsTerminal reports whether the standard output is an interactive terminal.
+// It is a variable so that it can be replaced in tests.
+var stdoutIsTerminal = func() bool {
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalWidth returns the width of the terminal in characters, or 0 if unknown.
+// The COLUMNS environment variable, if set, takes precedence.
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return stdoutTerminalWidth()
+}
+
+// maxColumnWidth returns the width at which the cells of a table with the given number
+// of columns should wrap, or 0 for no wrapping. The width specified with the
+// --max-col-width flag takes precedence; otherwise, cells wrap to fit the table within
+// the terminal's width and don't wrap if the output is not a terminal.
+func maxColumnWidth(cmd *cobra.Command, columns int) int {
+	if cmd != nil {
+		if width, err := cmd.Flags().GetInt(MaxColWidthFlag); err == nil && width > 0 {
+			return width
+		}
+	}
+	if !stdoutIsTerminal() || columns <= 0 {
+		return 0
+	}
+	width := terminalWidth()
+	if width <= 0 {
+		return 0
+	}
+	colWidth := width/columns - colPadding
+	if colWidth < minAutoColWidth {
+		colWidth = minAutoColWidth
+	}
+	return colWidth
+}
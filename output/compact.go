@@ -0,0 +1,60 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// CompactFlag is the name of the flag that displays tables as tab-separated rows without padding
+// and JSON without indentation
+const CompactFlag = "compact"
+
+// compactCellReplacer keeps each cell on a single line without tabs, so that rows stay intact
+var compactCellReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+
+// compact returns true if the command's --compact flag is set
+func compact(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	v, _ := cmd.Flags().GetBool(CompactFlag)
+	return v
+}
+
+// printCompactTable prints a table as rows of tab-separated cells, without padding,
+// borders or wrapping (e.g., for pasting into tickets or spreadsheets)
+func printCompactTable(cmd *cobra.Command, t *Table) {
+	printRow := func(cells []string) {
+		values := make([]string, len(cells))
+		for i, cell := range cells {
+			values[i] = compactCellReplacer.Replace(cell)
+		}
+		println(cmd, strings.Join(values, "\t"))
+	}
+
+	if !noHeaders(cmd) {
+		headers := make([]string, len(t.Headers))
+		for i, header := range t.Headers {
+			headers[i] = strings.ToUpper(header) // same as the padded table
+		}
+		printRow(headers)
+	}
+	for _, line := range t.Lines {
+		printRow(line)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfirmFlag is the name of the flag used to skip confirmation prompts
+const ConfirmFlag = "yes"
+
+// ErrInputRequired is returned when a command would prompt for input while prompting is disabled
+var ErrInputRequired = errors.New("input required but --no-input set; pass --yes or provide flags")
+
+// ErrNotInteractive is returned when a command would ask for confirmation but the input is not interactive
+var ErrNotInteractive = errors.New("cannot ask for confirmation when not running interactively; pass --yes to proceed")
+
+// noInput disables all interactive prompts
+var noInput bool
+
+// SetNoInput disables interactive prompts, making them fail with ErrInputRequired
+// instead of waiting for input
+func SetNoInput(enabled bool) {
+	noInput = enabled
+}
+
+// CheckInputAllowed returns ErrInputRequired if interactive prompts are disabled
+func CheckInputAllowed() error {
+	if noInput {
+		return ErrInputRequired
+	}
+	return nil
+}
+
+// inputIsTerminal reports whether the input is an interactive terminal; only a file (e.g.,
+// os.Stdin) can be one. It is a variable so that it can be replaced in tests.
+var inputIsTerminal = func(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// AddConfirmFlag adds the --yes flag to a command that asks for confirmation
+// before performing a destructive operation
+func AddConfirmFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP(ConfirmFlag, "y", false, "Skip the confirmation prompt and proceed (required when not running interactively)")
+}
+
+// Confirm asks the user to confirm an operation, returning true if the user agreed.
+// If the command's --yes flag is set, Confirm returns true without prompting.
+// If the input is not interactive or prompts are disabled with --no-input, Confirm
+// returns an error (ErrNotInteractive or ErrInputRequired) unless --yes is set, so that
+// the command fails instead of appearing to succeed without doing anything.
+func Confirm(cmd *cobra.Command, message string) (bool, error) {
+	if cmd != nil {
+		if yes, _ := cmd.Flags().GetBool(ConfirmFlag); yes {
+			return true, nil
+		}
+	}
+
+	if err := CheckInputAllowed(); err != nil {
+		return false, err
+	}
+
+	var in io.Reader = os.Stdin
+	if cmd != nil {
+		in = cmd.InOrStdin()
+	}
+	if !inputIsTerminal(in) {
+		return false, ErrNotInteractive
+	}
+	return confirm(cmd, in, message), nil
+}
+
+func confirm(cmd *cobra.Command, in io.Reader, message string) bool {
+	printf(cmd, "%v [y/N]: ", message)
+
+	reader := bufio.NewReader(in)
+	answer, err := reader.ReadString('\n')
+	if err != nil && answer == "" {
+		println(cmd)
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
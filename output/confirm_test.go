@@ -0,0 +1,115 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/test"
+)
+
+func TestConfirmAnswers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{input: "y\n", expected: true},
+		{input: "YES\n", expected: true},
+		{input: " yes \n", expected: true},
+		{input: "n\n", expected: false},
+		{input: "\n", expected: false},
+		{input: "", expected: false},
+		{input: "maybe\n", expected: false},
+	}
+
+	for _, tt := range tests {
+		var result bool
+		out := test.CaptureConsoleOutput(func() {
+			result = confirm(nil, strings.NewReader(tt.input), "Delete it?")
+		}, t)
+		require.Equal(t, tt.expected, result, "input %q", tt.input)
+		require.True(t, strings.HasPrefix(out, "Delete it? [y/N]: "))
+	}
+}
+
+func TestConfirmNonInteractive(t *testing.T) {
+	saved := inputIsTerminal
+	defer func() { inputIsTerminal = saved }()
+	inputIsTerminal = func(io.Reader) bool { return false }
+
+	cmd := &cobra.Command{}
+	AddConfirmFlag(cmd)
+
+	// fails without --yes
+	ok, err := Confirm(cmd, "Delete it?")
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrNotInteractive)
+	require.ErrorContains(t, err, "--yes")
+
+	// proceeds with --yes
+	require.Nil(t, cmd.Flags().Set(ConfirmFlag, "true"))
+	ok, err = Confirm(cmd, "Delete it?")
+	require.Nil(t, err)
+	require.True(t, ok)
+}
+
+func TestConfirmNoInput(t *testing.T) {
+	savedTerminal := inputIsTerminal
+	defer func() { inputIsTerminal = savedTerminal }()
+	inputIsTerminal = func(io.Reader) bool { return true }
+	SetNoInput(true)
+	defer SetNoInput(false)
+
+	cmd := &cobra.Command{}
+	AddConfirmFlag(cmd)
+	cmd.SetIn(strings.NewReader("y\n"))
+
+	// fails fast even though an answer is available
+	require.Equal(t, ErrInputRequired, CheckInputAllowed())
+	ok, err := Confirm(cmd, "Delete it?")
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrInputRequired)
+
+	// --yes still proceeds
+	require.Nil(t, cmd.Flags().Set(ConfirmFlag, "true"))
+	ok, err = Confirm(cmd, "Delete it?")
+	require.Nil(t, err)
+	require.True(t, ok)
+}
+
+func TestConfirmChecksCommandInput(t *testing.T) {
+	cmd := &cobra.Command{}
+	AddConfirmFlag(cmd)
+
+	// the command's input is not a terminal, even if the standard input is
+	cmd.SetIn(strings.NewReader("y\n"))
+	ok, err := Confirm(cmd, "Delete it?")
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrNotInteractive)
+
+	saved := inputIsTerminal
+	defer func() { inputIsTerminal = saved }()
+	in := strings.NewReader("y\n")
+	inputIsTerminal = func(r io.Reader) bool { return r == in }
+	cmd.SetIn(in)
+	ok, err = Confirm(cmd, "Delete it?")
+	require.Nil(t, err)
+	require.True(t, ok)
+}
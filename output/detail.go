@@ -0,0 +1,127 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// detailTable creates a detail table from a single object or from a collection's items, with
+// the fields of nested objects flattened into rows with dotted labels (e.g., "data.color").
+// Returns false if the value is not an object or a collection of objects.
+func detailTable(v any) (*Table, bool) {
+	var generic any
+	data, err := json.Marshal(v)
+	if err != nil || json.Unmarshal(data, &generic) != nil {
+		return nil, false
+	}
+	obj, ok := generic.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	entries := []any{obj}
+	if items, isCollection := obj["items"].([]any); isCollection {
+		entries = items
+	}
+
+	// collect the labels of all entries, in the order of their first appearance
+	table := &Table{Headers: []string{}, Lines: [][]string{}, Detail: true}
+	columns := map[string]int{}
+	rows := make([]map[string]string, 0, len(entries))
+	for _, entry := range entries {
+		labels, values := []string{}, []string{}
+		flattenDetailValue("", entry, &labels, &values)
+		row := map[string]string{}
+		for i, label := range labels {
+			if _, found := columns[label]; !found {
+				columns[label] = len(table.Headers)
+				table.Headers = append(table.Headers, label)
+			}
+			row[label] = values[i]
+		}
+		rows = append(rows, row)
+	}
+	for _, row := range rows {
+		line := make([]string, len(table.Headers))
+		for label, value := range row {
+			line[columns[label]] = value
+		}
+		table.Lines = append(table.Lines, line)
+	}
+	return table, true
+}
+
+// expandDetailRows returns the labels and values of an entry of the detail output, with
+// the values that are JSON objects (e.g., nested data converted to string) flattened into
+// rows with dotted labels
+func expandDetailRows(headers []string, entry []string) ([]string, []string) {
+	labels, values := []string{}, []string{}
+	for i, header := range headers {
+		value := ""
+		if i < len(entry) {
+			value = entry[i]
+		}
+		var nested map[string]any
+		if strings.HasPrefix(strings.TrimSpace(value), "{") && json.Unmarshal([]byte(value), &nested) == nil && len(nested) > 0 {
+			flattenDetailValue(header, nested, &labels, &values)
+			continue
+		}
+		labels = append(labels, header)
+		values = append(values, value)
+	}
+	return labels, values
+}
+
+// flattenDetailValue appends the label and value rows of a value, descending into non-empty
+// objects (sorted by key); other values are displayed as strings or compact JSON
+func flattenDetailValue(label string, value any, labels *[]string, values *[]string) {
+	if obj, ok := value.(map[string]any); ok && len(obj) > 0 {
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			nestedLabel := key
+			if label != "" {
+				nestedLabel = label + "." + key
+			}
+			flattenDetailValue(nestedLabel, obj[key], labels, values)
+		}
+		return
+	}
+	if label == "" {
+		return // nb: an empty top-level object has no rows
+	}
+
+	var s string
+	switch v := value.(type) {
+	case nil:
+		s = ""
+	case string:
+		s = v
+	default:
+		if data, err := json.Marshal(v); err == nil {
+			s = string(data)
+		} else {
+			s = fmt.Sprintf("%v", v)
+		}
+	}
+	*labels = append(*labels, label)
+	*values = append(*values, s)
+}
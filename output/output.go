@@ -37,6 +37,10 @@ const (
 
 	// DetailFieldsAnnotation is the name of the cobra.Command annotation to use to specify the fields JQ query for detail output
 	DetailFieldsAnnotation = "output/detailFields"
+
+	// WideFieldsAnnotation is the name of the cobra.Command annotation to use to specify the fields JQ query for wide
+	// table output, which adds columns that are omitted from the table output for brevity
+	WideFieldsAnnotation = "output/wideFields"
 )
 
 type printRequest struct {
@@ -44,6 +48,7 @@ type printRequest struct {
 	format      string
 	fields      string
 	annotations map[string]string
+	selection   string // jq expression to apply to the data instead of the fields and table, if not empty
 }
 
 func print(cmd *cobra.Command, a ...any) {
@@ -78,9 +83,48 @@ func GetOutWriter(cmd *cobra.Command) io.Writer {
 	}
 }
 
-// PrintJson displays the output in prettified JSON
+// PrettyFlag is the name of the flag that forces indented JSON output; see also CompactFlag
+const PrettyFlag = "pretty"
+
+// prettyJSON returns true if JSON output should be indented: as selected with --pretty or
+// --compact (--pretty takes precedence) or, if neither is specified, the default
+func prettyJSON(cmd *cobra.Command, defaultPretty bool) bool {
+	if cmd != nil {
+		if cmd.Flags().Changed(PrettyFlag) {
+			v, _ := cmd.Flags().GetBool(PrettyFlag)
+			return v
+		}
+		if compact(cmd) {
+			return false
+		}
+	}
+	return defaultPretty
+}
+
+// marshalJSON converts the value to JSON, indented or compact
+func marshalJSON(v any, pretty bool) ([]byte, error) {
+	if pretty {
+		return json.MarshalIndent(v, "", "   ")
+	}
+	return json.Marshal(v)
+}
+
+// PrintJson displays the output in JSON, indented when displayed on a terminal and compact
+// otherwise (e.g., when piped), unless selected with --pretty or --compact
 func PrintJson(cmd *cobra.Command, v any) error {
-	data, err := json.MarshalIndent(v, "", "   ")
+	data, err := marshalJSON(v, prettyJSON(cmd, IsTerminal(cmd)))
+	if err != nil {
+		return err
+	}
+
+	println(cmd, string(data))
+	return nil
+}
+
+// PrintJsonLine displays the output as compact JSON on a single line, unless
+// indentation is explicitly requested with --pretty
+func PrintJsonLine(cmd *cobra.Command, v any) error {
+	data, err := marshalJSON(v, prettyJSON(cmd, false))
 	if err != nil {
 		return err
 	}
@@ -89,6 +133,22 @@ func PrintJson(cmd *cobra.Command, v any) error {
 	return nil
 }
 
+// PrintJsonLines displays the output in JSON lines format: each item of
+// a collection (or the single object) as compact JSON on its own line
+func PrintJsonLines(cmd *cobra.Command, v any) error {
+	data, ok := canonicalizeData(v).(map[string]any)
+	if !ok {
+		return PrintJsonLine(cmd, v)
+	}
+	items, _ := data["items"].([]any)
+	for _, item := range items {
+		if err := PrintJsonLine(cmd, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // PrintYaml displays the output in YAML
 func PrintYaml(cmd *cobra.Command, v any) error {
 	data, err := yaml.Marshal(v)
@@ -107,6 +167,21 @@ func PrintCmdStatus(cmd *cobra.Command, s string) {
 	print(cmd, s)
 }
 
+// Formats lists the values of the --output flag
+var Formats = []string{"auto", "table", "wide", "detail", "json", "jsonl", "yaml"}
+
+// NoHeadersFlag is the name of the flag that omits table headers and footers, e.g., for scripting
+const NoHeadersFlag = "no-headers"
+
+// noHeaders returns true if the command's --no-headers flag is set
+func noHeaders(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	v, _ := cmd.Flags().GetBool(NoHeadersFlag)
+	return v
+}
+
 type Table struct {
 	// table output
 	Headers []string
@@ -115,6 +190,10 @@ type Table struct {
 
 	// extract field columns in the same order as headers
 	LineBuilder func(v any) []string // use together with Headers and no Lines
+
+	// text to display after the table or detail output (not displayed for json, jsonl and yaml
+	// or with --no-headers)
+	Footer string
 }
 
 // PrintCmdOutput displays the output of a command in the user-selected output format. If
@@ -145,11 +224,26 @@ func PrintCmdOutputCustom(cmd *cobra.Command, v any, table *Table) {
 	//        - for human outputs only, get the fields spec from the command annotations (if set)
 	//        - for machine formats, don't filter by fields
 	fields, _ := cmd.Flags().GetString("fields") // since --fields doesn't have default, non-empty means explicitly set
-	pr := printRequest{cmd: cmd, format: format, fields: fields, annotations: cmd.Annotations}
+	pr := printRequest{cmd: cmd, format: format, fields: fields, annotations: cmd.Annotations, selection: getSelectExpression(cmd)}
 	printCmdOutputCustom(pr, v, table)
 }
 
 func printCmdOutputCustom(pr printRequest, v any, table *Table) {
+	// extract or reshape the data, if requested, bypassing the fields and the custom table
+	if pr.selection != "" {
+		selected, err := selectData(v, pr.selection)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		printSelected(pr, selected)
+		return
+	}
+
+	footer := ""
+	if table != nil && !noHeaders(pr.cmd) {
+		footer = table.Footer
+	}
+
 	// if no field spec is given on the command line and built-in specs are available, use them
 	if pr.fields == "" && pr.annotations != nil {
 		// choose which annotations to use and in what priority order
@@ -157,6 +251,8 @@ func printCmdOutputCustom(pr printRequest, v any, table *Table) {
 		switch pr.format {
 		case "", "auto", "table":
 			annotations = []string{TableFieldsAnnotation, DetailFieldsAnnotation}
+		case "wide":
+			annotations = []string{WideFieldsAnnotation, TableFieldsAnnotation}
 		case "detail":
 			annotations = []string{DetailFieldsAnnotation, TableFieldsAnnotation}
 			// all others, keep empty list
@@ -197,6 +293,11 @@ func printCmdOutputCustom(pr printRequest, v any, table *Table) {
 			log.Fatalf("Failed to convert output to YAML: %v (%+v)", err, v)
 		}
 		return
+	case "jsonl":
+		if err := PrintJsonLines(pr.cmd, v); err != nil {
+			log.Fatalf("Failed to convert output to JSON lines: %v (%+v)", err, v)
+		}
+		return
 	}
 
 	// display simple values
@@ -213,6 +314,13 @@ func printCmdOutputCustom(pr printRequest, v any, table *Table) {
 		}
 	}
 
+	// display objects field by field in the detail format, if there is no custom table
+	if pr.format == "detail" && pr.fields == "" && (table == nil || len(table.Headers) == 0) {
+		if t, ok := detailTable(v); ok {
+			table = t
+		}
+	}
+
 	// format table if a transform is provided or there is no custom table
 	if pr.fields != "" || table == nil || len(table.Headers) == 0 {
 		var err error
@@ -226,12 +334,20 @@ func printCmdOutputCustom(pr printRequest, v any, table *Table) {
 		}
 	}
 
+	// display timestamps in the selected timezone or relative to now
+	table = formatTimestamps(pr.cmd, table)
+
 	// display table
 	if table.Detail || pr.format == "detail" {
 		printDetail(pr.cmd, table)
 	} else {
 		printTable(pr.cmd, table)
 	}
+
+	// display footer
+	if footer != "" {
+		println(pr.cmd, footer)
+	}
 }
 
 func buildLines(in any, builderFunc func(any) []string) ([][]string, bool) {
@@ -267,12 +383,24 @@ func printTable(cmd *cobra.Command, t *Table) {
 		printSimple(cmd, "Nothing to display")
 		return
 	}
+	if compact(cmd) {
+		printCompactTable(cmd, t)
+		return
+	}
 	tw := tablewriter.NewWriter(GetOutWriter(cmd))
 	tw.SetBorder(false)
 	tw.SetCenterSeparator("")
 	tw.SetColumnSeparator("")
 	tw.SetRowSeparator("")
-	tw.SetHeader(t.Headers)
+	if !noHeaders(cmd) {
+		tw.SetHeader(t.Headers)
+	}
+	if width := maxColumnWidth(cmd, len(t.Headers)); width > 0 {
+		tw.SetAutoWrapText(true)
+		tw.SetColWidth(width)
+	} else {
+		tw.SetAutoWrapText(false)
+	}
 	tw.AppendBulk(t.Lines)
 	tw.Render()
 }
@@ -287,18 +415,38 @@ func printDetail(cmd *cobra.Command, t *Table) {
 		return
 	}
 
-	// determine header max. width
+	// flatten nested objects into rows with dotted labels and determine the labels' max. width
 	labelWidth := 0
-	for _, v := range t.Headers {
-		if l := len(v); l > labelWidth {
-			labelWidth = l
+	type detailEntry struct{ labels, values []string }
+	entries := make([]detailEntry, len(t.Lines))
+	for i, line := range t.Lines {
+		labels, values := expandDetailRows(t.Headers, line)
+		entries[i] = detailEntry{labels, values}
+		for _, label := range labels {
+			if l := len(label); l > labelWidth {
+				labelWidth = l
+			}
 		}
 	}
 
-	// display first row as entries
-	for _, entry := range t.Lines {
-		for i := range t.Headers {
-			printf(cmd, "%[1]*[2]s: %[3]v\n", labelWidth, t.Headers[i], entry[i])
+	// values wrap within the remaining width, if limited
+	valueWidth := maxColumnWidth(cmd, 1)
+	if valueWidth > 0 && (cmd == nil || !cmd.Flags().Changed(MaxColWidthFlag)) {
+		valueWidth -= labelWidth + 2 - colPadding // fit label and value within the terminal
+		if valueWidth < minAutoColWidth {
+			valueWidth = minAutoColWidth
+		}
+	}
+
+	// display each row as entries
+	for _, entry := range entries {
+		labels, values := entry.labels, entry.values
+		for i := range labels {
+			lines := []string{values[i]}
+			if valueWidth > 0 {
+				lines, _ = tablewriter.WrapString(values[i], valueWidth)
+			}
+			printf(cmd, "%[1]*[2]s: %[3]v\n", labelWidth, labels[i], strings.Join(lines, "\n"+strings.Repeat(" ", labelWidth+2)))
 			//TODO: add support for multi-line values, see Jira ticket FSOC-23
 		}
 		println(cmd)
@@ -15,10 +15,13 @@
 package output
 
 import (
+	"bytes"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 
 	"github.com/cisco-open/fsoc/test"
@@ -31,6 +34,10 @@ type testStruct struct {
 }
 
 func TestPrintJSONAndYaml(t *testing.T) {
+	// json is indented on a terminal
+	savedIsTerminal := stdoutIsTerminal
+	t.Cleanup(func() { stdoutIsTerminal = savedIsTerminal })
+	stdoutIsTerminal = func() bool { return true }
 
 	obj := testStruct{
 		Field1: "hello",
@@ -87,3 +94,176 @@ func TestPrintTable(t *testing.T) {
 	outActual := test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, nil, table) }, t)
 	require.Equal(t, outExpected, outActual)
 }
+
+func TestPrintTableFooter(t *testing.T) {
+	pr := printRequest{format: ""}
+
+	table := &Table{
+		Headers: []string{"Field1"},
+		Lines:   [][]string{{"Row1-Field1"}},
+		Footer:  "(results truncated)",
+	}
+	outActual := test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, nil, table) }, t)
+	require.True(t, strings.HasSuffix(outActual, "(results truncated)\n"))
+
+	// footer is not displayed in machine-readable formats
+	pr = printRequest{format: "json"}
+	outActual = test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, map[string]any{"a": 1}, table) }, t)
+	require.NotContains(t, outActual, "(results truncated)")
+}
+
+func TestPrintJsonLines(t *testing.T) {
+	pr := printRequest{format: "jsonl"}
+
+	// each collection item on its own line
+	collection := map[string]any{
+		"items": []any{map[string]any{"id": "a"}, map[string]any{"id": "b"}},
+		"total": 2,
+	}
+	outActual := test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, collection, nil) }, t)
+	require.Equal(t, "{\"id\":\"a\"}\n{\"id\":\"b\"}\n", outActual)
+
+	// single object on one line
+	obj := testStruct{Field1: "hello", Field2: 100, Field3: true}
+	outActual = test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, obj, nil) }, t)
+	require.Equal(t, "{\"Field1\":\"hello\",\"Field2\":100,\"Field3\":true}\n", outActual)
+}
+
+func TestPrintTableMaxColWidth(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Int(MaxColWidthFlag, 0, "")
+	require.Nil(t, cmd.Flags().Set(MaxColWidthFlag, "10"))
+	cmd.SetOut(&bytes.Buffer{})
+	out := cmd.OutOrStdout().(*bytes.Buffer)
+
+	pr := printRequest{cmd: cmd, format: "detail"}
+	table := &Table{
+		Headers: []string{"Message"},
+		Lines:   [][]string{{"dependency foo is not installed"}},
+	}
+	printCmdOutputCustom(pr, nil, table)
+	require.Equal(t, "Message: dependency\n         foo is not\n         installed\n\n", out.String())
+}
+
+func TestPrintTableNoWrapWhenNotTerminal(t *testing.T) {
+	pr := printRequest{format: "detail"}
+	table := &Table{
+		Headers: []string{"Message"},
+		Lines:   [][]string{{"a long message that would be wrapped on a terminal but not when redirected"}},
+	}
+	outActual := test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, nil, table) }, t)
+	require.Equal(t, "Message: a long message that would be wrapped on a terminal but not when redirected\n\n", outActual)
+}
+
+func TestPrintWideFields(t *testing.T) {
+	annotations := map[string]string{
+		TableFieldsAnnotation: "name:.name",
+		WideFieldsAnnotation:  "name:.name, id:.id",
+	}
+	v := map[string]any{"items": []any{map[string]any{"name": "mysolution", "id": "abc-123"}}, "total": 1}
+
+	// the table output omits the wide columns
+	pr := printRequest{format: "table", annotations: annotations}
+	outActual := test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, v, nil) }, t)
+	require.Contains(t, outActual, "mysolution")
+	require.NotContains(t, outActual, "abc-123")
+
+	// the wide output includes them
+	pr = printRequest{format: "wide", annotations: annotations}
+	outActual = test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, v, nil) }, t)
+	require.Contains(t, outActual, "mysolution")
+	require.Contains(t, outActual, "abc-123")
+
+	// commands without wide columns display their table columns
+	pr = printRequest{format: "wide", annotations: map[string]string{TableFieldsAnnotation: "name:.name"}}
+	outActual = test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, v, nil) }, t)
+	require.Contains(t, outActual, "mysolution")
+	require.NotContains(t, outActual, "abc-123")
+}
+
+func TestPrintTableNoHeaders(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool(NoHeadersFlag, false, "")
+	require.Nil(t, cmd.Flags().Set(NoHeadersFlag, "true"))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	table := &Table{
+		Headers: []string{"Field1"},
+		Lines:   [][]string{{"Row1-Field1"}},
+		Footer:  "1 object",
+	}
+	printCmdOutputCustom(printRequest{cmd: cmd, format: "table"}, nil, table)
+	require.Contains(t, out.String(), "Row1-Field1")
+	require.NotContains(t, out.String(), "FIELD1")
+	require.NotContains(t, out.String(), "1 object")
+}
+
+func TestPrintTableCompact(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool(CompactFlag, false, "")
+	cmd.Flags().Bool(NoHeadersFlag, false, "")
+	require.Nil(t, cmd.Flags().Set(CompactFlag, "true"))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	table := &Table{
+		Headers: []string{"Name", "Value"},
+		Lines:   [][]string{{"first", "1"}, {"second", "two\nlines"}},
+		Footer:  "2 objects",
+	}
+	printCmdOutputCustom(printRequest{cmd: cmd, format: "table"}, nil, table)
+	require.Equal(t, "NAME\tVALUE\nfirst\t1\nsecond\ttwo lines\n2 objects\n", out.String())
+
+	// without headers and footer
+	out.Reset()
+	require.Nil(t, cmd.Flags().Set(NoHeadersFlag, "true"))
+	printCmdOutputCustom(printRequest{cmd: cmd, format: "table"}, nil, table)
+	require.Equal(t, "first\t1\nsecond\ttwo lines\n", out.String())
+}
+
+func TestPrintJsonIndentation(t *testing.T) {
+	obj := map[string]any{"id": "a"}
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool(PrettyFlag, false, "")
+	cmd.Flags().Bool(CompactFlag, false, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	// not a terminal: compact by default
+	require.Nil(t, PrintJson(cmd, obj))
+	require.Equal(t, "{\"id\":\"a\"}\n", out.String())
+
+	out.Reset()
+	require.Nil(t, cmd.Flags().Set(PrettyFlag, "true"))
+	require.Nil(t, PrintJson(cmd, obj))
+	require.Equal(t, "{\n   \"id\": \"a\"\n}\n", out.String())
+
+	// jsonl is indented only on request
+	out.Reset()
+	require.Nil(t, PrintJsonLine(cmd, obj))
+	require.Equal(t, "{\n   \"id\": \"a\"\n}\n", out.String())
+
+	out.Reset()
+	require.Nil(t, cmd.Flags().Set(PrettyFlag, "false"))
+	require.Nil(t, PrintJsonLine(cmd, obj))
+	require.Equal(t, "{\"id\":\"a\"}\n", out.String())
+}
+
+func TestPrintDetailNested(t *testing.T) {
+	pr := printRequest{format: "detail"}
+	obj := map[string]any{
+		"id":   "mytheme",
+		"data": map[string]any{"color": "green", "font": map[string]any{"size": 12}, "tags": []any{"a", "b"}},
+	}
+	outActual := test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, obj, nil) }, t)
+	require.Equal(t, "    data.color: green\ndata.font.size: 12\n     data.tags: [\"a\",\"b\"]\n            id: mytheme\n\n", outActual)
+
+	// nested values converted to JSON strings in a custom table are flattened too
+	table := &Table{
+		Headers: []string{"Name", "Data"},
+		Lines:   [][]string{{"mytheme", `{"color":"green","size":12}`}},
+	}
+	outActual = test.CaptureConsoleOutput(func() { printCmdOutputCustom(pr, nil, table) }, t)
+	require.Equal(t, "      Name: mytheme\nData.color: green\n Data.size: 12\n\n", outActual)
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// clearScreenSequence moves the cursor to the top left corner and clears the terminal
+const clearScreenSequence = "\033[H\033[2J"
+
+// IsTerminal returns true if the command's output goes to an interactive terminal,
+// e.g., to decide whether the output can be redrawn in place
+func IsTerminal(cmd *cobra.Command) bool {
+	if cmd != nil && GetOutWriter(cmd) != os.Stdout {
+		return false
+	}
+	return stdoutIsTerminal()
+}
+
+// ClearScreen clears the terminal, so that the output can be redrawn in place.
+// Use it only if IsTerminal returns true.
+func ClearScreen(cmd *cobra.Command) {
+	print(cmd, clearScreenSequence)
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/itchyny/gojq"
+	"github.com/spf13/cobra"
+)
+
+// SelectFlag is the name of the flag that specifies a jq expression to extract or reshape the output data
+const SelectFlag = "select"
+
+// getSelectExpression returns the value of the command's --select flag, if any
+func getSelectExpression(cmd *cobra.Command) string {
+	if cmd == nil {
+		return ""
+	}
+	v, _ := cmd.Flags().GetString(SelectFlag)
+	return v
+}
+
+// selectData applies the jq expression to the data, as it would be displayed in json. If the
+// expression produces a single value, that value is returned; otherwise, a list of the values.
+func selectData(v any, expression string) (any, error) {
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --select expression %q: %v%v", expression, err, jmesPathHint(expression))
+	}
+
+	// convert to generic JSON values, as jq expects
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the data for --select: %v", err)
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to prepare the data for --select: %v", err)
+	}
+
+	results := []any{}
+	iter := query.Run(generic)
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := result.(error); ok {
+			return nil, fmt.Errorf("--select expression %q failed: %v%v", expression, err, jmesPathHint(expression))
+		}
+		results = append(results, result)
+	}
+	if len(results) == 1 {
+		return results[0], nil
+	}
+	return results, nil
+}
+
+// jmesPathHint returns a hint for an expression that looks like JMESPath (e.g., "items[0].id" or
+// "items[?data.isSuccessful]") rather than jq, or an empty string
+func jmesPathHint(expression string) string {
+	expression = strings.TrimSpace(expression)
+	if !strings.Contains(expression, "[?") && (expression == "" || !isIdentifierStart(expression[0])) {
+		return ""
+	}
+	return "; --select takes a jq expression, not JMESPath, e.g., '[.items[] | select(.data.isSuccessful) | .data.solutionName]'"
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// printSelected displays the result of a --select expression. The json, jsonl and yaml formats
+// are honored; otherwise, simple values are displayed as is, one per line, and other values as yaml.
+func printSelected(pr printRequest, v any) {
+	switch pr.format {
+	case "json":
+		if err := PrintJson(pr.cmd, v); err != nil {
+			log.Fatalf("Failed to convert output to JSON: %v (%+v)", err, v)
+		}
+		return
+	case "jsonl":
+		values, ok := v.([]any)
+		if !ok {
+			values = []any{v}
+		}
+		for _, value := range values {
+			if err := PrintJsonLine(pr.cmd, value); err != nil {
+				log.Fatalf("Failed to convert output to JSON lines: %v (%+v)", err, v)
+			}
+		}
+		return
+	case "yaml":
+		if err := PrintYaml(pr.cmd, v); err != nil {
+			log.Fatalf("Failed to convert output to YAML: %v (%+v)", err, v)
+		}
+		return
+	}
+
+	values, ok := v.([]any)
+	if !ok {
+		values = []any{v}
+	}
+	for _, value := range values {
+		if !isSimpleValue(value) {
+			if err := PrintYaml(pr.cmd, v); err != nil {
+				log.Fatalf("Failed to convert output to YAML: %v (%+v)", err, v)
+			}
+			return
+		}
+	}
+	for _, value := range values {
+		if value != nil {
+			printSimple(pr.cmd, value)
+		}
+	}
+}
+
+// isSimpleValue returns true for values that can be displayed on a single line
+func isSimpleValue(v any) bool {
+	switch v.(type) {
+	case nil, string, bool, float64, int:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var selectTestData = map[string]any{
+	"items": []any{
+		map[string]any{"id": "a", "data": map[string]any{"isSuccessful": true, "solutionName": "first"}},
+		map[string]any{"id": "b", "data": map[string]any{"isSuccessful": false, "solutionName": "second"}},
+		map[string]any{"id": "c", "data": map[string]any{"isSuccessful": true, "solutionName": "third"}},
+	},
+	"total": 3,
+}
+
+func TestSelectData(t *testing.T) {
+	// multiple results are collected into a list
+	v, err := selectData(selectTestData, ".items[] | select(.data.isSuccessful) | .data.solutionName")
+	require.Nil(t, err)
+	assert.Equal(t, []any{"first", "third"}, v)
+
+	// a single result is returned as is
+	v, err = selectData(selectTestData, ".total")
+	require.Nil(t, err)
+	assert.Equal(t, float64(3), v)
+
+	// structs are selected as they would be displayed in json
+	v, err = selectData(struct {
+		Name string `json:"name"`
+	}{Name: "x"}, ".name")
+	require.Nil(t, err)
+	assert.Equal(t, "x", v)
+
+	// errors
+	_, err = selectData(selectTestData, ".items[")
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid --select expression")
+	_, err = selectData(selectTestData, ".total | keys")
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "failed")
+	assert.NotContains(t, err.Error(), "JMESPath")
+
+	// JMESPath expressions are pointed to the jq syntax
+	_, err = selectData(selectTestData, "items[?data.isSuccessful].data.solutionName")
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not JMESPath")
+	_, err = selectData(selectTestData, "items[0].id")
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not JMESPath")
+}
+
+func TestPrintSelected(t *testing.T) {
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	// simple values are displayed one per line
+	table := &Table{Headers: []string{"ID"}, Lines: [][]string{{"a"}}}
+	printCmdOutputCustom(printRequest{cmd: cmd, format: "table", selection: "[.items[].id]"}, selectTestData, table)
+	assert.Equal(t, "a\nb\nc\n", out.String())
+
+	out.Reset()
+	printCmdOutputCustom(printRequest{cmd: cmd, format: "json", selection: "[.items[].id]"}, selectTestData, table)
+	assert.JSONEq(t, `["a", "b", "c"]`, out.String())
+
+	out.Reset()
+	printCmdOutputCustom(printRequest{cmd: cmd, format: "jsonl", selection: ".items[] | {id}"}, selectTestData, table)
+	assert.Equal(t, "{\"id\":\"a\"}\n{\"id\":\"b\"}\n{\"id\":\"c\"}\n", out.String())
+}
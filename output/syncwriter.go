@@ -0,0 +1,54 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"io"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// SyncWriter is an io.Writer that serializes concurrent writes to an underlying writer,
+// so that output from concurrent workers is not interleaved within a single write
+type SyncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewSyncWriter creates a SyncWriter around the given writer
+func NewSyncWriter(w io.Writer) *SyncWriter {
+	return &SyncWriter{w: w}
+}
+
+// Write writes p to the underlying writer, waiting for any write in progress to complete
+func (s *SyncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// EnableConcurrentOutput makes the command's output and error writers safe for use
+// by concurrent workers. Once enabled, each call to the print helpers in this package
+// (e.g., PrintCmdStatus) is written out without being interleaved with other calls.
+// Call it before starting the workers.
+func EnableConcurrentOutput(cmd *cobra.Command) {
+	if _, ok := cmd.OutOrStdout().(*SyncWriter); !ok {
+		cmd.SetOut(NewSyncWriter(cmd.OutOrStdout()))
+	}
+	if _, ok := cmd.ErrOrStderr().(*SyncWriter); !ok {
+		cmd.SetErr(NewSyncWriter(cmd.ErrOrStderr()))
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags that control how timestamps are displayed in the human output formats; the
+// json, jsonl and yaml formats always contain the timestamps as returned by the platform
+const (
+	UTCFlag          = "utc"           // display timestamps in UTC instead of the local timezone
+	RelativeTimeFlag = "relative-time" // display timestamps relative to now, e.g., "2h ago"
+)
+
+// timestampLayout is the layout of the displayed timestamps
+const timestampLayout = "2006-01-02 15:04:05 MST"
+
+// timestampColumnSuffixes identify the table columns that contain timestamps, matched against
+// the lowercase column header without spaces (e.g., "createdAt", "Solution Install Time")
+var timestampColumnSuffixes = []string{"createdat", "updatedat", "time", "timestamp", "date"}
+
+// now is the current time, for relative timestamps
+var now = time.Now
+
+// isTimestampColumn returns true if the table column with the given header is known to contain timestamps
+func isTimestampColumn(header string) bool {
+	name := strings.ToLower(strings.ReplaceAll(header, " ", ""))
+	for _, suffix := range timestampColumnSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatTimestamps returns the table with the values of the timestamp columns displayed in
+// the local timezone, in UTC or relative to now, as selected by the command's flags. Values
+// that are not RFC 3339 timestamps are left as they are.
+func formatTimestamps(cmd *cobra.Command, t *Table) *Table {
+	columns := []int{}
+	for i, header := range t.Headers {
+		if isTimestampColumn(header) {
+			columns = append(columns, i)
+		}
+	}
+	if len(columns) == 0 {
+		return t
+	}
+
+	location := time.Local
+	relative := false
+	if cmd != nil {
+		if utc, _ := cmd.Flags().GetBool(UTCFlag); utc {
+			location = time.UTC
+		}
+		relative, _ = cmd.Flags().GetBool(RelativeTimeFlag)
+	}
+
+	lines := make([][]string, len(t.Lines))
+	for i, line := range t.Lines {
+		lines[i] = append([]string(nil), line...)
+		for _, col := range columns {
+			if col < len(lines[i]) {
+				lines[i][col] = formatTimestamp(lines[i][col], location, relative)
+			}
+		}
+	}
+	formatted := *t // shallow copy
+	formatted.Lines = lines
+	return &formatted
+}
+
+// formatTimestamp displays an RFC 3339 timestamp in the given location or relative to now;
+// other values are returned unchanged
+func formatTimestamp(value string, location *time.Location, relative bool) string {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return value
+	}
+	if relative {
+		return relativeTime(t, now())
+	}
+	return t.In(location).Format(timestampLayout)
+}
+
+// relativeTime describes the time relative to now in its largest unit, e.g., "2h ago" or "in 5m"
+func relativeTime(t time.Time, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var amount string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		amount = fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		amount = fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		amount = fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTimestampColumn(t *testing.T) {
+	for _, header := range []string{"createdAt", "updatedAt", "Created At", "Solution Install Time", "Upload Timestamp", "installDate"} {
+		assert.True(t, isTimestampColumn(header), header)
+	}
+	for _, header := range []string{"Name", "format", "Solution Install Message"} {
+		assert.False(t, isTimestampColumn(header), header)
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	base := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, "just now", relativeTime(base.Add(-30*time.Second), base))
+	assert.Equal(t, "5m ago", relativeTime(base.Add(-5*time.Minute), base))
+	assert.Equal(t, "2h ago", relativeTime(base.Add(-2*time.Hour-10*time.Minute), base))
+	assert.Equal(t, "3d ago", relativeTime(base.Add(-73*time.Hour), base))
+	assert.Equal(t, "in 1h", relativeTime(base.Add(time.Hour), base))
+}
+
+func TestPrintTableTimestamps(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool(UTCFlag, false, "")
+	cmd.Flags().Bool(RelativeTimeFlag, false, "")
+	cmd.Flags().Bool(CompactFlag, true, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	table := &Table{
+		Headers: []string{"Name", "Created At"},
+		Lines:   [][]string{{"first", "2023-01-02T03:04:05.123Z"}, {"second", "unknown"}},
+	}
+
+	require.Nil(t, cmd.Flags().Set(UTCFlag, "true"))
+	printCmdOutputCustom(printRequest{cmd: cmd, format: "table"}, nil, table)
+	assert.Equal(t, "NAME\tCREATED AT\nfirst\t2023-01-02 03:04:05 UTC\nsecond\tunknown\n", out.String())
+	assert.Equal(t, "2023-01-02T03:04:05.123Z", table.Lines[0][1]) // the caller's table is not modified
+
+	now = func() time.Time { return time.Date(2023, 1, 2, 5, 10, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	out.Reset()
+	require.Nil(t, cmd.Flags().Set(RelativeTimeFlag, "true"))
+	printCmdOutputCustom(printRequest{cmd: cmd, format: "table"}, nil, table)
+	assert.Equal(t, "NAME\tCREATED AT\nfirst\t2h ago\nsecond\tunknown\n", out.String())
+
+	// machine formats keep the timestamps as they are
+	out.Reset()
+	printCmdOutputCustom(printRequest{cmd: cmd, format: "json"}, map[string]any{"createdAt": "2023-01-02T03:04:05.123Z"}, table)
+	assert.Contains(t, out.String(), `"2023-01-02T03:04:05.123Z"`)
+}
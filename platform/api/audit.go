@@ -0,0 +1,162 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apex/log"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+)
+
+// Results of the audited requests
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+)
+
+// AuditEntry is the record of a mutating request, written as a line of JSON in the audit log
+type AuditEntry struct {
+	Timestamp    string `json:"timestamp"`
+	Command      string `json:"command,omitempty"` // e.g., "fsoc objstore create"
+	Context      string `json:"context,omitempty"`
+	User         string `json:"user,omitempty"`
+	Tenant       string `json:"tenant,omitempty"`
+	FieldManager string `json:"fieldManager,omitempty"`
+	Operation    string `json:"operation"`          // HTTP method, e.g., "POST"
+	Path         string `json:"path"`               // request path, without the query string
+	Type         string `json:"type,omitempty"`     // object type, for object store requests
+	ObjectID     string `json:"objectId,omitempty"` // object ID, for object store requests
+	Result       string `json:"result"`
+	Status       int    `json:"status,omitempty"` // HTTP status, if a response was received
+	Error        string `json:"error,omitempty"`
+}
+
+var (
+	auditLogPath string
+	auditCommand string
+	auditMutex   sync.Mutex // serializes the entries of concurrent requests
+)
+
+// SetAuditLog enables appending an entry for each mutating request (POST, PUT, PATCH
+// and DELETE, except for read-only requests, see Options.ReadOnly) to the given file, attributed to the given command. Empty path disables it.
+// This function should not be used outside of the fsoc root pre-command.
+func SetAuditLog(path string, command string) {
+	auditLogPath = path
+	auditCommand = command
+}
+
+// isMutatingRequest returns true if the request changes the platform's state, based on its
+// HTTP method unless the caller marked it as read-only
+func isMutatingRequest(method string, options *Options) bool {
+	if options != nil && options.ReadOnly {
+		return false
+	}
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// auditRequest appends the entry for a request that was sent to the audit log, if enabled.
+// The response, if any, and the request's error determine the result. Failing to write the
+// entry does not fail the request.
+func auditRequest(cfg *config.Context, method string, path string, options *Options, resp *http.Response, respBytes []byte, err error) {
+	if auditLogPath == "" || !isMutatingRequest(method, options) {
+		return
+	}
+	entry := newAuditEntry(cfg, method, path, resp, respBytes, err)
+	if writeErr := appendAuditEntry(auditLogPath, entry); writeErr != nil {
+		log.Warnf("Failed to write to the audit log %q: %v", auditLogPath, writeErr)
+	}
+}
+
+func newAuditEntry(cfg *config.Context, method string, path string, resp *http.Response, respBytes []byte, err error) AuditEntry {
+	entry := AuditEntry{
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+		Command:      auditCommand,
+		Operation:    method,
+		Path:         path,
+		FieldManager: fieldManager,
+		Result:       AuditResultSuccess,
+	}
+	if i := strings.Index(path, "?"); i >= 0 {
+		entry.Path = path[:i]
+	}
+	if cfg != nil {
+		entry.Context = cfg.Name
+		entry.User = cfg.User
+		entry.Tenant = cfg.Tenant
+	}
+	entry.Type, entry.ObjectID = objectFromPath(entry.Path)
+	if resp != nil {
+		entry.Status = resp.StatusCode
+	}
+	// nb: a request whose response can't be parsed was still performed
+	if err != nil && (resp == nil || resp.StatusCode/100 != 2) {
+		entry.Result = AuditResultFailure
+		entry.Error = err.Error()
+	} else if entry.ObjectID == "" && entry.Type != "" {
+		// a created object's ID is returned in the response
+		var created struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(respBytes, &created) == nil {
+			entry.ObjectID = created.ID
+		}
+	}
+	return entry
+}
+
+// objectFromPath extracts the object type and ID, if present, from an object store path,
+// e.g., "objstore/v1beta/objects/<type>/<id>"
+func objectFromPath(path string) (string, string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 4 || segments[0] != "objstore" || segments[2] != "objects" {
+		return "", ""
+	}
+	objType := segments[3]
+	objectID := ""
+	if len(segments) > 4 {
+		objectID = segments[4]
+	}
+	return objType, objectID
+}
+
+func appendAuditEntry(path string, entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+)
+
+func TestObjectFromPath(t *testing.T) {
+	objType, id := objectFromPath("objstore/v1beta/objects/preferences:theme/mytheme")
+	assert.Equal(t, "preferences:theme", objType)
+	assert.Equal(t, "mytheme", id)
+
+	objType, id = objectFromPath("/objstore/v1beta/objects/preferences:theme")
+	assert.Equal(t, "preferences:theme", objType)
+	assert.Equal(t, "", id)
+
+	objType, _ = objectFromPath("solnmgmt/v1beta/solutions")
+	assert.Equal(t, "", objType)
+}
+
+func TestAuditLog(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "created-id"}`))
+	})
+	client.Context.Tenant, client.Context.User = "t1", "someone"
+
+	auditFile := filepath.Join(t.TempDir(), "audit.log")
+	SetAuditLog(auditFile, "fsoc objstore create")
+	defer SetAuditLog("", "")
+
+	var res any
+	require.NoError(t, client.JSONGet("objstore/v1beta/objects/preferences:theme/a", &res, nil)) // not audited
+	// a read-only POST (e.g., a query) is not audited either
+	require.NoError(t, client.JSONPost("monitoring/v1/query/execute", map[string]any{}, &res, &Options{ReadOnly: true}))
+	require.NoError(t, client.JSONPost("objstore/v1beta/objects/preferences:theme", map[string]any{}, &res, nil))
+	require.Error(t, client.JSONDelete("objstore/v1beta/objects/preferences:theme/missing?x=1", &res, nil))
+
+	data, err := os.ReadFile(auditFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var created, deleted AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &created))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &deleted))
+
+	assert.Equal(t, "fsoc objstore create", created.Command)
+	assert.Equal(t, "someone", created.User)
+	assert.Equal(t, "t1", created.Tenant)
+	assert.Equal(t, "POST", created.Operation)
+	assert.Equal(t, "preferences:theme", created.Type)
+	assert.Equal(t, "created-id", created.ObjectID)
+	assert.Equal(t, AuditResultSuccess, created.Result)
+	assert.Equal(t, 200, created.Status)
+	assert.Equal(t, config.DefaultFieldManager, created.FieldManager)
+
+	assert.Equal(t, "DELETE", deleted.Operation)
+	assert.Equal(t, "objstore/v1beta/objects/preferences:theme/missing", deleted.Path)
+	assert.Equal(t, "missing", deleted.ObjectID)
+	assert.Equal(t, AuditResultFailure, deleted.Result)
+	assert.Equal(t, 404, deleted.Status)
+	assert.NotEmpty(t, deleted.Error)
+}
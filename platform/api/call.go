@@ -37,6 +37,34 @@ import (
 type Options struct {
 	Headers         map[string]string
 	ResponseHeaders map[string][]string // headers as returned by the call
+	BaseURLOverride string              // base URL (e.g., "https://host:port/prefix") to use instead of the context's server; empty to use the context
+	QueryParams     map[string]string   // query parameters to add to the request URL (encoded by the call)
+
+	// ResponseStatusCode is the HTTP status code returned by the call. For JSON requests, 304 (Not Modified)
+	// is treated as success and leaves the output unchanged; it is returned only if the caller sent
+	// a conditional request (e.g., an If-None-Match header) and should use its cached copy.
+	ResponseStatusCode int
+
+	// CollectionTruncated is set by JSONGetCollection when the collection had more items than the
+	// maximum allowed (see SetMaxCollectionItems) and was truncated
+	CollectionTruncated bool
+
+	// CollectionTotal and CollectionPageSize are set by JSONGetCollection to the total number of items
+	// reported by the server and the number of items in the first page, respectively
+	CollectionTotal    int
+	CollectionPageSize int
+
+	// RetrySafe marks a non-idempotent request (POST, PATCH) as safe to retry on any transient failure,
+	// e.g., because it carries an idempotency key that the user knows the platform honors
+	RetrySafe bool
+
+	// ReadOnly marks a POST request that does not change the platform's state (e.g., a query), so that
+	// it is neither recorded in the audit log nor attributed to the field manager
+	ReadOnly bool
+
+	// ItemHandler, if set, is called by JSONGetCollection for each item as its page is received,
+	// instead of accumulating the items into the output; used to stream large collections
+	ItemHandler func(item any) error
 }
 
 // Problem type is a json object returned for content-type application/problem+json according to the RFC-7807
@@ -77,50 +105,81 @@ func (p Problem) Error() string {
 	return fmt.Sprintf("%s - %s", p.Title, p.Detail)
 }
 
-// JSONGet performs a GET request and parses the response as JSON
+// ResponseError is returned when the platform responds with an HTTP error status
+// and the response is not a Problem
+type ResponseError struct {
+	StatusCode int // HTTP status code, e.g., 500
+	Body       any // response body, parsed as JSON if possible, otherwise as string
+}
+
+func (e ResponseError) Error() string {
+	return fmt.Sprintf("error response: %+v", e.Body)
+}
+
+// HasStatus returns true if the error is a platform response (a ResponseError or a Problem)
+// with the given HTTP status
+func HasStatus(err error, status int) bool {
+	var respErr ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode == status
+	}
+	var problem Problem
+	if errors.As(err, &problem) {
+		return problem.Status == status
+	}
+	return false
+}
+
+// IsNotFound returns true if the error is a platform response indicating that the requested entity doesn't exist
+func IsNotFound(err error) bool {
+	return HasStatus(err, http.StatusNotFound)
+}
+
+// JSONGet performs a GET request and parses the response as JSON.
+// If out is a *[]byte, the response body is returned verbatim instead of being parsed.
 func JSONGet(path string, out any, options *Options) error {
-	return jsonRequest("GET", path, nil, out, options)
+	return defaultClient.JSONGet(path, out, options)
 }
 
 // JSONDelete performs a DELETE request and parses the response as JSON
 func JSONDelete(path string, out any, options *Options) error {
-	return jsonRequest("DELETE", path, nil, out, options)
+	return defaultClient.JSONDelete(path, out, options)
 }
 
 // JSONPost performs a POST request with JSON command and response
 func JSONPost(path string, body any, out any, options *Options) error {
-	return jsonRequest("POST", path, body, out, options)
+	return defaultClient.JSONPost(path, body, out, options)
 }
 
 // HTTPPost performs a POST request with HTTP command and response - Accept and Content-Type headers are provided by the caller
 func HTTPPost(path string, body []byte, out any, options *Options) error {
-	return httpRequest("POST", path, body, out, options)
+	return defaultClient.HTTPPost(path, body, out, options)
 }
 
 // HTTPGet performs a GET request with HTTP command and response - Accept and Content-Type headers are provided by the caller
 func HTTPGet(path string, out any, options *Options) error {
-	return httpRequest("GET", path, nil, out, options)
+	return defaultClient.HTTPGet(path, out, options)
 }
 
 // JSONPut performs a PUT request with JSON command and response
 func JSONPut(path string, body any, out any, options *Options) error {
-	return jsonRequest("PUT", path, body, out, options)
+	return defaultClient.JSONPut(path, body, out, options)
 }
 
 // JSONPatch performs a PATCH request and parses the response as JSON
 func JSONPatch(path string, body any, out any, options *Options) error {
-	return jsonRequest("PATCH", path, body, out, options)
+	return defaultClient.JSONPatch(path, body, out, options)
 }
 
 // JSONRequest performs an HTTP request and parses the response as JSON, allowing
 // the http method to be specified
 func JSONRequest(method string, path string, body any, out any, options *Options) error {
-	return jsonRequest(method, path, body, out, options)
+	return defaultClient.JSONRequest(method, path, body, out, options)
 }
 
 // --- Internal methods -----------------------------------------------------
 
-func jsonRequest(method string, path string, body any, out any, options *Options) error {
+func (c *Client) jsonRequest(method string, path string, body any, out any, options *Options) (err error) {
 	log.WithFields(log.Fields{"method": method, "path": path}).Info("Calling FSO platform API")
 
 	// create a default options to avoid nil-checking
@@ -128,106 +187,106 @@ func jsonRequest(method string, path string, body any, out any, options *Options
 		options = &Options{}
 	}
 
-	// get current context to obtain the URL and token (TODO: consider supporting unauth access for local dev)
-	cfg := config.GetCurrentContext()
+	// get the client's context to obtain the URL and token (TODO: consider supporting unauth access for local dev)
+	cfg := c.context()
 	if cfg == nil {
 		return errors.New("Missing context; use 'fsoc config set' to configure your context")
 	}
 	log.WithFields(log.Fields{"context": cfg.Name, "server": cfg.Server, "tenant": cfg.Tenant}).Info("Using context")
 
+	// display the request instead of executing it, if requested
+	if explainMode {
+		req, err := c.prepareJSONRequest(cfg, nil, method, path, body, options)
+		if err != nil {
+			return err
+		}
+		return explainRequest(req)
+	}
+
 	// force login if no token
 	if cfg.Token == "" {
 		log.Infof("No token available, trying to log in")
-		if err := Login(); err != nil {
+		var err error
+		if cfg, err = c.login(cfg); err != nil {
 			return err
 		}
-		cfg = config.GetCurrentContext()
 		if cfg.Token == "" {
 			return errors.New("Login succeeded but did not provide a token")
 		}
 	}
 
 	// create http client for the request
-	client := &http.Client{}
+	client := c.httpClient()
 
-	// build HTTP request
-	req, err := prepareJSONRequest(cfg, client, method, path, body, options.Headers)
-	if err != nil {
-		return err // anything that needed logging has been logged
-	}
-
-	// execute request
-	resp, err := client.Do(req)
+	// record the request in the audit log, with its final result
+	var resp *http.Response
+	var respBytes []byte
+	defer func() {
+		auditRequest(cfg, method, path, options, resp, respBytes, err)
+		recordRequestError(err)
+	}()
+
+	// build and execute HTTP request, retrying on transient failures
+	resp, respBytes, attempts, err := executeRequest(client, options != nil && options.RetrySafe, func() (*http.Request, error) {
+		return c.prepareJSONRequest(cfg, client, method, path, body, options)
+	})
 	if err != nil {
-		return fmt.Errorf("%v request to %q failed: %v", method, req.RequestURI, err)
+		return err
 	}
 
 	// log error if it occurred
-	if resp.StatusCode/100 != 2 {
+	if !isSuccessStatus(resp.StatusCode) {
 		// log error before trying to parse body, more processing later
 		log.Errorf("Request failed, status %q; more info to follow", resp.Status)
 	}
 
-	// collect response body (whether success or error)
-	var respBytes []byte
-	defer resp.Body.Close()
-	respBytes, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("Failed reading response to %v to %q: %v", method, req.RequestURI, err)
-	}
-
 	// handle special case when access token needs to be refreshed and request retried
 	if resp.StatusCode == http.StatusForbidden {
 		log.Info("Current token is no longer valid; trying to refresh")
-		err := Login()
+		// re-load context, including refreshed token
+		cfg, err = c.login(cfg)
 		if err != nil {
 			// nb: sufficient logging from login should have occurred
 			return err
 		}
 
-		// re-load context, including refreshed token
-		cfg = config.GetCurrentContext()
-
 		// retry the request
 		log.Info("Retrying the request with the refreshed token")
-		req, err = prepareJSONRequest(cfg, client, method, path, body, options.Headers)
+		var retryAttempts int
+		resp, respBytes, retryAttempts, err = executeRequest(client, options != nil && options.RetrySafe, func() (*http.Request, error) {
+			return c.prepareJSONRequest(cfg, client, method, path, body, options)
+		})
+		attempts += retryAttempts
 		if err != nil {
-			return err // anything that needed logging has been logged
-		}
-		resp, err = client.Do(req)
-		if err != nil {
-			return fmt.Errorf("%v request to %q failed: %v", method, req.RequestURI, err.Error())
+			return err
 		}
 
 		// log error if it occurred
-		if resp.StatusCode/100 != 2 {
+		if !isSuccessStatus(resp.StatusCode) {
 			// log error before trying to parse body, more processing later
 			log.Errorf("Request failed, status %q; more info to follow", resp.Status)
 		} else {
 			log.Infof("Request completed successfully: %v", resp.Status)
 		}
-
-		// collect response body (whether success or error)
-		defer resp.Body.Close()
-		respBytes, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("Failed reading response to %v to %q: %v", method, req.RequestURI, err)
-		}
 	}
 
-	if resp.StatusCode/100 != 2 {
-		return parseIntoError(resp, respBytes)
+	if !isSuccessStatus(resp.StatusCode) {
+		return responseError(resp, respBytes, attempts)
 	}
 
-	if method != "DELETE" {
-		if err := json.Unmarshal(respBytes, out); err != nil {
+	// parse response body, unless there is none (nb: 304 means the caller's cached copy is current)
+	if method != "DELETE" && resp.StatusCode != http.StatusNotModified {
+		if raw, ok := out.(*[]byte); ok {
+			*raw = respBytes // caller requested the response body verbatim
+		} else if err := json.Unmarshal(respBytes, out); err != nil {
 			return fmt.Errorf("Failed to JSON parse the response: %v (%q)", err, respBytes)
 		}
 		//log.Infof("API Response as struct %+v\n", out) //@@
 	}
 
-	// return response headers
+	// return response status and headers
 	if options != nil {
+		options.ResponseStatusCode = resp.StatusCode
 		if resp.Header != nil {
 			options.ResponseHeaders = map[string][]string(resp.Header)
 		} else {
@@ -238,7 +297,9 @@ func jsonRequest(method string, path string, body any, out any, options *Options
 	return nil
 }
 
-func prepareJSONRequest(cfg *config.Context, client *http.Client, method string, path string, body any, headers map[string]string) (*http.Request, error) {
+func (c *Client) prepareJSONRequest(cfg *config.Context, client *http.Client, method string, path string, body any, options *Options) (*http.Request, error) {
+	headers := c.requestHeaders(options)
+
 	// marshal body data into a io.Reader
 	var bodyReader io.Reader = nil
 	if body != nil {
@@ -252,22 +313,10 @@ func prepareJSONRequest(cfg *config.Context, client *http.Client, method string,
 	}
 
 	// create a HTTP request
-	url := &url.URL{
-		Scheme: "https",
-		Host:   cfg.Server,
-		Path:   path,
-	}
-
-	// checking if there is a query in the path
-	iQuery := strings.Index(path, "?")
-
-	if iQuery > -1 {
-		// extracting the query and updating the path
-
-		purePath := path[:iQuery]
-		query := path[iQuery+1:]
-		url.RawQuery = query
-		url.Path = purePath
+	url, err := c.requestURL(cfg, path, options)
+	if err != nil {
+		log.Errorf("Failed to determine the request URL: %v", err.Error())
+		return nil, err
 	}
 
 	req, err := http.NewRequest(method, url.String(), bodyReader)
@@ -290,15 +339,26 @@ func prepareJSONRequest(cfg *config.Context, client *http.Client, method string,
 	}
 
 	req.Header.Add("Authorization", "Bearer "+cfg.Token)
+	req.Header.Set("User-Agent", userAgent)
 
 	for k, v := range headers {
 		req.Header.Add(k, v)
 	}
+	addFieldManager(req, options)
 
 	return req, nil
 }
 
-func httpRequest(method string, path string, body []byte, out any, options *Options) error {
+// bytesBody returns a request body factory for a body held in memory
+func bytesBody(body []byte) func() (io.Reader, error) {
+	return func() (io.Reader, error) {
+		return bytes.NewReader(body), nil
+	}
+}
+
+// httpRequest performs a request whose body is created by newBody for each attempt
+// (retries and re-login create the body again) and parses the response as JSON
+func (c *Client) httpRequest(method string, path string, newBody func() (io.Reader, error), out any, options *Options) (err error) {
 	log.WithFields(log.Fields{"method": method, "path": path}).Info("Calling FSO platform API")
 
 	// create a default options to avoid nil-checking
@@ -306,38 +366,51 @@ func httpRequest(method string, path string, body []byte, out any, options *Opti
 		options = &Options{}
 	}
 
-	// get current context to obtain the URL and token (TODO: consider supporting unauth access for local dev)
-	cfg := config.GetCurrentContext()
+	// get the client's context to obtain the URL and token (TODO: consider supporting unauth access for local dev)
+	cfg := c.context()
 	if cfg == nil {
 		return errors.New("Missing context; use 'fsoc config set' to configure your context")
 	}
 	log.WithFields(log.Fields{"context": cfg.Name, "server": cfg.Server, "tenant": cfg.Tenant}).Info("Using context")
 
+	// display the request instead of executing it, if requested
+	if explainMode {
+		req, err := c.prepareHTTPRequest(cfg, nil, method, path, newBody, options)
+		if err != nil {
+			return err
+		}
+		return explainRequest(req)
+	}
+
 	// force login if no token
 	if cfg.Token == "" {
 		log.Infof("No token available, trying to log in")
-		if err := Login(); err != nil {
+		var err error
+		if cfg, err = c.login(cfg); err != nil {
 			return err
 		}
-		cfg = config.GetCurrentContext()
 		if cfg.Token == "" {
 			return errors.New("Login succeeded but did not provide a token")
 		}
 	}
 
 	// create http client for the request
-	client := &http.Client{}
+	client := c.httpClient()
 
-	// build HTTP request
-	req, err := prepareHTTPRequest(cfg, client, method, path, body, options.Headers)
-	if err != nil {
-		return err // anything that needed logging has been logged
-	}
-
-	// execute request
-	resp, err := client.Do(req)
+	// record the request in the audit log, with its final result
+	var resp *http.Response
+	var respBytes []byte
+	defer func() {
+		auditRequest(cfg, method, path, options, resp, respBytes, err)
+		recordRequestError(err)
+	}()
+
+	// build and execute HTTP request, retrying on transient failures
+	resp, respBytes, attempts, err := executeRequest(client, options != nil && options.RetrySafe, func() (*http.Request, error) {
+		return c.prepareHTTPRequest(cfg, client, method, path, newBody, options)
+	})
 	if err != nil {
-		return fmt.Errorf("%v request to %q failed: %v", method, req.RequestURI, err)
+		return err
 	}
 
 	// log error if it occurred
@@ -346,35 +419,25 @@ func httpRequest(method string, path string, body []byte, out any, options *Opti
 		log.Errorf("Request failed, status %q; more info to follow", resp.Status)
 	}
 
-	// collect response body (whether success or error)
-	var respBytes []byte
-	defer resp.Body.Close()
-	respBytes, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("Failed reading response to %v to %q: %v", method, req.RequestURI, err)
-	}
-
 	// handle special case when access token needs to be refreshed and request retried
 	if resp.StatusCode == http.StatusForbidden {
 		log.Info("Current token is no longer valid; trying to refresh")
-		err := Login()
+		// re-load context, including refreshed token
+		cfg, err = c.login(cfg)
 		if err != nil {
 			// nb: sufficient logging from login should have occurred
 			return err
 		}
 
-		// re-load context, including refreshed token
-		cfg = config.GetCurrentContext()
-
 		// retry the request
 		log.Info("Retrying the request with the refreshed token")
-		req, err = prepareHTTPRequest(cfg, client, method, path, body, options.Headers)
+		var retryAttempts int
+		resp, respBytes, retryAttempts, err = executeRequest(client, options != nil && options.RetrySafe, func() (*http.Request, error) {
+			return c.prepareHTTPRequest(cfg, client, method, path, newBody, options)
+		})
+		attempts += retryAttempts
 		if err != nil {
-			return err // anything that needed logging has been logged
-		}
-		resp, err = client.Do(req)
-		if err != nil {
-			return fmt.Errorf("%v request to %q failed: %v", method, req.RequestURI, err.Error())
+			return err
 		}
 
 		// log error if it occurred
@@ -384,17 +447,10 @@ func httpRequest(method string, path string, body []byte, out any, options *Opti
 		} else {
 			log.Infof("Request completed successfully: %v", resp.Status)
 		}
-
-		// collect response body (whether success or error)
-		defer resp.Body.Close()
-		respBytes, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("Failed reading response to %v to %q: %v", method, req.RequestURI, err)
-		}
 	}
 
 	if resp.StatusCode/100 != 2 {
-		return parseIntoError(resp, respBytes)
+		return responseError(resp, respBytes, attempts)
 	}
 
 	contentType := resp.Header.Get("content-type")
@@ -416,22 +472,51 @@ func httpRequest(method string, path string, body []byte, out any, options *Opti
 	return nil
 }
 
-func prepareHTTPRequest(cfg *config.Context, client *http.Client, method string, path string, body []byte, headers map[string]string) (*http.Request, error) {
-	// marshal body data into a io.Reader
-	bodyReader := bytes.NewReader(body)
-
-	// if body != nil {
-	// 	bodyBytes, err := json.Marshal(body)
-	// 	if err != nil {
-	// 		log.Errorf("Failed to marshal data: %v", err.Error())
-	// 		return nil, err
-	// 	}
-	// 	//log.Infof("HTTP Request body: %q", bodyBytes)
-	// 	bodyReader = bytes.NewReader(bodyBytes)
-	// }
+func (c *Client) prepareHTTPRequest(cfg *config.Context, client *http.Client, method string, path string, newBody func() (io.Reader, error), options *Options) (*http.Request, error) {
+	headers := c.requestHeaders(options)
 
 	// create a HTTP request
-	url := &url.URL{
+	url, err := c.requestURL(cfg, path, options)
+	if err != nil {
+		log.Errorf("Failed to determine the request URL: %v", err.Error())
+		return nil, err
+	}
+
+	// create the body for this attempt
+	bodyReader, err := newBody()
+	if err != nil {
+		log.Errorf("Failed to create the request body: %v", err.Error())
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, url.String(), bodyReader)
+	if err != nil {
+		if closer, ok := bodyReader.(io.Closer); ok {
+			closer.Close() // stop a streaming body's producer
+		}
+		log.Errorf("Failed to create a request %q: %v", url.String(), err.Error())
+		return nil, err
+	}
+
+	req.Header.Add("Authorization", "Bearer "+cfg.Token)
+	req.Header.Set("User-Agent", userAgent)
+
+	for k, v := range headers {
+		req.Header.Add(k, v)
+	}
+	addFieldManager(req, options)
+
+	return req, nil
+}
+
+// buildRequestURL creates the URL for a request to the given path (which may include a query string).
+// The URL is based on the context's server unless a base URL override is provided; an override
+// replaces the scheme and host and its path, if any, is used as a prefix to the request's path.
+// Query parameters from the options are encoded and appended to the path's query string, if any.
+func buildRequestURL(cfg *config.Context, path string, options *Options) (*url.URL, error) {
+	baseURLOverride := options.BaseURLOverride
+
+	reqURL := &url.URL{
 		Scheme: "https",
 		Host:   cfg.Server,
 		Path:   path,
@@ -445,23 +530,44 @@ func prepareHTTPRequest(cfg *config.Context, client *http.Client, method string,
 
 		purePath := path[:iQuery]
 		query := path[iQuery+1:]
-		url.RawQuery = query
-		url.Path = purePath
+		reqURL.RawQuery = query
+		reqURL.Path = purePath
 	}
 
-	req, err := http.NewRequest(method, url.String(), bodyReader)
-	if err != nil {
-		log.Errorf("Failed to create a request %q: %v", url.String(), err.Error())
-		return nil, err
+	// add structured query parameters
+	if len(options.QueryParams) > 0 {
+		values := url.Values{}
+		for k, v := range options.QueryParams {
+			values.Set(k, v)
+		}
+		if reqURL.RawQuery != "" {
+			reqURL.RawQuery += "&"
+		}
+		reqURL.RawQuery += values.Encode()
 	}
 
-	req.Header.Add("Authorization", "Bearer "+cfg.Token)
-
-	for k, v := range headers {
-		req.Header.Add(k, v)
+	// apply base URL override, if requested
+	if baseURLOverride != "" {
+		base, err := url.Parse(baseURLOverride)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL override %q: %v", baseURLOverride, err)
+		}
+		if base.Scheme == "" || base.Host == "" {
+			return nil, fmt.Errorf("invalid base URL override %q: scheme and host are required", baseURLOverride)
+		}
+		reqURL.Scheme = base.Scheme
+		reqURL.Host = base.Host
+		if prefix := strings.TrimSuffix(base.Path, "/"); prefix != "" {
+			reqURL.Path = prefix + "/" + strings.TrimPrefix(reqURL.Path, "/")
+		}
 	}
 
-	return req, nil
+	return reqURL, nil
+}
+
+// isSuccessStatus returns true if the HTTP status code indicates a successful JSON request
+func isSuccessStatus(statusCode int) bool {
+	return statusCode/100 == 2 || statusCode == http.StatusNotModified
 }
 
 // parseError creates an error from HTTP response data
@@ -485,5 +591,5 @@ func parseIntoError(resp *http.Response, respBytes []byte) error {
 		// process as a string instead, ignore parsing error
 		errobj = bytes.NewBuffer(respBytes).String()
 	}
-	return fmt.Errorf("error response: %+v", errobj)
+	return ResponseError{StatusCode: resp.StatusCode, Body: errobj}
 }
@@ -0,0 +1,52 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasStatus(t *testing.T) {
+	assert.True(t, IsNotFound(ResponseError{StatusCode: http.StatusNotFound}))
+	assert.True(t, IsNotFound(fmt.Errorf("failed: %w", Problem{Status: http.StatusNotFound})))
+	assert.False(t, IsNotFound(ResponseError{StatusCode: http.StatusConflict}))
+	assert.False(t, IsNotFound(errors.New("not found")))
+	assert.False(t, IsNotFound(nil))
+
+	assert.True(t, HasStatus(RetryError{Err: ResponseError{StatusCode: http.StatusTooManyRequests}}, http.StatusTooManyRequests))
+}
+
+func TestExplainMode(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	})
+	SetExplainMode(true)
+	t.Cleanup(func() {
+		SetExplainMode(false)
+		requestExplained = false
+	})
+
+	var res any
+	err := client.JSONGet("objects", &res, nil)
+	assert.True(t, errors.Is(err, ErrRequestExplained))
+	assert.True(t, RequestExplained())
+	assert.Equal(t, 0, requests)
+}
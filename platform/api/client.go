@@ -0,0 +1,195 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+)
+
+// Client performs requests to the platform API. The zero value is ready to use and
+// calls the platform configured in the current context, the same way as the package-level
+// functions (e.g., JSONGet) do.
+type Client struct {
+	// Context provides the server and credentials to use; nil to use the current context.
+	// A login (e.g., to refresh an expired token) replaces this context with a copy holding
+	// the new credentials in memory only, while a login for the current context is saved
+	// in the config file.
+	Context *config.Context
+
+	// BaseURL (e.g., "https://host:port/prefix") is used instead of the context's server;
+	// empty to use the context. A request's Options.BaseURLOverride takes precedence.
+	BaseURL string
+
+	// HTTPClient is used to send the requests; nil to use a client sharing connections
+	// with all other requests (see SetConnectionSettings)
+	HTTPClient *http.Client
+
+	// Headers are added to every request; headers in a request's Options take precedence
+	Headers map[string]string
+}
+
+// defaultClient is used by the package-level functions
+var defaultClient = &Client{}
+
+type clientKey struct{}
+
+// NewClient returns a client for the platform configured in the given context
+func NewClient(cfg *config.Context) *Client {
+	return &Client{Context: cfg}
+}
+
+// DefaultClient returns the client used by the package-level functions, which
+// calls the platform configured in the current context
+func DefaultClient() *Client {
+	return defaultClient
+}
+
+// WithClient returns a copy of the context which carries the given client, so that
+// commands can be run against a client other than the default one (e.g., in tests)
+func WithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client carried by the context, or the default client if none
+func ClientFromContext(ctx context.Context) *Client {
+	if ctx != nil {
+		if client, ok := ctx.Value(clientKey{}).(*Client); ok && client != nil {
+			return client
+		}
+	}
+	return defaultClient
+}
+
+// JSONGet performs a GET request, like the package-level JSONGet
+func (c *Client) JSONGet(path string, out any, options *Options) error {
+	return c.jsonRequest("GET", path, nil, out, options)
+}
+
+// JSONDelete performs a DELETE request, like the package-level JSONDelete
+func (c *Client) JSONDelete(path string, out any, options *Options) error {
+	return c.jsonRequest("DELETE", path, nil, out, options)
+}
+
+// JSONPost performs a POST request, like the package-level JSONPost
+func (c *Client) JSONPost(path string, body any, out any, options *Options) error {
+	return c.jsonRequest("POST", path, body, out, options)
+}
+
+// HTTPPost performs a POST request, like the package-level HTTPPost
+func (c *Client) HTTPPost(path string, body []byte, out any, options *Options) error {
+	return c.httpRequest("POST", path, bytesBody(body), out, options)
+}
+
+// HTTPGet performs a GET request, like the package-level HTTPGet
+func (c *Client) HTTPGet(path string, out any, options *Options) error {
+	return c.httpRequest("GET", path, bytesBody(nil), out, options)
+}
+
+// JSONPut performs a PUT request, like the package-level JSONPut
+func (c *Client) JSONPut(path string, body any, out any, options *Options) error {
+	return c.jsonRequest("PUT", path, body, out, options)
+}
+
+// JSONPatch performs a PATCH request, like the package-level JSONPatch
+func (c *Client) JSONPatch(path string, body any, out any, options *Options) error {
+	return c.jsonRequest("PATCH", path, body, out, options)
+}
+
+// JSONRequest performs a request with the given method, like the package-level JSONRequest
+func (c *Client) JSONRequest(method string, path string, body any, out any, options *Options) error {
+	return c.jsonRequest(method, path, body, out, options)
+}
+
+// --- Internal methods -----------------------------------------------------
+
+// loginMu serializes logins with the reading of the contexts, so that concurrent requests
+// (e.g., a batch of fetches) that find their token expired log in only once
+var loginMu sync.RWMutex
+
+// context returns the context to use for the next request, nil if not configured
+func (c *Client) context() *config.Context {
+	loginMu.RLock()
+	defer loginMu.RUnlock()
+	if c.Context != nil {
+		return c.Context
+	}
+	return config.GetCurrentContext()
+}
+
+// login logs into the client's context and returns the context with the new credentials.
+// stale is the context whose token was rejected; if a concurrent request has already
+// replaced that token, its context is returned without logging in again.
+func (c *Client) login(stale *config.Context) (*config.Context, error) {
+	loginMu.Lock()
+	defer loginMu.Unlock()
+
+	if c.Context == nil {
+		if cfg := config.GetCurrentContext(); cfg != nil && cfg.Token != "" && cfg.Token != stale.Token {
+			return cfg, nil
+		}
+		if err := Login(); err != nil {
+			return nil, err
+		}
+		return config.GetCurrentContext(), nil
+	}
+	if c.Context.Token != "" && c.Context.Token != stale.Token {
+		return c.Context, nil
+	}
+
+	// nb: the context is replaced rather than updated, since requests in progress may use it
+	cfg := *c.Context
+	if err := loginContext(&cfg); err != nil {
+		return nil, err
+	}
+	c.Context = &cfg
+	return c.Context, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return newHTTPClient()
+}
+
+// requestHeaders returns the client's headers combined with the request's headers
+func (c *Client) requestHeaders(options *Options) map[string]string {
+	if len(c.Headers) == 0 {
+		return options.Headers
+	}
+	headers := make(map[string]string, len(c.Headers)+len(options.Headers))
+	for k, v := range c.Headers {
+		headers[k] = v
+	}
+	for k, v := range options.Headers {
+		headers[k] = v
+	}
+	return headers
+}
+
+// requestURL returns the URL for a request, using the client's base URL unless the request overrides it
+func (c *Client) requestURL(cfg *config.Context, path string, options *Options) (*url.URL, error) {
+	if options.BaseURLOverride == "" && c.BaseURL != "" {
+		clientOptions := *options // shallow copy
+		clientOptions.BaseURLOverride = c.BaseURL
+		options = &clientOptions
+	}
+	return buildRequestURL(cfg, path, options)
+}
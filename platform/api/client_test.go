@@ -0,0 +1,95 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+)
+
+// newTestClient returns a client with a test token that sends its requests to the handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &Client{Context: &config.Context{Name: "test", Token: "test-token"}, BaseURL: srv.URL}
+}
+
+func TestClientRequest(t *testing.T) {
+	var got *http.Request
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "test"}`))
+	})
+	client.BaseURL += "/prefix"
+	client.Headers = map[string]string{"layer-type": "TENANT", "layer-id": "default"}
+	var res map[string]any
+	err := client.JSONGet("/objects", &res, &Options{Headers: map[string]string{"layer-id": "tenant1"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"name": "test"}, res)
+	assert.Equal(t, "/prefix/objects", got.URL.Path)
+	assert.Equal(t, "Bearer test-token", got.Header.Get("Authorization"))
+	assert.Equal(t, "TENANT", got.Header.Get("layer-type"))
+	assert.Equal(t, "tenant1", got.Header.Get("layer-id")) // request's headers take precedence
+}
+
+func TestClientFromContext(t *testing.T) {
+	client := NewClient(&config.Context{Name: "other"})
+
+	assert.True(t, ClientFromContext(WithClient(context.Background(), client)) == client)
+	assert.True(t, ClientFromContext(context.Background()) == DefaultClient())
+	assert.True(t, ClientFromContext(nil) == DefaultClient()) //nolint:staticcheck // nil context is handled
+}
+
+func TestFieldManagerHeader(t *testing.T) {
+	got := map[string]string{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got[r.Method] = r.Header.Get(FieldManagerHeader)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+	var res any
+	require.NoError(t, client.JSONGet("/objects/a", &res, nil))
+	require.NoError(t, client.JSONPost("/objects", map[string]any{}, &res, nil))
+	assert.Equal(t, "", got["GET"]) // only mutating requests are attributed
+	assert.Equal(t, config.DefaultFieldManager, got["POST"])
+	require.NoError(t, client.JSONPost("/query", map[string]any{}, &res, &Options{ReadOnly: true}))
+	assert.Equal(t, "", got["POST"]) // a read-only POST is not attributed either
+
+	SetFieldManager("release-pipeline")
+	defer SetFieldManager("")
+	require.NoError(t, client.JSONPut("/objects/a", map[string]any{}, &res, nil))
+	assert.Equal(t, "release-pipeline", got["PUT"])
+}
+
+func TestClientLoginAfterConcurrentRefresh(t *testing.T) {
+	// another request has already replaced the rejected token, so there is no second login
+	// (which would fail here, since the context has no credentials to log in with)
+	client := &Client{Context: &config.Context{Name: "test", Token: "new-token"}}
+	cfg, err := client.login(&config.Context{Name: "test", Token: "old-token"})
+	require.Nil(t, err)
+	assert.Equal(t, "new-token", cfg.Token)
+
+	_, err = client.login(&config.Context{Name: "test", Token: "new-token"})
+	assert.NotNil(t, err)
+}
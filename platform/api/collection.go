@@ -30,16 +30,41 @@ const (
 	nextRelName    = "next"
 )
 
+// DefaultMaxCollectionItems is the default limit on the number of items returned by JSONGetCollection
+const DefaultMaxCollectionItems = 1000
+
+var maxCollectionItems = DefaultMaxCollectionItems
+
 type dataPage struct {
-	Items []any `json:"items"`
-	Total int   `json:"total"`
+	Items     []any `json:"items"`
+	Total     int   `json:"total"`
+	Truncated bool  `json:"truncated,omitempty"`
+}
+
+// SetMaxCollectionItems sets the maximum number of items that JSONGetCollection returns;
+// collections with more items are truncated. Zero or negative value means no limit.
+// This function should not be used outside of the fsoc root pre-command.
+func SetMaxCollectionItems(n int) {
+	maxCollectionItems = n
+}
+
+// GetMaxCollectionItems returns the maximum number of items that JSONGetCollection returns
+func GetMaxCollectionItems() int {
+	return maxCollectionItems
 }
 
 // JSONGetCollection performs a GET request and parses the response as JSON,
 // handling pagination per https://www.rfc-editor.org/rfc/rfc5988,
 // https://developer.cisco.com/api-guidelines/#rest-style/API.REST.STYLE.25 and
 // https://developer.cisco.com/api-guidelines/#rest-style/API.REST.STYLE.24
+// If options.ItemHandler is set, items are passed to it as they arrive and the
+// returned collection contains only the total count.
 func JSONGetCollection(path string, out any, options *Options) error {
+	return defaultClient.JSONGetCollection(path, out, options)
+}
+
+// JSONGetCollection performs a GET request for a collection, like the package-level JSONGetCollection
+func (c *Client) JSONGetCollection(path string, out any, options *Options) error {
 
 	// ensure we can return the data
 	outPtr, ok := out.(*any)
@@ -53,12 +78,14 @@ func JSONGetCollection(path string, out any, options *Options) error {
 	}
 
 	var result dataPage
+	streaming := subOptions.ItemHandler != nil
+	count := 0 // number of items received, including streamed ones
 
 	var page dataPage
 	var pageNo int
 	for pageNo = 0; true; pageNo += 1 {
 		// request collection
-		err := jsonRequest("GET", path, nil, &page, &subOptions)
+		err := c.jsonRequest("GET", path, nil, &page, &subOptions)
 		if err != nil {
 			if pageNo > 0 {
 				return fmt.Errorf("Error retrieving non-first page #%v in collection at %q: %v. All data discarded", pageNo+1, path, err)
@@ -66,13 +93,44 @@ func JSONGetCollection(path string, out any, options *Options) error {
 			return err
 		}
 
+		if pageNo == 0 && options != nil {
+			options.CollectionTotal = page.Total
+			options.CollectionPageSize = len(page.Items)
+		}
+
 		// transfer received items
 		//log.Infof("Collection page #%v returned %v items, with total of %v", pageNo+1, len(page.Items), page.Total)
-		if result.Items == nil {
-			// initialize slice for the full result size
-			result.Items = make([]any, 0, page.Total)
+		items := page.Items
+		limitReached := maxCollectionItems > 0 && count+len(items) >= maxCollectionItems
+		if limitReached {
+			if count+len(items) > maxCollectionItems || hasNextPage(subOptions.ResponseHeaders) {
+				items = items[:maxCollectionItems-count]
+				result.Truncated = true
+			}
+		}
+		count += len(items)
+		if streaming {
+			for _, item := range items {
+				if err := subOptions.ItemHandler(item); err != nil {
+					return err
+				}
+			}
+		} else {
+			if result.Items == nil {
+				// initialize slice for the full result size, up to the maximum number of items kept
+				size := page.Total
+				if maxCollectionItems > 0 && size > maxCollectionItems {
+					size = maxCollectionItems
+				}
+				result.Items = make([]any, 0, size)
+			}
+			result.Items = append(result.Items, items...)
+		}
+
+		// stop if the maximum number of items has been reached
+		if limitReached {
+			break
 		}
-		result.Items = append(result.Items, page.Items...)
 
 		// break if no more pages (no response headers, no links or no next link)
 		if subOptions.ResponseHeaders == nil {
@@ -101,14 +159,33 @@ func JSONGetCollection(path string, out any, options *Options) error {
 		}
 		nextUrl.RawQuery = nextQuery
 		path = nextUrl.String()
+		subOptions.QueryParams = nil // already included in the next page's query string
 	}
 	log.Infof("Collection page #%v at %q returned %v items (last page)", pageNo+1, path, len(page.Items))
 
-	result.Total = len(result.Items)
-	if result.Total != page.Total {
+	result.Total = count
+	if result.Truncated {
+		log.Infof("Collection at %q was truncated to %v items", path, result.Total)
+		if options != nil {
+			options.CollectionTruncated = true
+		}
+	} else if result.Total != page.Total {
 		log.Warnf("Collection at %q returned %v items vs. expected %v items", path, result.Total, page.Total)
 	}
 	*outPtr = &result
 
 	return nil
 }
+
+// hasNextPage returns true if the response headers contain a link to a next page
+func hasNextPage(headers map[string][]string) bool {
+	if headers == nil {
+		return false
+	}
+	links, found := headers[linkHeaderName]
+	if !found {
+		return false
+	}
+	_, found = link.Parse(strings.Join(links, ", "))[nextRelName]
+	return found
+}
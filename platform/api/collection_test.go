@@ -0,0 +1,64 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionPaginationMetadata(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", `</objects?cursor=2>; rel="next"`)
+			_, _ = w.Write([]byte(`{"items": [1, 2], "total": 3}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"items": [3], "total": 3}`))
+	})
+
+	var res any
+	options := Options{}
+	require.NoError(t, client.JSONGetCollection("objects", &res, &options))
+
+	assert.Len(t, res.(*dataPage).Items, 3)
+	assert.Equal(t, 3, options.CollectionTotal)
+	assert.Equal(t, 2, options.CollectionPageSize)
+	assert.False(t, options.CollectionTruncated)
+}
+
+func TestCollectionCapacityLimitedByMaxItems(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": [1, 2, 3], "total": 1000000000}`))
+	})
+
+	saved := GetMaxCollectionItems()
+	SetMaxCollectionItems(2)
+	defer SetMaxCollectionItems(saved)
+
+	var res any
+	options := Options{}
+	require.NoError(t, client.JSONGetCollection("objects", &res, &options))
+
+	items := res.(*dataPage).Items
+	assert.Len(t, items, 2)
+	assert.Equal(t, 2, cap(items)) // not the total reported by the server
+	assert.True(t, options.CollectionTruncated)
+}
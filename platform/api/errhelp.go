@@ -0,0 +1,183 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/apex/log"
+)
+
+// ErrorExplanation is the human explanation of a platform error, with a suggested fix
+type ErrorExplanation struct {
+	Explanation string
+	Fix         string
+}
+
+// errorPattern maps platform errors with the given status (0 for any) whose message matches
+// the pattern (nil for any) to an explanation. The first matching pattern applies.
+type errorPattern struct {
+	status  func(int) bool
+	message *regexp.Regexp
+	ErrorExplanation
+}
+
+func statusIs(codes ...int) func(int) bool {
+	return func(status int) bool {
+		for _, code := range codes {
+			if status == code {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var errorPatterns = []errorPattern{
+	{statusIs(http.StatusUnauthorized), nil, ErrorExplanation{
+		"The platform did not accept the credentials, e.g., because the session or token expired.",
+		`Log in again with "fsoc login" or check the context's credentials with "fsoc config get".`,
+	}},
+	{statusIs(http.StatusForbidden, http.StatusPaymentRequired), regexp.MustCompile(`(?i)entitle|licen[cs]e|subscri|not enabled`), ErrorExplanation{
+		"The tenant is not entitled to the requested feature or solution.",
+		"Ask the tenant's administrator to enable the entitlement (or subscribe to the solution) and try again.",
+	}},
+	{statusIs(http.StatusForbidden), regexp.MustCompile(`(?i)layer`), ErrorExplanation{
+		"The object can't be changed in the requested layer, e.g., objects in the SOLUTION layer can only be changed by the solution.",
+		`Check --layer-type and --layer-id; "fsoc obj type-layers --type <type>" lists the layers a type supports.`,
+	}},
+	{statusIs(http.StatusForbidden), nil, ErrorExplanation{
+		"The user or service principal does not have the permission for this operation.",
+		"Check the roles of the principal used by the current context, or use a context with more privileges.",
+	}},
+	{statusIs(http.StatusBadRequest, http.StatusUnprocessableEntity), regexp.MustCompile(`(?i)schema|validat|required propert|additional propert|does not match|invalid type`), ErrorExplanation{
+		"The object does not conform to its type's JSON schema.",
+		`Compare the object with the schema shown by "fsoc obj get-type --type <type>", fix the listed fields and try again.`,
+	}},
+	{statusIs(http.StatusBadRequest, http.StatusNotFound), regexp.MustCompile(`(?i)layer`), ErrorExplanation{
+		"The layer type or layer ID does not match the layers in which the object or type exists.",
+		`Check --layer-type and --layer-id; "fsoc obj type-layers --type <type>" lists the layers a type supports.`,
+	}},
+	{statusIs(http.StatusNotFound), nil, ErrorExplanation{
+		"The requested entity does not exist, or it is not visible from the requested layer.",
+		"Check the type name, the object ID and the layer; objects created in lower layers are not visible from higher layers.",
+	}},
+	{statusIs(http.StatusConflict), nil, ErrorExplanation{
+		"An entity with the same ID already exists.",
+		`Use "fsoc obj update" to change it, or "fsoc obj create --merge-existing" to merge into it.`,
+	}},
+	{statusIs(http.StatusPreconditionFailed), nil, ErrorExplanation{
+		"The entity was changed by someone else since it was read.",
+		"Fetch the entity again, reapply the change and try again.",
+	}},
+	{statusIs(http.StatusTooManyRequests), nil, ErrorExplanation{
+		"Too many requests were sent to the platform in a short time.",
+		"Wait a moment and try again, or reduce the --concurrency of bulk commands.",
+	}},
+	{func(status int) bool { return status >= 500 }, nil, ErrorExplanation{
+		"The platform could not process the request because of a problem on its side.",
+		"Try again later; --retries retries requests that fail because the service is temporarily unavailable.",
+	}},
+}
+
+// ExplainError returns the explanation of a platform error response, if one is known
+func ExplainError(err error) (ErrorExplanation, bool) {
+	status := 0
+	var respErr ResponseError
+	var problem Problem
+	switch {
+	case errors.As(err, &respErr):
+		status = respErr.StatusCode
+	case errors.As(err, &problem):
+		status = problem.Status
+	default:
+		return ErrorExplanation{}, false
+	}
+
+	message := err.Error()
+	for _, p := range errorPatterns {
+		if p.status(status) && (p.message == nil || p.message.MatchString(message)) {
+			return p.ErrorExplanation, true
+		}
+	}
+	return ErrorExplanation{}, false
+}
+
+var (
+	explainErrors     bool
+	lastRequestError  error // error of the most recent request, nil if it succeeded
+	lastRequestMutex  sync.Mutex
+	explanationWriter io.Writer
+)
+
+// SetExplainErrors enables explaining the platform error of the last request after a command
+// reports an error, writing the explanation to the given writer (see ExplainErrorHandler).
+// This function should not be used outside of the fsoc root pre-command.
+func SetExplainErrors(enabled bool, w io.Writer) {
+	explainErrors = enabled
+	explanationWriter = w
+}
+
+// recordRequestError records the outcome of a request, to be explained if the command fails
+func recordRequestError(err error) {
+	if !explainErrors {
+		return
+	}
+	lastRequestMutex.Lock()
+	defer lastRequestMutex.Unlock()
+	lastRequestError = err
+}
+
+// ExplainErrorHandler returns a log handler that passes the log entries to the next handler and,
+// when an error is logged, displays the explanation of the last request's platform error, if any.
+// The explanation is displayed once, after the error.
+func ExplainErrorHandler(next log.Handler) log.Handler {
+	return log.HandlerFunc(func(e *log.Entry) error {
+		err := next.HandleLog(e)
+		if e.Level >= log.ErrorLevel {
+			explainLastRequestError()
+		}
+		return err
+	})
+}
+
+func explainLastRequestError() {
+	lastRequestMutex.Lock()
+	reqErr := lastRequestError
+	lastRequestError = nil
+	lastRequestMutex.Unlock()
+
+	if reqErr == nil || explanationWriter == nil {
+		return
+	}
+	explanation, ok := ExplainError(reqErr)
+	if !ok {
+		return
+	}
+	fmt.Fprint(explanationWriter, formatExplanation(explanation))
+}
+
+func formatExplanation(e ErrorExplanation) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\nWhat happened: %v\n", e.Explanation)
+	fmt.Fprintf(&sb, "Suggested fix: %v\n", e.Fix)
+	return sb.String()
+}
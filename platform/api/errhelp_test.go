@@ -0,0 +1,80 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainError(t *testing.T) {
+	tests := []struct {
+		err      error
+		contains string
+	}{
+		{ResponseError{StatusCode: http.StatusUnauthorized}, "fsoc login"},
+		{Problem{Status: http.StatusForbidden, Title: "Forbidden", Detail: "tenant is not entitled to this solution"}, "entitlement"},
+		{ResponseError{StatusCode: http.StatusForbidden, Body: "cannot modify objects in the SOLUTION layer"}, "type-layers"},
+		{ResponseError{StatusCode: http.StatusForbidden, Body: "access denied"}, "roles"},
+		{Problem{Status: http.StatusBadRequest, Title: "Bad Request", Detail: "object failed schema validation: missing required property"}, "get-type"},
+		{fmt.Errorf("failed: %w", ResponseError{StatusCode: http.StatusNotFound}), "object ID"},
+		{ResponseError{StatusCode: http.StatusConflict}, "--merge-existing"},
+		{ResponseError{StatusCode: http.StatusServiceUnavailable}, "--retries"},
+	}
+	for _, tt := range tests {
+		explanation, ok := ExplainError(tt.err)
+		assert.True(t, ok, "no explanation for %v", tt.err)
+		assert.Contains(t, explanation.Fix, tt.contains)
+	}
+
+	_, ok := ExplainError(fmt.Errorf("connection refused"))
+	assert.False(t, ok)
+	_, ok = ExplainError(ResponseError{StatusCode: http.StatusTeapot})
+	assert.False(t, ok)
+}
+
+func TestExplainErrorHandler(t *testing.T) {
+	var out bytes.Buffer
+	SetExplainErrors(true, &out)
+	t.Cleanup(func() { SetExplainErrors(false, nil) })
+
+	handled := 0
+	handler := ExplainErrorHandler(log.HandlerFunc(func(e *log.Entry) error {
+		handled++
+		return nil
+	}))
+
+	// warnings are not explained; errors are, once
+	recordRequestError(ResponseError{StatusCode: http.StatusConflict})
+	_ = handler.HandleLog(&log.Entry{Level: log.WarnLevel})
+	assert.Empty(t, out.String())
+	_ = handler.HandleLog(&log.Entry{Level: log.ErrorLevel})
+	assert.Contains(t, out.String(), "Suggested fix: ")
+	out.Reset()
+	_ = handler.HandleLog(&log.Entry{Level: log.ErrorLevel})
+	assert.Empty(t, out.String())
+	assert.Equal(t, 3, handled)
+
+	// the last request succeeded, nothing to explain
+	recordRequestError(ResponseError{StatusCode: http.StatusConflict})
+	recordRequestError(nil)
+	_ = handler.HandleLog(&log.Entry{Level: log.ErrorLevel})
+	assert.Empty(t, out.String())
+}
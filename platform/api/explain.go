@@ -0,0 +1,136 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var explainMode bool
+
+// requestExplained is set once a request has been displayed in explain mode
+var requestExplained bool
+
+// ErrRequestExplained is returned for a request that was displayed instead of being executed
+// in explain mode. It is not a failure: the command is expected to stop without reporting it.
+var ErrRequestExplained = errors.New("the request was displayed instead of being executed")
+
+// maskedHeaders lists the headers whose values are not displayed when explaining a request
+var maskedHeaders = []string{"Authorization", "Cookie"}
+
+// SetExplainMode enables or disables explain mode. In explain mode, the first
+// request made to the platform is displayed instead of being executed and fails with
+// ErrRequestExplained, so that the command stops.
+// This function should not be used outside of the fsoc root pre-command.
+func SetExplainMode(enabled bool) {
+	explainMode = enabled
+}
+
+// RequestExplained returns true if a request was displayed instead of being executed, i.e.,
+// the command stopped at its first request rather than failed
+func RequestExplained() bool {
+	return requestExplained
+}
+
+// explainRequest displays the request plan (method, URL, headers, query and body)
+// to stdout and returns ErrRequestExplained, without executing the request
+func explainRequest(req *http.Request) error {
+	fmt.Fprint(os.Stdout, formatRequestPlan(req))
+	requestExplained = true
+	return ErrRequestExplained
+}
+
+func formatRequestPlan(req *http.Request) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Method: %v\n", req.Method)
+	u := *req.URL
+	u.RawQuery = ""
+	fmt.Fprintf(&sb, "URL: %v\n", u.String())
+
+	// headers, sorted and masked
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sb.WriteString("Headers:\n")
+	for _, name := range names {
+		value := strings.Join(req.Header.Values(name), ", ")
+		for _, masked := range maskedHeaders {
+			if strings.EqualFold(name, masked) {
+				value = "(masked)"
+			}
+		}
+		fmt.Fprintf(&sb, "  %v: %v\n", name, value)
+	}
+
+	// query parameters, sorted
+	query := req.URL.Query()
+	if len(query) > 0 {
+		keys := make([]string, 0, len(query))
+		for key := range query {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		sb.WriteString("Query:\n")
+		for _, key := range keys {
+			fmt.Fprintf(&sb, "  %v: %v\n", key, strings.Join(query[key], ", "))
+		}
+	}
+
+	// body, pretty-printed if JSON
+	body := requestBodyBytes(req)
+	if len(body) > 0 {
+		sb.WriteString("Body:\n")
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "   ") == nil {
+			sb.Write(pretty.Bytes())
+			sb.WriteString("\n")
+		} else if utf8.Valid(body) && !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") {
+			sb.Write(body)
+			sb.WriteString("\n")
+		} else {
+			fmt.Fprintf(&sb, "(%v bytes of %v)\n", len(body), req.Header.Get("Content-Type"))
+		}
+	}
+
+	return sb.String()
+}
+
+func requestBodyBytes(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	reader, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer reader.Close()
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil
+	}
+	return body
+}
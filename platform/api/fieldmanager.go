@@ -0,0 +1,45 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/cisco-open/fsoc/cmd/config"
+)
+
+// FieldManagerHeader is the header that identifies the tool (or person) making a change,
+// sent with each mutating request so that the platform can attribute the change
+const FieldManagerHeader = "X-Field-Manager"
+
+// fieldManager is the FieldManagerHeader value sent with mutating requests
+var fieldManager = config.DefaultFieldManager
+
+// SetFieldManager sets the identifier sent with each mutating request; empty restores the default.
+// This function should not be used outside of the fsoc root pre-command.
+func SetFieldManager(value string) {
+	if value == "" {
+		value = config.DefaultFieldManager
+	}
+	fieldManager = value
+}
+
+// addFieldManager adds the field manager header to mutating requests, unless the caller
+// already specified it
+func addFieldManager(req *http.Request, options *Options) {
+	if isMutatingRequest(req.Method, options) && req.Header.Get(FieldManagerHeader) == "" {
+		req.Header.Set(FieldManagerHeader, fieldManager)
+	}
+}
@@ -50,8 +50,22 @@ var fieldToFlag = map[string]string{
 func Login() error {
 	log.Infof("Login is forced in order to get a valid access token")
 
-	// get and check current context for required fields
+	// get current context and log into it
 	cfg := config.GetCurrentContext()
+	if err := loginContext(cfg); err != nil {
+		return err
+	}
+
+	// update current context with logged in credentials (token(s)) to use
+	config.ReplaceCurrentContext(cfg)
+
+	return nil
+}
+
+// loginContext performs a login for the given context, updating its credentials (token(s))
+// in place; it does not save them
+func loginContext(cfg *config.Context) error {
+	// check context for required fields
 	if err := checkConfigForAuth(cfg); err != nil {
 		return err
 	}
@@ -69,14 +83,7 @@ func Login() error {
 	default:
 		panic(fmt.Sprintf("bug: unhandled authentication method %q", cfg.AuthMethod))
 	}
-	if authErr != nil {
-		return authErr
-	}
-
-	// update current context with logged in credentials (token(s)) to use
-	config.ReplaceCurrentContext(cfg)
-
-	return nil
+	return authErr
 }
 
 func nonZeroStructFields(theStruct *config.Context) []string {
@@ -31,6 +31,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/cisco-open/fsoc/cmd/config"
+	"github.com/cisco-open/fsoc/output"
 )
 
 const (
@@ -109,6 +110,11 @@ func oauthLogin(ctx *config.Context) error {
 		}
 	}
 
+	// a new login requires the user to authenticate in the browser
+	if err := output.CheckInputAllowed(); err != nil {
+		return fmt.Errorf("cannot log in interactively: %w", err)
+	}
+
 	// generate PKCE codes
 	code, err := pkce.Generate()
 	if err != nil {
@@ -201,18 +207,22 @@ func getAuthorizationCodes(url string) (*authCodes, error) {
 		// fall through
 	}
 
-	// wait for authorization codes (TODO: add timeout, e.g., a few minutes)
-	authCode := <-respChan // nb: blocks until a callback is received on localhost with the correct path
-	log.Infof("PKCE authorization codes received")
-
-	return &authCode, nil
+	// wait for authorization codes (TODO: add timeout, e.g., a few minutes), until a callback is
+	// received on localhost with the correct path or the command is interrupted
+	select {
+	case authCode := <-respChan:
+		log.Infof("PKCE authorization codes received")
+		return &authCode, nil
+	case <-cancelCtx.Done():
+		return nil, fmt.Errorf("login canceled before the authorization completed: %w", cancelCtx.Err())
+	}
 }
 
 func exchangeCodeForToken(conf *oauth2.Config, pkce pkce.Code, auth *authCodes) (*appTokens, error) {
 	log.Infof("Exchanging authorization codes for access token")
 
 	// create http client for the request
-	client := &http.Client{}
+	client := newHTTPClient()
 
 	// prepare urlencoded data body
 	values := url.Values{}
@@ -224,11 +234,12 @@ func exchangeCodeForToken(conf *oauth2.Config, pkce pkce.Code, auth *authCodes)
 	bodyReader := bytes.NewReader([]byte(values.Encode()))
 
 	// create a POST HTTP request
-	req, err := http.NewRequest("POST", conf.Endpoint.TokenURL, bodyReader)
+	req, err := http.NewRequestWithContext(cancelCtx, "POST", conf.Endpoint.TokenURL, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create a request %q: %v", conf.Endpoint.TokenURL, err.Error())
 	}
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", userAgent)
 
 	// execute request
 	resp, err := client.Do(req)
@@ -281,7 +292,7 @@ func oauthRefreshToken(ctx *config.Context) error {
 	log.Infof("Trying to get a new access token using the refresh token")
 
 	// create http client for the request
-	client := &http.Client{}
+	client := newHTTPClient()
 
 	// prepare urlencoded data body
 	values := url.Values{}
@@ -293,11 +304,12 @@ func oauthRefreshToken(ctx *config.Context) error {
 
 	// create a POST HTTP request
 	tokenUri := oauthUriWithSuffix(ctx, oauth2TokenUriSuffix)
-	req, err := http.NewRequest("POST", tokenUri, bodyReader)
+	req, err := http.NewRequestWithContext(cancelCtx, "POST", tokenUri, bodyReader)
 	if err != nil {
 		return fmt.Errorf("Failed to create a token refresh request %q: %v", tokenUri, err)
 	}
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	req.Header.Set("User-Agent", userAgent)
 
 	// execute request
 	resp, err := client.Do(req)
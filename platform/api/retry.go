@@ -0,0 +1,247 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/apex/log"
+)
+
+// IdempotencyKeyHeader is the request header that allows the platform to recognize repeated
+// attempts of the same write request. Since the platform may not honor it, the header alone does
+// not make a request safe to retry (see Options.RetrySafe).
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxRetries is the number of times a request is retried after a transient failure
+var maxRetries = 0
+
+// retryWrites enables retrying non-idempotent requests (POST, PATCH) as if they were idempotent
+var retryWrites = false
+
+// maxRetryElapsed is the total time within which a request can be retried; zero for no limit
+var maxRetryElapsed time.Duration
+
+// maxBackoff caps the backoff before a retry
+const maxBackoff = time.Minute
+
+// retryDelay returns the time to wait before the given retry (1-based). It uses "full jitter":
+// a random delay up to a backoff that doubles with each retry, so that requests failing at the
+// same time (e.g., in parallel bulk operations) are not all retried at the same time.
+var retryDelay = func(retry int) time.Duration {
+	return time.Duration(rand.Int63n(int64(retryBackoff(retry)) + 1))
+}
+
+// retryBackoff returns the maximum delay before the given retry (1-based), doubling with each retry
+func retryBackoff(retry int) time.Duration {
+	if retry > 7 { // nb: 1s << 6 already exceeds the cap
+		return maxBackoff
+	}
+	backoff := time.Second << (retry - 1)
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// cancelCtx is the context whose cancellation (e.g., by a repeated interrupt) aborts requests and stops retrying them
+var cancelCtx = context.Background()
+
+// SetContext sets the context whose cancellation aborts requests in progress and stops retrying
+// failed requests, so that commands aborted by the user or their deadline don't keep waiting for
+// the platform (see cmdkit.RequestContext).
+// This function should not be used outside of the fsoc root pre-command.
+func SetContext(ctx context.Context) {
+	if ctx != nil {
+		cancelCtx = ctx
+	}
+}
+
+// waitForRetry waits for the given delay before a retry, returning an error
+// if the context is canceled in the meantime
+func waitForRetry(delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-cancelCtx.Done():
+		return cancelCtx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SetMaxRetries sets the number of times a request is retried after a transient failure
+// (connection error or a 502, 503 or 504 response). Zero means no retries.
+// Which failures are retried depends on the request method:
+//
+//	Method                     Connection failed   Other network error   502, 503, 504
+//	GET, HEAD, OPTIONS         retried             retried               retried
+//	PUT, DELETE                retried             retried               retried
+//	POST, PATCH                retried             not retried           not retried
+//	POST, PATCH, safe to retry retried             retried               retried
+//
+// A connection failure means the request was never sent (e.g., connection refused or DNS
+// failure). Other network errors (e.g., a reset connection or a timeout) and error responses
+// may happen after the platform has processed the request, so repeating a non-idempotent
+// request could, for example, create an object twice. POST and PATCH requests are safe to retry
+// if the caller marks them as such (see Options.RetrySafe) or if retrying writes is enabled
+// (see SetRetryWrites).
+// This function should not be used outside of the fsoc root pre-command.
+func SetMaxRetries(n int) {
+	maxRetries = n
+}
+
+// SetMaxRetryElapsed sets the total time within which a request can be retried (see SetMaxRetries),
+// counted from the first attempt: a retry that would start after it is not made. Zero means no limit.
+// This function should not be used outside of the fsoc root pre-command.
+func SetMaxRetryElapsed(d time.Duration) {
+	maxRetryElapsed = d
+}
+
+// retryAllowed returns true if the given retry (1-based) is within both the number of retries and,
+// after waiting for the delay, the time budget for retrying a request whose first attempt started at start
+func retryAllowed(retry int, start time.Time, delay time.Duration) bool {
+	if retry > maxRetries {
+		return false
+	}
+	return maxRetryElapsed <= 0 || time.Since(start)+delay <= maxRetryElapsed
+}
+
+// SetRetryWrites enables retrying POST and PATCH requests on any transient failure, like
+// idempotent requests (see SetMaxRetries).
+// This function should not be used outside of the fsoc root pre-command.
+func SetRetryWrites(enabled bool) {
+	retryWrites = enabled
+}
+
+// isSafeToRetry returns true if the request can be sent again even if the platform
+// may have processed it already; retrySafe is set if the caller marked the request as such
+func isSafeToRetry(req *http.Request, retrySafe bool) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return retryWrites || retrySafe
+}
+
+// isConnectError returns true if the error indicates that the connection could not be
+// established, i.e., the request was never sent
+func isConnectError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// RetryError is returned when a request failed after more than one attempt.
+// It wraps the error of the last attempt.
+type RetryError struct {
+	Attempts   int   // number of attempts made
+	LastStatus int   // HTTP status code of the last attempt; 0 if no response was received
+	Err        error // error of the last attempt
+}
+
+func (e RetryError) Error() string {
+	if e.LastStatus == 0 {
+		return fmt.Sprintf("failed after %v attempts: %v", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("failed after %v attempts, last status %v: %v", e.Attempts, e.LastStatus, e.Err)
+}
+
+func (e RetryError) Unwrap() error {
+	return e.Err
+}
+
+// isRetryableStatus returns true for the HTTP status codes that indicate a transient server condition
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusBadGateway ||
+		statusCode == http.StatusServiceUnavailable ||
+		statusCode == http.StatusGatewayTimeout
+}
+
+// executeRequest executes the request created by newRequest, retrying it on transient failures
+// up to the configured number of retries; retrySafe marks a non-idempotent request as safe to retry.
+// It returns the response (with the body already read and closed), the response body and the
+// number of attempts made.
+func executeRequest(client *http.Client, retrySafe bool, newRequest func() (*http.Request, error)) (*http.Response, []byte, int, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, nil, attempt - 1, err // anything that needed logging has been logged
+		}
+		req = req.WithContext(cancelCtx) // abort the request if the command is aborted
+		if traceMode {
+			req = traceRequest(req)
+		}
+
+		resp, err := client.Do(req)
+		showRequestURL(req, resp, err)
+		if err != nil {
+			retryable := isSafeToRetry(req, retrySafe) || isConnectError(err)
+			err = fmt.Errorf("%v request to %q failed: %w", req.Method, req.URL, err)
+			delay := retryDelay(attempt)
+			if !retryable || !retryAllowed(attempt, start, delay) {
+				if attempt > 1 {
+					err = RetryError{Attempts: attempt, Err: err}
+				}
+				return nil, nil, attempt, err
+			}
+			log.Warnf("%v; retrying in %v (retry %v of %v)", err, delay.Round(time.Millisecond), attempt, maxRetries)
+			if cancelErr := waitForRetry(delay); cancelErr != nil {
+				return nil, nil, attempt, fmt.Errorf("%v; not retried: %w", err, cancelErr)
+			}
+			continue
+		}
+
+		// collect response body (whether success or error)
+		respBytes, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, attempt, fmt.Errorf("Failed reading response to %v to %q: %v", req.Method, req.URL, err)
+		}
+
+		if !isRetryableStatus(resp.StatusCode) || !isSafeToRetry(req, retrySafe) {
+			return resp, respBytes, attempt, nil
+		}
+		delay := retryDelay(attempt)
+		if !retryAllowed(attempt, start, delay) {
+			return resp, respBytes, attempt, nil
+		}
+		log.Warnf("Request failed, status %q; retrying in %v (retry %v of %v)", resp.Status, delay.Round(time.Millisecond), attempt, maxRetries)
+		if waitForRetry(delay) != nil {
+			return resp, respBytes, attempt, nil // return the last response
+		}
+	}
+}
+
+// responseError creates the error for a failed response, noting the number
+// of attempts if the request was tried more than once
+func responseError(resp *http.Response, respBytes []byte, attempts int) error {
+	err := parseIntoError(resp, respBytes)
+	if attempts > 1 {
+		return RetryError{Attempts: attempts, LastStatus: resp.StatusCode, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,275 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setTestRetries(t *testing.T, n int) {
+	savedRetries, savedDelay := maxRetries, retryDelay
+	maxRetries = n
+	retryDelay = func(int) time.Duration { return 0 }
+	t.Cleanup(func() {
+		maxRetries, retryDelay = savedRetries, savedDelay
+	})
+}
+
+func TestExecuteRequestRetryBudgetExhausted(t *testing.T) {
+	setTestRetries(t, 2)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("try later"))
+	}))
+	defer srv.Close()
+
+	resp, body, attempts, err := executeRequest(srv.Client(), false, func() (*http.Request, error) {
+		return http.NewRequest("GET", srv.URL, nil)
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 3, attempts)
+
+	err = responseError(resp, body, attempts)
+	assert.Equal(t, `failed after 3 attempts, last status 503: error response: try later`, err.Error())
+	var respErr ResponseError
+	require.True(t, errors.As(err, &respErr))
+	assert.Equal(t, http.StatusServiceUnavailable, respErr.StatusCode)
+}
+
+func TestExecuteRequestRecovers(t *testing.T) {
+	setTestRetries(t, 2)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	resp, _, attempts, err := executeRequest(srv.Client(), false, func() (*http.Request, error) {
+		return http.NewRequest("GET", srv.URL, nil)
+	})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestExecuteRequestNoRetryOnClientError(t *testing.T) {
+	setTestRetries(t, 2)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	resp, body, attempts, err := executeRequest(srv.Client(), false, func() (*http.Request, error) {
+		return http.NewRequest("GET", srv.URL, nil)
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 1, calls)
+
+	// a single attempt is reported as the plain response error
+	err = responseError(resp, body, attempts)
+	assert.IsType(t, ResponseError{}, err)
+}
+
+func TestExecuteRequestNoRetryWhenCanceled(t *testing.T) {
+	setTestRetries(t, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	SetContext(ctx)
+	t.Cleanup(func() { cancelCtx = context.Background() })
+	retryDelay = func(int) time.Duration {
+		cancel() // interrupted while waiting to retry
+		return time.Hour
+	}
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	resp, _, attempts, err := executeRequest(srv.Client(), false, func() (*http.Request, error) {
+		return http.NewRequest("GET", srv.URL, nil)
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestExecuteRequestAbortedWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	SetContext(ctx)
+	t.Cleanup(func() { cancelCtx = context.Background() })
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel() // interrupted while the request is in progress
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	_, _, _, err := executeRequest(srv.Client(), false, func() (*http.Request, error) {
+		return http.NewRequest("GET", srv.URL, nil)
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestExecuteRequestRetryByMethod(t *testing.T) {
+	setTestRetries(t, 1)
+
+	tests := []struct {
+		method      string
+		header      string
+		retrySafe   bool
+		retryWrites bool
+		wantCalls   int
+	}{
+		{method: "GET", wantCalls: 2},
+		{method: "HEAD", wantCalls: 2},
+		{method: "OPTIONS", wantCalls: 2},
+		{method: "PUT", wantCalls: 2},
+		{method: "DELETE", wantCalls: 2},
+		{method: "POST", wantCalls: 1},
+		{method: "PATCH", wantCalls: 1},
+		{method: "POST", header: "key-1", wantCalls: 1}, // the platform may not honor the key
+		{method: "POST", header: "key-1", retrySafe: true, wantCalls: 2},
+		{method: "PATCH", retrySafe: true, wantCalls: 2},
+		{method: "POST", retryWrites: true, wantCalls: 2},
+		{method: "PATCH", retryWrites: true, wantCalls: 2},
+	}
+	for _, tt := range tests {
+		retryWrites = tt.retryWrites
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+
+		resp, _, attempts, err := executeRequest(srv.Client(), tt.retrySafe, func() (*http.Request, error) {
+			req, err := http.NewRequest(tt.method, srv.URL, nil)
+			if err == nil && tt.header != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.header)
+			}
+			return req, err
+		})
+		srv.Close()
+
+		require.Nil(t, err, tt)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, tt)
+		assert.Equal(t, tt.wantCalls, calls, tt)
+		assert.Equal(t, tt.wantCalls, attempts, tt)
+	}
+	retryWrites = false
+}
+
+func TestExecuteRequestRetryWriteOnConnectError(t *testing.T) {
+	setTestRetries(t, 1)
+
+	// a closed server refuses connections, so the request is never sent and can be retried
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+
+	_, _, attempts, err := executeRequest(http.DefaultClient, false, func() (*http.Request, error) {
+		return http.NewRequest("POST", url, nil)
+	})
+	require.NotNil(t, err)
+	assert.Equal(t, 2, attempts)
+	var retryErr RetryError
+	assert.True(t, errors.As(err, &retryErr))
+}
+
+func TestExecuteRequestNoRetryWriteOnNetworkError(t *testing.T) {
+	setTestRetries(t, 1)
+
+	// the connection is dropped after the request is received, so it may have been processed
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer srv.Close()
+
+	_, _, attempts, err := executeRequest(srv.Client(), false, func() (*http.Request, error) {
+		return http.NewRequest("POST", srv.URL, nil)
+	})
+	require.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, attempts)
+
+	// an idempotent request is retried
+	calls = 0
+	_, _, attempts, err = executeRequest(srv.Client(), false, func() (*http.Request, error) {
+		return http.NewRequest("PUT", srv.URL, nil)
+	})
+	require.NotNil(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestExecuteRequestRetryElapsedExhausted(t *testing.T) {
+	setTestRetries(t, 10)
+	retryDelay = func(int) time.Duration { return 20 * time.Millisecond }
+	savedElapsed := maxRetryElapsed
+	maxRetryElapsed = 50 * time.Millisecond
+	t.Cleanup(func() { maxRetryElapsed = savedElapsed })
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, _, attempts, err := executeRequest(srv.Client(), false, func() (*http.Request, error) {
+		return http.NewRequest("GET", srv.URL, nil)
+	})
+	require.Nil(t, err)
+	assert.Equal(t, calls, attempts)
+	assert.True(t, attempts >= 2 && attempts <= 3, "retries stop when the time budget is used up, got %v attempts", attempts)
+}
+
+func TestRetryDelayJitter(t *testing.T) {
+	for retry := 1; retry <= 10; retry++ {
+		delay := retryDelay(retry)
+		assert.True(t, delay >= 0 && delay <= retryBackoff(retry), "delay %v of retry %v out of range", delay, retry)
+	}
+	assert.Equal(t, time.Second, retryBackoff(1))
+	assert.Equal(t, 4*time.Second, retryBackoff(3))
+	assert.Equal(t, maxBackoff, retryBackoff(7))
+	assert.Equal(t, maxBackoff, retryBackoff(100))
+}
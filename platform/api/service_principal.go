@@ -89,8 +89,8 @@ func servicePrincipalLogin(cfg *config.Context) error {
 		Host:   cfg.Server,
 		Path:   "auth/" + cfg.Tenant + "/default/oauth2/token",
 	}
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", url.String(), strings.NewReader("grant_type=client_credentials")) //TODO: urlencode data!
+	client := newHTTPClient()
+	req, err := http.NewRequestWithContext(cancelCtx, "POST", url.String(), strings.NewReader("grant_type=client_credentials")) //TODO: urlencode data!
 	if err != nil {
 		log.Errorf("Failed to create a request %q: %v", url.String(), err.Error())
 		return err
@@ -98,6 +98,7 @@ func servicePrincipalLogin(cfg *config.Context) error {
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
 	req.SetBasicAuth(credentials.ClientID, credentials.Secret)
+	req.Header.Set("User-Agent", userAgent)
 
 	// execute request
 	resp, err := client.Do(req)
@@ -0,0 +1,61 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/apex/log"
+)
+
+var showURLMode bool
+
+// SetShowURLMode enables or disables displaying the method, URL and status of each request
+// made to the platform, at info level but regardless of the log level (i.e., without --verbose).
+// This function should not be used outside of the fsoc root pre-command.
+func SetShowURLMode(enabled bool) {
+	showURLMode = enabled
+}
+
+// showRequestURL displays the request's method and full URL (including the query) with the
+// response status or the error, if the request failed without a response
+func showRequestURL(req *http.Request, resp *http.Response, err error) {
+	if !showURLMode {
+		return
+	}
+	outcome := ""
+	if resp != nil {
+		outcome = resp.Status
+	} else if err != nil {
+		outcome = "failed: " + err.Error()
+	}
+	message := fmt.Sprintf("%v %v -> %v", req.Method, req.URL, outcome)
+
+	// nb: the entry is passed to the handler directly, so that it is displayed at any log level
+	logger, ok := log.Log.(*log.Logger)
+	if !ok || logger.Handler == nil {
+		log.Info(message)
+		return
+	}
+	_ = logger.Handler.HandleLog(&log.Entry{
+		Logger:    logger,
+		Fields:    log.Fields{},
+		Level:     log.InfoLevel,
+		Timestamp: time.Now(),
+		Message:   message,
+	})
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowRequestURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	var entries []*log.Entry
+	savedLog, savedLevel := log.Log, log.Log.(*log.Logger).Level
+	log.Log = &log.Logger{Handler: log.HandlerFunc(func(e *log.Entry) error {
+		entries = append(entries, e)
+		return nil
+	}), Level: log.WarnLevel}
+	SetShowURLMode(true)
+	t.Cleanup(func() {
+		log.Log = savedLog
+		log.SetLevel(savedLevel)
+		SetShowURLMode(false)
+	})
+
+	_, _, _, err := executeRequest(srv.Client(), false, func() (*http.Request, error) {
+		return http.NewRequest("GET", srv.URL+"/objects?filter=x", nil)
+	})
+	require.Nil(t, err)
+
+	// displayed at info level even though the log level is warn
+	require.Len(t, entries, 1)
+	assert.Equal(t, log.InfoLevel, entries[0].Level)
+	assert.Equal(t, "GET "+srv.URL+"/objects?filter=x -> 204 No Content", entries[0].Message)
+}
@@ -41,11 +41,12 @@ func resolveTenant(ctx *config.Context) (string, error) {
 	log.Infof("Looking up tenant ID for %v", ctx.Server)
 
 	// create a GET HTTP request
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", resolverUri, nil)
+	client := newHTTPClient()
+	req, err := http.NewRequestWithContext(cancelCtx, "GET", resolverUri, nil)
 	if err != nil {
 		return "", fmt.Errorf("Failed to create a request %q: %v", resolverUri, err.Error())
 	}
+	req.Header.Set("User-Agent", userAgent)
 
 	// execute request
 	resp, err := client.Do(req)
@@ -0,0 +1,82 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/apex/log"
+)
+
+var traceMode bool
+
+// SetTraceMode enables or disables tracing of the network activity of each request
+// (DNS resolution, connection reuse, TLS handshake and time to first byte), logged at debug level.
+// This function should not be used outside of the fsoc root pre-command.
+func SetTraceMode(enabled bool) {
+	traceMode = enabled
+}
+
+// traceRequest returns the request with a client trace attached that logs the
+// request's network events with the time elapsed since the request started
+func traceRequest(req *http.Request) *http.Request {
+	start := time.Now()
+	event := func(name string) *log.Entry {
+		return log.WithFields(log.Fields{"event": name, "elapsed": time.Since(start).String()})
+	}
+	var dnsStart, connectStart, tlsStart time.Time
+
+	trace := &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			event("get-conn").Debugf("Getting connection to %v", hostPort)
+		},
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+			event("dns-start").Debugf("Resolving %v", info.Host)
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			event("dns-done").Debugf("Resolved to %v in %v (error: %v)", info.Addrs, time.Since(dnsStart), info.Err)
+		},
+		ConnectStart: func(network, addr string) {
+			connectStart = time.Now()
+			event("connect-start").Debugf("Connecting to %v %v", network, addr)
+		},
+		ConnectDone: func(network, addr string, err error) {
+			event("connect-done").Debugf("Connected to %v %v in %v (error: %v)", network, addr, time.Since(connectStart), err)
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+			event("tls-start").Debug("Starting TLS handshake")
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			event("tls-done").Debugf("TLS handshake completed in %v (version %v, resumed: %v, error: %v)",
+				time.Since(tlsStart), tls.VersionName(state.Version), state.DidResume, err)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			event("got-conn").Debugf("Got connection to %v (reused: %v, was idle: %v, idle time: %v)",
+				info.Conn.RemoteAddr(), info.Reused, info.WasIdle, info.IdleTime)
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			event("wrote-request").Debugf("Request sent (error: %v)", info.Err)
+		},
+		GotFirstResponseByte: func() {
+			event("first-byte").Debug("Received first response byte")
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Default connection settings, tuned for commands that send many requests to the same
+// platform, sequentially or concurrently (Go's default keeps only 2 idle connections per host)
+const (
+	DefaultMaxIdleConnsPerHost = 16
+	DefaultMaxConnsPerHost     = 0 // no limit
+	DefaultKeepAlive           = 90 * time.Second
+)
+
+// ConnectionSettings controls the reuse of connections to the platform
+type ConnectionSettings struct {
+	MaxIdleConnsPerHost int           // idle connections kept open for reuse, per host
+	MaxConnsPerHost     int           // connections per host, including active ones; 0 for no limit
+	KeepAlive           time.Duration // how long idle connections are kept open; 0 disables connection reuse
+}
+
+var connectionSettings = ConnectionSettings{
+	MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+	MaxConnsPerHost:     DefaultMaxConnsPerHost,
+	KeepAlive:           DefaultKeepAlive,
+}
+
+var (
+	sharedTransport     *http.Transport
+	sharedTransportOnce sync.Once
+)
+
+// SetConnectionSettings sets how connections to the platform are reused. It must be called
+// before any request is sent.
+// This function should not be used outside of the fsoc root pre-command.
+func SetConnectionSettings(settings ConnectionSettings) {
+	connectionSettings = settings
+}
+
+// GetConnectionSettings returns the settings for reusing connections to the platform
+func GetConnectionSettings() ConnectionSettings {
+	return connectionSettings
+}
+
+// newHTTPClient returns a client for platform requests. All clients share the same
+// transport, so that connections are reused across requests.
+func newHTTPClient() *http.Client {
+	sharedTransportOnce.Do(func() {
+		sharedTransport = newTransport(connectionSettings)
+	})
+	return &http.Client{Transport: sharedTransport}
+}
+
+// newTransport creates a transport with Go's default settings (proxy, timeouts, HTTP/2)
+// adjusted for the connection settings
+func newTransport(settings ConnectionSettings) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = settings.MaxIdleConnsPerHost
+	if transport.MaxIdleConns < settings.MaxIdleConnsPerHost {
+		transport.MaxIdleConns = settings.MaxIdleConnsPerHost
+	}
+	transport.MaxConnsPerHost = settings.MaxConnsPerHost
+	if settings.KeepAlive > 0 {
+		transport.IdleConnTimeout = settings.KeepAlive
+	} else {
+		transport.DisableKeepAlives = true
+	}
+	return transport
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransport(t *testing.T) {
+	transport := newTransport(ConnectionSettings{MaxIdleConnsPerHost: 200, MaxConnsPerHost: 8, KeepAlive: time.Minute})
+	assert.Equal(t, 200, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 200, transport.MaxIdleConns) // raised to allow the per-host idle connections
+	assert.Equal(t, 8, transport.MaxConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.False(t, transport.DisableKeepAlives)
+	assert.NotNil(t, transport.Proxy) // Go's defaults are kept
+
+	// no keep-alive disables connection reuse
+	transport = newTransport(ConnectionSettings{MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost})
+	assert.True(t, transport.DisableKeepAlives)
+	assert.Equal(t, 100, transport.MaxIdleConns) // Go's default
+}
+
+func TestNewHTTPClientSharesTransport(t *testing.T) {
+	assert.True(t, newHTTPClient().Transport == newHTTPClient().Transport)
+}
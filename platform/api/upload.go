@@ -0,0 +1,114 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"sort"
+)
+
+// Upload performs a POST request with a multipart/form-data body containing the given form fields
+// and a file part with the content of the reader, and parses the response as JSON. The body is
+// streamed, so that large files are not buffered in memory. The file name sent in the file part is
+// the base name of the reader's Name(), if it has one (e.g., *os.File), or the file field's name.
+// The request can be retried (see SetMaxRetries) or repeated after a re-login only if the reader
+// is also an io.Seeker, since the file needs to be sent again. The Content-Type header is set
+// by Upload; other headers are taken from the options.
+func Upload(path string, fields map[string]string, fileField string, file io.Reader, out any, options *Options) error {
+	return defaultClient.Upload(path, fields, fileField, file, out, options)
+}
+
+// Upload performs a multipart/form-data POST request, like the package-level Upload
+func (c *Client) Upload(path string, fields map[string]string, fileField string, file io.Reader, out any, options *Options) error {
+	if options == nil {
+		options = &Options{}
+	}
+
+	fileName := fileField
+	if named, ok := file.(interface{ Name() string }); ok {
+		fileName = filepath.Base(named.Name())
+	}
+
+	// use the same boundary for all attempts, so that the Content-Type header matches each body
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	headers := map[string]string{}
+	for k, v := range options.Headers {
+		headers[k] = v
+	}
+	headers["Content-Type"] = "multipart/form-data; boundary=" + boundary
+	uploadOptions := *options // shallow copy
+	uploadOptions.Headers = headers
+
+	attempts := 0
+	newBody := func() (io.Reader, error) {
+		if attempts > 0 {
+			seeker, ok := file.(io.Seeker)
+			if !ok {
+				return nil, errors.New("cannot send the file again: it can be read only once")
+			}
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("cannot send the file again: %w", err)
+			}
+		}
+		attempts++
+		return multipartBody(boundary, fields, fileField, fileName, file), nil
+	}
+
+	err := c.httpRequest("POST", path, newBody, out, &uploadOptions)
+	options.ResponseStatusCode = uploadOptions.ResponseStatusCode
+	options.ResponseHeaders = uploadOptions.ResponseHeaders
+	return err
+}
+
+// multipartBody returns a reader that produces the multipart body as it is read,
+// writing the fields (sorted by name) followed by the file part
+func multipartBody(boundary string, fields map[string]string, fileField string, fileName string, file io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		writer := multipart.NewWriter(pw)
+		pw.CloseWithError(writeMultipart(writer, boundary, fields, fileField, fileName, file))
+	}()
+	return pr
+}
+
+func writeMultipart(writer *multipart.Writer, boundary string, fields map[string]string, fileField string, fileName string, file io.Reader) error {
+	if err := writer.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writer.WriteField(name, fields[name]); err != nil {
+			return err
+		}
+	}
+
+	part, err := writer.CreateFormFile(fileField, fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to read the file to upload: %w", err)
+	}
+	return writer.Close()
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipartBody(t *testing.T) {
+	fields := map[string]string{"stage": "STABLE", "operation": "UPLOAD"}
+	body := multipartBody("test-boundary", fields, "file", "bundle.zip", strings.NewReader("archive content"))
+	defer body.Close()
+
+	reader := multipart.NewReader(body, "test-boundary")
+	var names []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		names = append(names, part.FormName())
+		switch part.FormName() {
+		case "file":
+			assert.Equal(t, "bundle.zip", part.FileName())
+			assert.Equal(t, "archive content", string(content))
+		default:
+			assert.Equal(t, fields[part.FormName()], string(content))
+		}
+	}
+	assert.Equal(t, []string{"operation", "stage", "file"}, names)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk error")
+}
+
+func TestMultipartBodyReadError(t *testing.T) {
+	body := multipartBody("test-boundary", nil, "file", "bundle.zip", failingReader{})
+	defer body.Close()
+
+	_, err := io.ReadAll(body)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disk error")
+}
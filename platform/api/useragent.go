@@ -0,0 +1,35 @@
+// Copyright 2022 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// userAgent is the User-Agent header value sent with all platform requests
+var userAgent = DefaultUserAgent("unknown")
+
+// DefaultUserAgent returns the standard fsoc User-Agent value for the given fsoc version,
+// in the form fsoc/<version> (<os>/<arch>)
+func DefaultUserAgent(version string) string {
+	return fmt.Sprintf("fsoc/%v (%v/%v)", version, runtime.GOOS, runtime.GOARCH)
+}
+
+// SetUserAgent sets the User-Agent header value sent with all platform requests.
+// This function should not be used outside of the fsoc root pre-command.
+func SetUserAgent(value string) {
+	userAgent = value
+}